@@ -0,0 +1,116 @@
+// Package agent selects the coding agent backend that runs workflow prompts.
+//
+// Every backend implements [claude.Executor] and reports what the agent does
+// as [claude.Event] values, so the workflow runner and lifecycle work the
+// same whichever agent runs the prompt. The backend is chosen by the
+// agent.backend config key.
+//
+// Built-in backends:
+//   - "claude" runs the Claude CLI (see [claude.DefaultExecutor])
+//   - "command" runs any agent CLI that writes JSON lines, mapped to events
+//     by agent.command.events (see [CommandExecutor] and [MappingParser])
+//
+// Further backends are added with [Register].
+package agent
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"bmaduum/internal/claude"
+	"bmaduum/internal/config"
+)
+
+// Factory creates the executor of a backend from the configuration. stderr
+// is called for each line the agent writes to stderr; it may be nil.
+type Factory func(cfg *config.Config, stderr func(line string)) (claude.Executor, error)
+
+var (
+	mu       sync.RWMutex
+	backends = map[string]Factory{
+		config.AgentBackendClaude:  newClaudeExecutor,
+		config.AgentBackendCommand: newCommandExecutor,
+	}
+)
+
+// Register makes a backend available under name, for selection with
+// agent.backend. It panics if name is empty, factory is nil or a backend
+// is already registered under name.
+func Register(name string, factory Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if name == "" || factory == nil {
+		panic("agent: Register needs a name and a factory")
+	}
+	if _, ok := backends[name]; ok {
+		panic(fmt.Sprintf("agent: backend %q is already registered", name))
+	}
+	backends[name] = factory
+}
+
+// Backends returns the names of the registered backends, sorted.
+func Backends() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New creates the executor of the backend selected by cfg.Agent.Backend,
+// or of the claude backend when none is selected.
+func New(cfg *config.Config, stderr func(line string)) (claude.Executor, error) {
+	name := cfg.Agent.Backend
+	if name == "" {
+		name = config.AgentBackendClaude
+	}
+
+	mu.RLock()
+	factory, ok := backends[name]
+	mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown agent backend %q (available: %s)", name, strings.Join(Backends(), ", "))
+	}
+
+	executor, err := factory(cfg, stderr)
+	if err != nil {
+		return nil, fmt.Errorf("agent backend %s: %w", name, err)
+	}
+	return executor, nil
+}
+
+// newClaudeExecutor creates the Claude CLI executor from cfg.Claude.
+func newClaudeExecutor(cfg *config.Config, stderr func(line string)) (claude.Executor, error) {
+	return claude.NewExecutor(claude.ExecutorConfig{
+		BinaryPath:     cfg.Claude.BinaryPath,
+		OutputFormat:   cfg.Claude.OutputFormat,
+		PromptStdin:    cfg.Claude.PromptStdin,
+		StdinThreshold: cfg.Claude.StdinThreshold,
+		StderrHandler:  stderr,
+	}), nil
+}
+
+// newCommandExecutor creates the command executor from cfg.Agent.Command.
+func newCommandExecutor(cfg *config.Config, stderr func(line string)) (claude.Executor, error) {
+	return NewCommandExecutor(cfg.Agent.Command, stderr), nil
+}
+
+// Binary returns the agent binary of the selected backend and the setting
+// that configures it, for checking that it can be found. Both are empty for
+// backends without a binary setting.
+func Binary(cfg *config.Config) (path, setting string) {
+	switch cfg.Agent.Backend {
+	case "", config.AgentBackendClaude:
+		return cfg.Claude.BinaryPath, "claude.binary_path"
+	case config.AgentBackendCommand:
+		return cfg.Agent.Command.Path, "agent.command.path"
+	}
+	return "", ""
+}
@@ -0,0 +1,95 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bmaduum/internal/claude"
+	"bmaduum/internal/config"
+)
+
+func TestNew(t *testing.T) {
+	cfg := config.DefaultConfig()
+
+	executor, err := New(cfg, nil)
+	require.NoError(t, err)
+	assert.IsType(t, &claude.DefaultExecutor{}, executor)
+
+	cfg.Agent.Backend = ""
+	executor, err = New(cfg, nil)
+	require.NoError(t, err)
+	assert.IsType(t, &claude.DefaultExecutor{}, executor, "claude is the default backend")
+
+	cfg.Agent.Backend = config.AgentBackendCommand
+	executor, err = New(cfg, nil)
+	require.NoError(t, err)
+	assert.IsType(t, &CommandExecutor{}, executor)
+}
+
+func TestNew_UnknownBackend(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Agent.Backend = "gpt"
+
+	_, err := New(cfg, nil)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown agent backend "gpt" (available: `)
+	assert.Contains(t, err.Error(), "claude, command")
+}
+
+func TestRegister(t *testing.T) {
+	mock := &claude.MockExecutor{ExitCode: 0}
+	Register("test-stub", func(cfg *config.Config, stderr func(string)) (claude.Executor, error) {
+		return mock, nil
+	})
+	Register("test-broken", func(cfg *config.Config, stderr func(string)) (claude.Executor, error) {
+		return nil, errors.New("no license")
+	})
+	t.Cleanup(func() {
+		mu.Lock()
+		defer mu.Unlock()
+		delete(backends, "test-stub")
+		delete(backends, "test-broken")
+	})
+
+	assert.Equal(t, []string{"claude", "command", "test-broken", "test-stub"}, Backends())
+
+	cfg := config.DefaultConfig()
+	cfg.Agent.Backend = "test-stub"
+	executor, err := New(cfg, nil)
+	require.NoError(t, err)
+	_, err = executor.ExecuteWithResult(context.Background(), "prompt", nil, claude.Options{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"prompt"}, mock.RecordedPrompts)
+
+	cfg.Agent.Backend = "test-broken"
+	_, err = New(cfg, nil)
+	assert.EqualError(t, err, "agent backend test-broken: no license")
+
+	assert.Panics(t, func() { Register("claude", newClaudeExecutor) })
+	assert.Panics(t, func() { Register("empty", nil) })
+}
+
+func TestBinary(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Claude.BinaryPath = "/opt/claude"
+	cfg.Agent.Command.Path = "my-agent"
+
+	path, setting := Binary(cfg)
+	assert.Equal(t, "/opt/claude", path)
+	assert.Equal(t, "claude.binary_path", setting)
+
+	cfg.Agent.Backend = config.AgentBackendCommand
+	path, setting = Binary(cfg)
+	assert.Equal(t, "my-agent", path)
+	assert.Equal(t, "agent.command.path", setting)
+
+	cfg.Agent.Backend = "custom"
+	path, setting = Binary(cfg)
+	assert.Empty(t, path)
+	assert.Empty(t, setting)
+}
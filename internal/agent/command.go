@@ -0,0 +1,154 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"bmaduum/internal/claude"
+	"bmaduum/internal/config"
+)
+
+// CommandExecutor implements [claude.Executor] for any agent CLI that writes
+// one JSON object per line to stdout, such as another local coding agent or
+// a stub for testing workflows.
+//
+// The agent is run as the configured path and args, with the prompt on stdin
+// or as the last argument. Its output lines are mapped to events by a
+// [MappingParser] built from the configured event mappings.
+//
+// Of the [claude.Options], only the model (when a model flag is configured)
+// and the extra args are passed on; the Claude-specific options are ignored.
+//
+// Create instances using [NewCommandExecutor].
+type CommandExecutor struct {
+	config config.CommandAgentConfig
+	parser claude.Parser
+	stderr func(line string)
+}
+
+// NewCommandExecutor creates a [CommandExecutor] for the configured agent.
+// stderr is called for each line the agent writes to stderr; if nil,
+// stderr is discarded. The prompt goes to stdin unless cfg.Prompt is
+// [config.AgentPromptArg].
+func NewCommandExecutor(cfg config.CommandAgentConfig, stderr func(line string)) *CommandExecutor {
+	if cfg.Prompt == "" {
+		cfg.Prompt = config.AgentPromptStdin
+	}
+	return &CommandExecutor{
+		config: cfg,
+		parser: NewMappingParser(cfg.Events),
+		stderr: stderr,
+	}
+}
+
+// Execute runs the agent with the given prompt and returns a channel of
+// events. The channel is closed once the agent has exited or the context is
+// canceled; the exit status is not available.
+func (e *CommandExecutor) Execute(ctx context.Context, prompt string) (<-chan claude.Event, error) {
+	cmd, events, stderrDone, err := e.start(ctx, prompt, claude.Options{})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan claude.Event)
+	go func() {
+		defer close(out)
+		for event := range events {
+			select {
+			case out <- event:
+			case <-ctx.Done():
+			}
+		}
+		<-stderrDone
+		_ = cmd.Wait() //nolint:errcheck // Exit status intentionally ignored; use ExecuteWithResult if needed
+	}()
+
+	return out, nil
+}
+
+// ExecuteWithResult runs the agent with the given prompt, calls the handler
+// for each event and returns the agent's exit code once it has exited.
+// Events that arrive after the context is canceled are dropped.
+func (e *CommandExecutor) ExecuteWithResult(ctx context.Context, prompt string, handler claude.EventHandler, opts claude.Options) (int, error) {
+	cmd, events, stderrDone, err := e.start(ctx, prompt, opts)
+	if err != nil {
+		return 1, err
+	}
+
+	// Drain the events so the parser finishes once the agent is killed
+	for event := range events {
+		if handler != nil && ctx.Err() == nil {
+			handler(event)
+		}
+	}
+	<-stderrDone
+
+	if err := cmd.Wait(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode(), nil
+		}
+		return 1, err
+	}
+	return 0, nil
+}
+
+// start starts the agent and returns its parsed events and a channel that
+// is closed once its stderr has been read.
+func (e *CommandExecutor) start(ctx context.Context, prompt string, opts claude.Options) (*exec.Cmd, <-chan claude.Event, <-chan struct{}, error) {
+	cmd := exec.CommandContext(ctx, e.config.Path, e.args(prompt, opts)...)
+	if e.config.Prompt != config.AgentPromptArg {
+		cmd.Stdin = strings.NewReader(prompt)
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to create stderr pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to start %s: %w", e.config.Path, err)
+	}
+
+	stderrDone := make(chan struct{})
+	go func() {
+		defer close(stderrDone)
+		e.handleStderr(stderr)
+	}()
+
+	return cmd, e.parser.Parse(stdout), stderrDone, nil
+}
+
+// args returns the agent's arguments: the configured args, the model flag,
+// the extra args and, when passed as an argument, the prompt.
+func (e *CommandExecutor) args(prompt string, opts claude.Options) []string {
+	args := append([]string{}, e.config.Args...)
+	if e.config.ModelFlag != "" && opts.Model != "" {
+		args = append(args, e.config.ModelFlag, opts.Model)
+	}
+	args = append(args, opts.ExtraArgs...)
+	if e.config.Prompt == config.AgentPromptArg {
+		args = append(args, prompt)
+	}
+	return args
+}
+
+func (e *CommandExecutor) handleStderr(stderr io.Reader) {
+	if e.stderr == nil {
+		_, _ = io.Copy(io.Discard, stderr) //nolint:errcheck // Intentionally discarding stderr
+		return
+	}
+
+	scanner := bufio.NewScanner(stderr)
+	for scanner.Scan() {
+		e.stderr(scanner.Text())
+	}
+}
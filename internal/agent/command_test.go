@@ -0,0 +1,145 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bmaduum/internal/claude"
+	"bmaduum/internal/config"
+)
+
+// fakeAgent is a stand-in agent CLI: a shell script that records its
+// arguments and stdin, prints a short JSONL session in the format of
+// testRules and a line to stderr, and exits with the given code.
+type fakeAgent struct {
+	path string
+	dir  string
+}
+
+func newFakeAgent(t *testing.T, exitCode int) *fakeAgent {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake agent binary is a shell script")
+	}
+
+	dir := t.TempDir()
+	script := `#!/bin/sh
+for arg in "$@"; do printf '%s\0' "$arg"; done > "` + dir + `/args"
+cat > "` + dir + `/stdin"
+echo 'warming up' >&2
+echo '{"type":"thread.started","thread_id":"t-1"}'
+echo 'not json'
+echo '{"type":"item.completed","item":{"type":"agent_message","text":"Done."}}'
+echo '{"type":"turn.completed","usage":{"input_tokens":10,"output_tokens":5}}'
+exit ` + strconv.Itoa(exitCode) + "\n"
+
+	path := filepath.Join(dir, "agent")
+	require.NoError(t, os.WriteFile(path, []byte(script), 0755))
+	return &fakeAgent{path: path, dir: dir}
+}
+
+// args returns the arguments of the last run.
+func (f *fakeAgent) args(t *testing.T) []string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(f.dir, "args"))
+	require.NoError(t, err)
+	if len(data) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\x00"), "\x00")
+}
+
+// stdin returns what the last run read from stdin.
+func (f *fakeAgent) stdin(t *testing.T) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(f.dir, "stdin"))
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestCommandExecutor_ExecuteWithResult(t *testing.T) {
+	fake := newFakeAgent(t, 0)
+	var stderr []string
+	executor := NewCommandExecutor(config.CommandAgentConfig{
+		Path:      fake.path,
+		Args:      []string{"exec", "--json"},
+		ModelFlag: "-m",
+		Events:    testRules,
+	}, func(line string) { stderr = append(stderr, line) })
+
+	var events []claude.Event
+	exitCode, err := executor.ExecuteWithResult(context.Background(), "Work on story 1-1", func(event claude.Event) {
+		events = append(events, event)
+	}, claude.Options{Model: "large", PermissionMode: "plan", ExtraArgs: []string{"--sandbox"}})
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, exitCode)
+	require.Len(t, events, 3)
+	assert.True(t, events[0].SessionStarted)
+	assert.Equal(t, "Done.", events[1].Text)
+	assert.True(t, events[2].SessionComplete)
+	assert.Equal(t, 10, events[2].InputTokens)
+	assert.Equal(t, []string{"warming up"}, stderr)
+
+	assert.Equal(t, []string{"exec", "--json", "-m", "large", "--sandbox"}, fake.args(t))
+	assert.Equal(t, "Work on story 1-1", fake.stdin(t))
+}
+
+func TestCommandExecutor_PromptArg(t *testing.T) {
+	fake := newFakeAgent(t, 0)
+	executor := NewCommandExecutor(config.CommandAgentConfig{
+		Path:   fake.path,
+		Prompt: config.AgentPromptArg,
+		Events: testRules,
+	}, nil)
+
+	exitCode, err := executor.ExecuteWithResult(context.Background(), "Work on story 1-1", nil, claude.Options{Model: "large"})
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, exitCode)
+	assert.Equal(t, []string{"Work on story 1-1"}, fake.args(t), "the model is only passed with a model flag")
+	assert.Empty(t, fake.stdin(t))
+}
+
+func TestCommandExecutor_ExitCode(t *testing.T) {
+	fake := newFakeAgent(t, 4)
+	executor := NewCommandExecutor(config.CommandAgentConfig{Path: fake.path, Events: testRules}, nil)
+
+	exitCode, err := executor.ExecuteWithResult(context.Background(), "prompt", nil, claude.Options{})
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, exitCode)
+}
+
+func TestCommandExecutor_NotFound(t *testing.T) {
+	executor := NewCommandExecutor(config.CommandAgentConfig{Path: filepath.Join(t.TempDir(), "missing")}, nil)
+
+	exitCode, err := executor.ExecuteWithResult(context.Background(), "prompt", nil, claude.Options{})
+
+	require.Error(t, err)
+	assert.Equal(t, 1, exitCode)
+	assert.Contains(t, err.Error(), "failed to start")
+}
+
+func TestCommandExecutor_Execute(t *testing.T) {
+	fake := newFakeAgent(t, 0)
+	executor := NewCommandExecutor(config.CommandAgentConfig{Path: fake.path, Events: testRules}, nil)
+
+	events, err := executor.Execute(context.Background(), "prompt")
+	require.NoError(t, err)
+
+	var count int
+	for range events {
+		count++
+	}
+	assert.Equal(t, 3, count)
+	assert.Equal(t, "prompt", fake.stdin(t), "the channel is closed after the agent exits")
+}
@@ -0,0 +1,209 @@
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"bmaduum/internal/claude"
+	"bmaduum/internal/config"
+)
+
+// MappingParser implements [claude.Parser] for agents that write one JSON
+// object per line, turning lines into events with declarative
+// [config.EventMapping] rules.
+//
+// Each line is checked against the rules in order and becomes the event of
+// the first rule whose conditions it meets. Lines that are not JSON objects
+// or match no rule are skipped, like malformed lines in [claude.DefaultParser].
+//
+// Create instances using [NewMappingParser].
+type MappingParser struct {
+	rules []config.EventMapping
+
+	// BufferSize is the maximum size in bytes for a single line.
+	// Defaults to 10MB.
+	BufferSize int
+}
+
+// NewMappingParser creates a [MappingParser] with the given rules.
+func NewMappingParser(rules []config.EventMapping) *MappingParser {
+	return &MappingParser{
+		rules:      rules,
+		BufferSize: 10 * 1024 * 1024, // 10MB
+	}
+}
+
+// Parse reads lines from the reader and emits the events they map to. The
+// channel is closed when the reader is exhausted or a line is too long.
+func (p *MappingParser) Parse(reader io.Reader) <-chan claude.Event {
+	events := make(chan claude.Event)
+
+	go func() {
+		defer close(events)
+
+		scanner := bufio.NewScanner(reader)
+		bufSize := p.BufferSize
+		if bufSize <= 0 {
+			bufSize = 10 * 1024 * 1024
+		}
+		scanner.Buffer(make([]byte, 0, 64*1024), bufSize)
+
+		for scanner.Scan() {
+			if event, ok := p.ParseLine(scanner.Text()); ok {
+				events <- event
+			}
+		}
+	}()
+
+	return events
+}
+
+// ParseLine maps a single line to an event. It returns false if the line is
+// not a JSON object or matches no rule.
+func (p *MappingParser) ParseLine(line string) (claude.Event, bool) {
+	decoder := json.NewDecoder(strings.NewReader(line))
+	decoder.UseNumber()
+	var obj map[string]any
+	if err := decoder.Decode(&obj); err != nil || obj == nil {
+		return claude.Event{}, false
+	}
+
+	for _, rule := range p.rules {
+		if matches(obj, rule.Match) {
+			return newEvent(obj, rule), true
+		}
+	}
+	return claude.Event{}, false
+}
+
+// matches reports whether obj meets every condition.
+func matches(obj map[string]any, conditions []config.FieldMatch) bool {
+	for _, c := range conditions {
+		value, ok := lookup(obj, c.Field)
+		if !ok || stringify(value) != c.Equals {
+			return false
+		}
+	}
+	return true
+}
+
+// newEvent builds the event of the given kind from the fields of obj.
+func newEvent(obj map[string]any, rule config.EventMapping) claude.Event {
+	var e claude.Event
+	switch rule.Kind {
+	case config.EventKindSessionStart:
+		e.Type = claude.EventTypeSystem
+		e.Subtype = claude.SubtypeInit
+		e.SessionStarted = true
+	case config.EventKindText, config.EventKindToolUse:
+		e.Type = claude.EventTypeAssistant
+	case config.EventKindToolResult:
+		e.Type = claude.EventTypeUser
+		e.HasToolResult = true
+	case config.EventKindResult:
+		e.Type = claude.EventTypeResult
+		e.SessionComplete = true
+	}
+
+	for field, path := range rule.Fields {
+		value, ok := lookup(obj, path)
+		if !ok {
+			continue
+		}
+		setField(&e, field, stringify(value))
+	}
+
+	e.Raw = &claude.StreamEvent{
+		Type:      string(e.Type),
+		Subtype:   e.Subtype,
+		SessionID: e.SessionID,
+		Result:    e.Result,
+		IsError:   e.IsError,
+	}
+	return e
+}
+
+// setField sets one of the [config.EventFields] of the event.
+func setField(e *claude.Event, field, value string) {
+	switch field {
+	case "text":
+		e.Text = value
+	case "tool_id":
+		e.ToolID = value
+	case "tool_use_id":
+		e.ToolUseID = value
+	case "tool_name":
+		e.ToolName = value
+	case "tool_description":
+		e.ToolDescription = value
+	case "tool_command":
+		e.ToolCommand = value
+	case "tool_file_path":
+		e.ToolFilePath = value
+	case "tool_stdout":
+		e.ToolStdout = value
+	case "tool_stderr":
+		e.ToolStderr = value
+	case "session_id":
+		e.SessionID = value
+	case "result":
+		e.Result = value
+	case "is_error":
+		// Set by true, or by an error message
+		e.IsError = !slices.Contains([]string{"", "false", "0"}, value)
+	case "input_tokens":
+		e.InputTokens, _ = strconv.Atoi(value)
+	case "output_tokens":
+		e.OutputTokens, _ = strconv.Atoi(value)
+	}
+}
+
+// lookup returns the value at a dotted path in obj, where numeric path
+// elements index arrays.
+func lookup(obj map[string]any, path string) (any, bool) {
+	var value any = obj
+	for _, key := range strings.Split(path, ".") {
+		switch v := value.(type) {
+		case map[string]any:
+			next, ok := v[key]
+			if !ok {
+				return nil, false
+			}
+			value = next
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			value = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// stringify returns a field value as text: strings as they are, null as an
+// empty string and anything else in its JSON form.
+func stringify(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(value) //nolint:errcheck // Decoded JSON always encodes
+	return strings.TrimSuffix(buf.String(), "\n")
+}
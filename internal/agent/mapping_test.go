@@ -0,0 +1,181 @@
+package agent
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bmaduum/internal/claude"
+	"bmaduum/internal/config"
+)
+
+// testRules map a JSONL format similar to common agent CLIs.
+var testRules = []config.EventMapping{
+	{
+		Match:  []config.FieldMatch{{Field: "type", Equals: "thread.started"}},
+		Kind:   config.EventKindSessionStart,
+		Fields: map[string]string{"session_id": "thread_id"},
+	},
+	{
+		Match:  []config.FieldMatch{{Field: "type", Equals: "item.completed"}, {Field: "item.type", Equals: "agent_message"}},
+		Kind:   config.EventKindText,
+		Fields: map[string]string{"text": "item.text"},
+	},
+	{
+		Match:  []config.FieldMatch{{Field: "type", Equals: "item.started"}, {Field: "item.type", Equals: "command_execution"}},
+		Kind:   config.EventKindToolUse,
+		Fields: map[string]string{"tool_id": "item.id", "tool_name": "item.type", "tool_command": "item.command"},
+	},
+	{
+		Match:  []config.FieldMatch{{Field: "type", Equals: "item.completed"}, {Field: "item.type", Equals: "command_execution"}},
+		Kind:   config.EventKindToolResult,
+		Fields: map[string]string{"tool_use_id": "item.id", "tool_stdout": "item.aggregated_output"},
+	},
+	{
+		Match:  []config.FieldMatch{{Field: "type", Equals: "turn.completed"}},
+		Kind:   config.EventKindResult,
+		Fields: map[string]string{"input_tokens": "usage.input_tokens", "output_tokens": "usage.output_tokens"},
+	},
+	{
+		Match:  []config.FieldMatch{{Field: "type", Equals: "turn.failed"}},
+		Kind:   config.EventKindResult,
+		Fields: map[string]string{"result": "error.message", "is_error": "error.message"},
+	},
+}
+
+func TestMappingParser_ParseLine(t *testing.T) {
+	parser := NewMappingParser(testRules)
+
+	tests := []struct {
+		name  string
+		line  string
+		check func(t *testing.T, e claude.Event)
+	}{
+		{
+			name: "session start",
+			line: `{"type":"thread.started","thread_id":"t-1"}`,
+			check: func(t *testing.T, e claude.Event) {
+				assert.True(t, e.SessionStarted)
+				assert.Equal(t, claude.EventTypeSystem, e.Type)
+				assert.Equal(t, "t-1", e.SessionID)
+			},
+		},
+		{
+			name: "text",
+			line: `{"type":"item.completed","item":{"id":"i-1","type":"agent_message","text":"Done."}}`,
+			check: func(t *testing.T, e claude.Event) {
+				assert.True(t, e.IsText())
+				assert.Equal(t, "Done.", e.Text)
+			},
+		},
+		{
+			name: "tool use",
+			line: `{"type":"item.started","item":{"id":"i-2","type":"command_execution","command":"go test ./..."}}`,
+			check: func(t *testing.T, e claude.Event) {
+				assert.True(t, e.IsToolUse())
+				assert.Equal(t, "i-2", e.ToolID)
+				assert.Equal(t, "command_execution", e.ToolName)
+				assert.Equal(t, "go test ./...", e.ToolCommand)
+			},
+		},
+		{
+			name: "tool result",
+			line: `{"type":"item.completed","item":{"id":"i-2","type":"command_execution","aggregated_output":"ok"}}`,
+			check: func(t *testing.T, e claude.Event) {
+				assert.True(t, e.IsToolResult())
+				assert.Equal(t, "i-2", e.ToolUseID)
+				assert.Equal(t, "ok", e.ToolStdout)
+			},
+		},
+		{
+			name: "result with numbers",
+			line: `{"type":"turn.completed","usage":{"input_tokens":1200,"output_tokens":34}}`,
+			check: func(t *testing.T, e claude.Event) {
+				assert.True(t, e.SessionComplete)
+				assert.False(t, e.IsError)
+				assert.Equal(t, 1200, e.InputTokens)
+				assert.Equal(t, 34, e.OutputTokens)
+			},
+		},
+		{
+			name: "error result",
+			line: `{"type":"turn.failed","error":{"message":"usage limit reached"}}`,
+			check: func(t *testing.T, e claude.Event) {
+				assert.True(t, e.SessionComplete)
+				assert.True(t, e.IsError)
+				assert.Equal(t, "usage limit reached", e.Result)
+				require.NotNil(t, e.Raw)
+				assert.True(t, e.Raw.IsError)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, ok := parser.ParseLine(tt.line)
+			require.True(t, ok)
+			tt.check(t, event)
+		})
+	}
+}
+
+func TestMappingParser_ParseLine_Skipped(t *testing.T) {
+	parser := NewMappingParser(testRules)
+
+	for _, line := range []string{
+		"",
+		"Loading model...",
+		`["not", "an", "object"]`,
+		`null`,
+		`{"type":"item.completed","item":{"type":"reasoning","text":"hmm"}}`,
+	} {
+		_, ok := parser.ParseLine(line)
+		assert.False(t, ok, line)
+	}
+}
+
+func TestMappingParser_FieldPaths(t *testing.T) {
+	parser := NewMappingParser([]config.EventMapping{
+		{
+			Match:  []config.FieldMatch{{Field: "done", Equals: "true"}, {Field: "code", Equals: "0"}},
+			Kind:   config.EventKindResult,
+			Fields: map[string]string{"result": "parts.1.text", "is_error": "failed", "session_id": "meta"},
+		},
+		{Kind: config.EventKindText, Fields: map[string]string{"text": "missing.path"}},
+	})
+
+	event, ok := parser.ParseLine(`{"done":true,"code":0,"failed":false,"parts":[{"text":"a"},{"text":"b"}],"meta":{"id":"x"}}`)
+	require.True(t, ok)
+	assert.Equal(t, claude.EventTypeResult, event.Type)
+	assert.Equal(t, "b", event.Result)
+	assert.False(t, event.IsError)
+	assert.Equal(t, `{"id":"x"}`, event.SessionID, "objects are mapped in their JSON form")
+
+	// A rule without conditions matches every object; missing paths are left empty
+	event, ok = parser.ParseLine(`{"done":false}`)
+	require.True(t, ok)
+	assert.Equal(t, claude.EventTypeAssistant, event.Type)
+	assert.Empty(t, event.Text)
+}
+
+func TestMappingParser_Parse(t *testing.T) {
+	parser := NewMappingParser(testRules)
+	input := strings.Join([]string{
+		`{"type":"thread.started","thread_id":"t-1"}`,
+		`not json`,
+		`{"type":"item.completed","item":{"type":"agent_message","text":"Done."}}`,
+		`{"type":"turn.completed","usage":{"input_tokens":1,"output_tokens":2}}`,
+	}, "\n")
+
+	var events []claude.Event
+	for event := range parser.Parse(strings.NewReader(input)) {
+		events = append(events, event)
+	}
+
+	require.Len(t, events, 3)
+	assert.True(t, events[0].SessionStarted)
+	assert.Equal(t, "Done.", events[1].Text)
+	assert.True(t, events[2].SessionComplete)
+}
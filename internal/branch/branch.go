@@ -0,0 +1,143 @@
+// Package branch moves each story onto its own git branch.
+//
+// When git.branch_per_story is enabled, [Manager] checks out a branch named
+// from git.branch_template before a story starts, creating it from the base
+// branch if needed, and returns to the base branch once the story is done
+// unless git.return_to_base is false. [Manager] implements
+// [lifecycle.StoryBrancher].
+//
+// The sprint status file is the run's source of truth and is usually tracked
+// in git, so its content and its history log are carried across every switch
+// (see [worktree.Preserve]) instead of being reset to the version on the
+// target branch.
+package branch
+
+import (
+	"errors"
+	"fmt"
+
+	"bmaduum/internal/config"
+	"bmaduum/internal/git"
+	"bmaduum/internal/output/core"
+	"bmaduum/internal/status"
+	"bmaduum/internal/worktree"
+)
+
+// Manager checks out story branches.
+//
+// Use [NewManager] to create an instance.
+type Manager struct {
+	config     *config.Config
+	printer    core.Printer
+	dir        string
+	statusPath string
+
+	// base is the resolved base branch, set on first use.
+	base string
+}
+
+// NewManager creates a [Manager] for the project in the current working
+// directory, using the git settings from cfg. The sprint status file at
+// statusPath and its history log are carried across branch switches.
+func NewManager(cfg *config.Config, printer core.Printer, statusPath string) *Manager {
+	return &Manager{
+		config:     cfg,
+		printer:    printer,
+		statusPath: statusPath,
+	}
+}
+
+// Start checks out the branch for storyKey, creating it from the base branch
+// if it does not exist yet. An existing branch is reused so a resumed story
+// continues where it stopped.
+func (m *Manager) Start(storyKey string) error {
+	base, err := m.baseBranch()
+	if err != nil {
+		return err
+	}
+
+	_, epicID, _ := status.ParseKey(storyKey)
+	name, err := m.config.GetBranchName(config.PromptData{StoryKey: storyKey, EpicID: epicID})
+	if err != nil {
+		return err
+	}
+
+	current, err := git.CurrentBranch(m.dir)
+	if err != nil {
+		return err
+	}
+	if current == name {
+		m.printer.Text(fmt.Sprintf("Continuing on story branch %s", name))
+		return nil
+	}
+
+	exists, err := git.BranchExists(m.dir, name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		if err := m.switchTo(func() error { return git.Checkout(m.dir, name) }); err != nil {
+			return err
+		}
+		m.printer.Text(fmt.Sprintf("Switched to existing story branch %s", name))
+		return nil
+	}
+
+	if err := m.switchTo(func() error { return git.CreateBranch(m.dir, name, base) }); err != nil {
+		return err
+	}
+	m.printer.Text(fmt.Sprintf("Created story branch %s from %s", name, base))
+	return nil
+}
+
+// Finish switches back to the base branch after storyKey is done, or leaves
+// the story branch checked out when git.return_to_base is false.
+func (m *Manager) Finish(storyKey string) error {
+	current, err := git.CurrentBranch(m.dir)
+	if err != nil {
+		return err
+	}
+
+	if !m.config.Git.ReturnToBase {
+		m.printer.Text(fmt.Sprintf("Leaving story branch %s checked out for review", current))
+		return nil
+	}
+
+	if current == m.base {
+		return nil
+	}
+	if err := m.switchTo(func() error { return git.Checkout(m.dir, m.base) }); err != nil {
+		return err
+	}
+	m.printer.Text(fmt.Sprintf("Returned to %s", m.base))
+	return nil
+}
+
+// baseBranch returns the branch stories start from: git.base_branch, or the
+// branch checked out when the manager was first used.
+func (m *Manager) baseBranch() (string, error) {
+	if m.base != "" {
+		return m.base, nil
+	}
+
+	base := m.config.Git.BaseBranch
+	if base == "" {
+		current, err := git.CurrentBranch(m.dir)
+		if err != nil {
+			return "", err
+		}
+		if current == "" {
+			return "", errors.New("HEAD is detached; set git.base_branch to the branch stories start from")
+		}
+		base = current
+	}
+
+	m.base = base
+	return base, nil
+}
+
+// switchTo runs switchFn with the sprint status file and its history log
+// carried across.
+func (m *Manager) switchTo(switchFn func() error) error {
+	return worktree.Preserve(m.dir, m.statusPath, switchFn)
+}
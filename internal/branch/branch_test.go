@@ -0,0 +1,149 @@
+package branch
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bmaduum/internal/config"
+	"bmaduum/internal/output"
+)
+
+const statusFile = "sprint-status.yaml"
+
+// initRepo creates a git repository on branch "main" with a committed
+// sprint status file.
+func initRepo(t *testing.T) string {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	gitCmd(t, dir, "init", "-q")
+	gitCmd(t, dir, "checkout", "-q", "-b", "main")
+	gitCmd(t, dir, "config", "user.email", "test@example.com")
+	gitCmd(t, dir, "config", "user.name", "Test")
+	writeFile(t, filepath.Join(dir, statusFile), "6-1-first: ready-for-dev\n")
+	gitCmd(t, dir, "add", "-A")
+	gitCmd(t, dir, "commit", "-q", "-m", "initial")
+
+	return dir
+}
+
+// gitCmd runs git in dir and returns its trimmed output, failing the test on error.
+func gitCmd(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	return strings.TrimSpace(string(out))
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func newTestManager(dir string, cfg *config.Config) (*Manager, *bytes.Buffer) {
+	buf := &bytes.Buffer{}
+	m := NewManager(cfg, output.NewPrinterWithWriter(buf), filepath.Join(dir, statusFile))
+	m.dir = dir
+	return m, buf
+}
+
+func TestManager_StartAndFinish(t *testing.T) {
+	dir := initRepo(t)
+	m, buf := newTestManager(dir, config.DefaultConfig())
+
+	require.NoError(t, m.Start("6-1-first"))
+	assert.Equal(t, "story/6-1-first", gitCmd(t, dir, "branch", "--show-current"))
+	assert.Contains(t, buf.String(), "Created story branch story/6-1-first from main")
+
+	// The story's status changes survive the switch back
+	writeFile(t, filepath.Join(dir, statusFile), "6-1-first: done\n")
+
+	require.NoError(t, m.Finish("6-1-first"))
+	assert.Equal(t, "main", gitCmd(t, dir, "branch", "--show-current"))
+	assert.Equal(t, "6-1-first: done\n", readFile(t, filepath.Join(dir, statusFile)))
+	assert.Contains(t, buf.String(), "Returned to main")
+}
+
+func TestManager_StartReusesExistingBranch(t *testing.T) {
+	dir := initRepo(t)
+	gitCmd(t, dir, "branch", "story/6-1-first")
+	m, buf := newTestManager(dir, config.DefaultConfig())
+
+	require.NoError(t, m.Start("6-1-first"))
+	assert.Equal(t, "story/6-1-first", gitCmd(t, dir, "branch", "--show-current"))
+	assert.Contains(t, buf.String(), "Switched to existing story branch story/6-1-first")
+
+	require.NoError(t, m.Start("6-1-first"))
+	assert.Contains(t, buf.String(), "Continuing on story branch story/6-1-first")
+}
+
+func TestManager_CarriesStatusFiles(t *testing.T) {
+	dir := initRepo(t)
+	statusPath := filepath.Join(dir, statusFile)
+	historyPath := filepath.Join(dir, "sprint-status.history.jsonl")
+
+	// The story branch has an older committed status
+	gitCmd(t, dir, "checkout", "-q", "-b", "story/6-1-first")
+	writeFile(t, statusPath, "6-1-first: backlog\n")
+	gitCmd(t, dir, "commit", "-q", "-am", "old status")
+	gitCmd(t, dir, "checkout", "-q", "main")
+
+	writeFile(t, statusPath, "6-1-first: in-progress\n")
+	writeFile(t, historyPath, "{}\n")
+	m, _ := newTestManager(dir, config.DefaultConfig())
+
+	require.NoError(t, m.Start("6-1-first"))
+	assert.Equal(t, "story/6-1-first", gitCmd(t, dir, "branch", "--show-current"))
+	assert.Equal(t, "6-1-first: in-progress\n", readFile(t, statusPath))
+	assert.Equal(t, "{}\n", readFile(t, historyPath))
+}
+
+func TestManager_Template(t *testing.T) {
+	dir := initRepo(t)
+	gitCmd(t, dir, "branch", "develop")
+	cfg := config.DefaultConfig()
+	cfg.Git.BranchTemplate = "feature/epic-{{.EpicID}}/{{.StoryKey}}"
+	cfg.Git.BaseBranch = "develop"
+	cfg.Git.ReturnToBase = false
+	m, buf := newTestManager(dir, cfg)
+
+	require.NoError(t, m.Start("6-1-first"))
+	assert.Equal(t, "feature/epic-6/6-1-first", gitCmd(t, dir, "branch", "--show-current"))
+	assert.Contains(t, buf.String(), "from develop")
+
+	require.NoError(t, m.Finish("6-1-first"))
+	assert.Equal(t, "feature/epic-6/6-1-first", gitCmd(t, dir, "branch", "--show-current"))
+	assert.Contains(t, buf.String(), "Leaving story branch feature/epic-6/6-1-first checked out for review")
+}
+
+func TestManager_DetachedHead(t *testing.T) {
+	dir := initRepo(t)
+	gitCmd(t, dir, "checkout", "-q", "--detach")
+	m, _ := newTestManager(dir, config.DefaultConfig())
+
+	err := m.Start("6-1-first")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "git.base_branch")
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"os/exec"
+	"strings"
 	"sync"
 )
 
@@ -31,12 +32,13 @@ type Executor interface {
 
 	// ExecuteWithResult runs Claude with the given prompt and waits for completion.
 	// The handler is called for each [Event] received during execution.
-	// The model parameter is optional; if empty, uses the default model.
+	// The [Options] select the model, tools and other CLI settings; the zero
+	// value uses the CLI defaults with permission checks skipped.
 	// Returns the exit code (0 for success) and any error encountered during execution.
 	//
 	// This is the recommended method for production use as it provides the exit code
 	// needed to determine if Claude completed successfully.
-	ExecuteWithResult(ctx context.Context, prompt string, handler EventHandler, model string) (int, error)
+	ExecuteWithResult(ctx context.Context, prompt string, handler EventHandler, opts Options) (int, error)
 }
 
 // EventHandler is a callback function invoked for each [Event] received from Claude.
@@ -74,8 +76,32 @@ type ExecutorConfig struct {
 	// If nil, stderr output is silently discarded.
 	// Set this to capture error messages or debug output from Claude.
 	StderrHandler func(line string)
+
+	// PromptStdin decides when the prompt is written to Claude's stdin
+	// instead of being passed as a command-line argument, which is limited
+	// in size and visible in the process list: [PromptStdinAuto] above
+	// StdinThreshold, [PromptStdinAlways] or [PromptStdinNever].
+	// If empty, defaults to [PromptStdinAuto].
+	PromptStdin string
+
+	// StdinThreshold is the prompt size in bytes above which
+	// [PromptStdinAuto] sends the prompt over stdin.
+	// If 0, defaults to [DefaultStdinThreshold].
+	StdinThreshold int
 }
 
+// Values for [ExecutorConfig.PromptStdin].
+const (
+	PromptStdinAuto   = "auto"
+	PromptStdinAlways = "always"
+	PromptStdinNever  = "never"
+)
+
+// DefaultStdinThreshold is the default [ExecutorConfig.StdinThreshold],
+// well below the per-argument limit on Linux (128 KiB) and the command line
+// limit on Windows (32 KiB).
+const DefaultStdinThreshold = 16 * 1024
+
 // DefaultExecutor implements [Executor] by spawning Claude as a subprocess.
 //
 // This is the production implementation that uses os/exec to run the Claude CLI.
@@ -94,6 +120,8 @@ type DefaultExecutor struct {
 //   - BinaryPath defaults to "claude"
 //   - OutputFormat defaults to "stream-json"
 //   - Parser defaults to a new [DefaultParser]
+//   - PromptStdin defaults to [PromptStdinAuto]
+//   - StdinThreshold defaults to [DefaultStdinThreshold]
 //
 // Pass an empty [ExecutorConfig] to use all defaults.
 func NewExecutor(config ExecutorConfig) *DefaultExecutor {
@@ -103,6 +131,12 @@ func NewExecutor(config ExecutorConfig) *DefaultExecutor {
 	if config.OutputFormat == "" {
 		config.OutputFormat = "stream-json"
 	}
+	if config.PromptStdin == "" {
+		config.PromptStdin = PromptStdinAuto
+	}
+	if config.StdinThreshold <= 0 {
+		config.StdinThreshold = DefaultStdinThreshold
+	}
 
 	parser := config.Parser
 	if parser == nil {
@@ -127,12 +161,7 @@ func NewExecutor(config ExecutorConfig) *DefaultExecutor {
 // intentionally not propagated. Use [DefaultExecutor.ExecuteWithResult] if you need
 // to check whether Claude completed successfully.
 func (e *DefaultExecutor) Execute(ctx context.Context, prompt string) (<-chan Event, error) {
-	cmd := exec.CommandContext(ctx, e.config.BinaryPath,
-		"--dangerously-skip-permissions",
-		"--output-format", e.config.OutputFormat,
-		"--verbose",
-		"-p", prompt,
-	)
+	cmd := e.command(ctx, prompt, Options{})
 
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
@@ -176,18 +205,9 @@ func (e *DefaultExecutor) Execute(ctx context.Context, prompt string) (<-chan Ev
 // If the handler is provided, it is called synchronously for each event before
 // this method returns.
 //
-// The model parameter is optional. If empty, the Claude CLI will use its default model.
-func (e *DefaultExecutor) ExecuteWithResult(ctx context.Context, prompt string, handler EventHandler, model string) (int, error) {
-	args := []string{
-		"--dangerously-skip-permissions",
-		"--output-format", e.config.OutputFormat,
-		"--verbose",
-		"-p", prompt,
-	}
-	if model != "" {
-		args = append(args, "--model", model)
-	}
-	cmd := exec.CommandContext(ctx, e.config.BinaryPath, args...)
+// The opts are added to the command line after the prompt (see [Options.Args]).
+func (e *DefaultExecutor) ExecuteWithResult(ctx context.Context, prompt string, handler EventHandler, opts Options) (int, error) {
+	cmd := e.command(ctx, prompt, opts)
 
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
@@ -244,6 +264,38 @@ eventLoop:
 	return exitCode, nil
 }
 
+// command builds the Claude command for a prompt. The prompt is passed after
+// -p, or written to stdin as configured by [ExecutorConfig.PromptStdin].
+func (e *DefaultExecutor) command(ctx context.Context, prompt string, opts Options) *exec.Cmd {
+	args := []string{
+		"--output-format", e.config.OutputFormat,
+		"--verbose",
+		"-p",
+	}
+	useStdin := e.promptViaStdin(prompt)
+	if !useStdin {
+		args = append(args, prompt)
+	}
+	args = append(args, opts.Args()...)
+
+	cmd := exec.CommandContext(ctx, e.config.BinaryPath, args...)
+	if useStdin {
+		cmd.Stdin = strings.NewReader(prompt)
+	}
+	return cmd
+}
+
+// promptViaStdin reports whether the prompt is written to stdin.
+func (e *DefaultExecutor) promptViaStdin(prompt string) bool {
+	switch e.config.PromptStdin {
+	case PromptStdinAlways:
+		return true
+	case PromptStdinNever:
+		return false
+	}
+	return len(prompt) > e.config.StdinThreshold
+}
+
 func (e *DefaultExecutor) handleStderr(stderr io.ReadCloser, wg *sync.WaitGroup) {
 	if wg != nil {
 		defer wg.Done()
@@ -268,7 +320,7 @@ func (e *DefaultExecutor) handleStderr(stderr io.ReadCloser, wg *sync.WaitGroup)
 //	    Events: []Event{{Type: EventTypeAssistant, Text: "Hello"}},
 //	    ExitCode: 0,
 //	}
-//	exitCode, err := mock.ExecuteWithResult(ctx, "prompt", handler)
+//	exitCode, err := mock.ExecuteWithResult(ctx, "prompt", handler, Options{})
 //
 // After execution, check RecordedPrompts to verify the prompts that were passed:
 //
@@ -295,6 +347,9 @@ type MockExecutor struct {
 	// RecordedPrompts accumulates all prompts passed to Execute/ExecuteWithResult.
 	// Use this in tests to verify the correct prompts were sent.
 	RecordedPrompts []string
+
+	// RecordedOptions accumulates the [Options] passed to ExecuteWithResult.
+	RecordedOptions []Options
 }
 
 // Execute returns the pre-configured [MockExecutor.Events] via a channel.
@@ -331,9 +386,10 @@ func (m *MockExecutor) Execute(ctx context.Context, prompt string) (<-chan Event
 // If [MockExecutor.Error] is set, it returns 1 and the error immediately.
 // Otherwise, all [MockExecutor.Events] are passed to the handler synchronously,
 // then the configured exit code is returned.
-// The model parameter is ignored in the mock.
-func (m *MockExecutor) ExecuteWithResult(ctx context.Context, prompt string, handler EventHandler, model string) (int, error) {
+// The opts are recorded in [MockExecutor.RecordedOptions].
+func (m *MockExecutor) ExecuteWithResult(ctx context.Context, prompt string, handler EventHandler, opts Options) (int, error) {
 	m.RecordedPrompts = append(m.RecordedPrompts, prompt)
+	m.RecordedOptions = append(m.RecordedOptions, opts)
 
 	if m.Error != nil {
 		return 1, m.Error
@@ -62,7 +62,7 @@ func TestMockExecutor_ExecuteWithResult(t *testing.T) {
 	}
 
 	ctx := context.Background()
-	exitCode, err := mock.ExecuteWithResult(ctx, "test prompt", handler, "")
+	exitCode, err := mock.ExecuteWithResult(ctx, "test prompt", handler, Options{})
 
 	require.NoError(t, err)
 	assert.Equal(t, 0, exitCode)
@@ -75,7 +75,7 @@ func TestMockExecutor_ExecuteWithResult_NonZeroExit(t *testing.T) {
 	}
 
 	ctx := context.Background()
-	exitCode, err := mock.ExecuteWithResult(ctx, "test prompt", nil, "")
+	exitCode, err := mock.ExecuteWithResult(ctx, "test prompt", nil, Options{})
 
 	require.NoError(t, err)
 	assert.Equal(t, 1, exitCode)
@@ -113,6 +113,8 @@ func TestNewExecutor(t *testing.T) {
 	assert.NotNil(t, exec)
 	assert.Equal(t, "claude", exec.config.BinaryPath)
 	assert.Equal(t, "stream-json", exec.config.OutputFormat)
+	assert.Equal(t, PromptStdinAuto, exec.config.PromptStdin)
+	assert.Equal(t, DefaultStdinThreshold, exec.config.StdinThreshold)
 
 	// Test custom config
 	exec = NewExecutor(ExecutorConfig{
@@ -155,7 +157,7 @@ func TestMockExecutor_ExecuteWithResult_WithError(t *testing.T) {
 	}
 
 	ctx := context.Background()
-	exitCode, err := mock.ExecuteWithResult(ctx, "test prompt", nil, "")
+	exitCode, err := mock.ExecuteWithResult(ctx, "test prompt", nil, Options{})
 
 	assert.Error(t, err)
 	assert.Equal(t, 1, exitCode)
@@ -174,7 +176,7 @@ func TestMockExecutor_ExecuteWithResult_NilHandler(t *testing.T) {
 	}
 
 	ctx := context.Background()
-	exitCode, err := mock.ExecuteWithResult(ctx, "test prompt", nil, "")
+	exitCode, err := mock.ExecuteWithResult(ctx, "test prompt", nil, Options{})
 
 	require.NoError(t, err)
 	assert.Equal(t, 0, exitCode)
@@ -194,7 +196,7 @@ func TestMockExecutor_MultiplePrompts(t *testing.T) {
 	// Execute multiple prompts
 	_, _ = mock.Execute(ctx, "prompt 1")
 	_, _ = mock.Execute(ctx, "prompt 2")
-	_, _ = mock.ExecuteWithResult(ctx, "prompt 3", nil, "")
+	_, _ = mock.ExecuteWithResult(ctx, "prompt 3", nil, Options{})
 
 	assert.Equal(t, []string{"prompt 1", "prompt 2", "prompt 3"}, mock.RecordedPrompts)
 }
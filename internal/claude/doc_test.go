@@ -30,7 +30,7 @@ func Example_mockExecutor() {
 				fmt.Println(event.Text)
 			}
 		},
-		"", // model (empty = use default)
+		claude.Options{}, // default model and settings
 	)
 
 	if err != nil {
@@ -0,0 +1,155 @@
+package claude
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClaude is a stand-in claude binary: a shell script that records its
+// arguments and stdin, prints a short stream-json session and exits with
+// the given code.
+type fakeClaude struct {
+	path string
+	dir  string
+}
+
+func newFakeClaude(t *testing.T, exitCode int) *fakeClaude {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake claude binary is a shell script")
+	}
+
+	dir := t.TempDir()
+	script := `#!/bin/sh
+for arg in "$@"; do printf '%s\0' "$arg"; done > "` + dir + `/args"
+cat > "` + dir + `/stdin"
+echo '{"type":"system","subtype":"init","session_id":"fake-session"}'
+echo '{"type":"assistant","message":{"content":[{"type":"text","text":"Done."}]}}'
+echo '{"type":"result","subtype":"success","result":"Done."}'
+exit ` + strconv.Itoa(exitCode) + "\n"
+
+	path := filepath.Join(dir, "claude")
+	require.NoError(t, os.WriteFile(path, []byte(script), 0755))
+	return &fakeClaude{path: path, dir: dir}
+}
+
+// args returns the arguments of the last run.
+func (f *fakeClaude) args(t *testing.T) []string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(f.dir, "args"))
+	require.NoError(t, err)
+	return strings.Split(strings.TrimSuffix(string(data), "\x00"), "\x00")
+}
+
+// stdin returns what the last run read from stdin.
+func (f *fakeClaude) stdin(t *testing.T) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(f.dir, "stdin"))
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestDefaultExecutor_ExecuteWithResult(t *testing.T) {
+	fake := newFakeClaude(t, 0)
+	executor := NewExecutor(ExecutorConfig{BinaryPath: fake.path})
+
+	var texts []string
+	exitCode, err := executor.ExecuteWithResult(context.Background(), "Review story 1-1", func(event Event) {
+		if event.IsText() {
+			texts = append(texts, event.Text)
+		}
+	}, Options{Model: "sonnet"})
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, exitCode)
+	assert.Equal(t, []string{"Done."}, texts)
+	assert.Equal(t, []string{
+		"--output-format", "stream-json", "--verbose",
+		"-p", "Review story 1-1",
+		"--dangerously-skip-permissions", "--model", "sonnet",
+	}, fake.args(t))
+	assert.Empty(t, fake.stdin(t))
+}
+
+func TestDefaultExecutor_ExecuteWithResult_ExitCode(t *testing.T) {
+	fake := newFakeClaude(t, 3)
+	executor := NewExecutor(ExecutorConfig{BinaryPath: fake.path})
+
+	exitCode, err := executor.ExecuteWithResult(context.Background(), "prompt", nil, Options{})
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, exitCode)
+}
+
+func TestDefaultExecutor_PromptStdin(t *testing.T) {
+	small := "Work on story 1-1"
+	large := strings.Repeat("x", 100)
+
+	tests := []struct {
+		name      string
+		mode      string
+		prompt    string
+		wantStdin bool
+	}{
+		{"auto below threshold", PromptStdinAuto, small, false},
+		{"auto above threshold", PromptStdinAuto, large, true},
+		{"default mode is auto", "", large, true},
+		{"always", PromptStdinAlways, small, true},
+		{"never", PromptStdinNever, large, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeClaude(t, 0)
+			executor := NewExecutor(ExecutorConfig{
+				BinaryPath:     fake.path,
+				PromptStdin:    tt.mode,
+				StdinThreshold: 50,
+			})
+
+			exitCode, err := executor.ExecuteWithResult(context.Background(), tt.prompt, nil, Options{Model: "opus"})
+			require.NoError(t, err)
+			assert.Equal(t, 0, exitCode)
+
+			args := fake.args(t)
+			if tt.wantStdin {
+				assert.Equal(t, tt.prompt, fake.stdin(t))
+				assert.NotContains(t, args, tt.prompt)
+				assert.Equal(t, []string{
+					"--output-format", "stream-json", "--verbose", "-p",
+					"--dangerously-skip-permissions", "--model", "opus",
+				}, args)
+			} else {
+				assert.Empty(t, fake.stdin(t))
+				assert.Contains(t, args, tt.prompt)
+			}
+		})
+	}
+}
+
+func TestDefaultExecutor_Execute_PromptStdin(t *testing.T) {
+	fake := newFakeClaude(t, 0)
+	executor := NewExecutor(ExecutorConfig{BinaryPath: fake.path, PromptStdin: PromptStdinAlways})
+
+	events, err := executor.Execute(context.Background(), "secret prompt")
+	require.NoError(t, err)
+
+	for range events {
+		// Drain channel; Execute does not wait for the exit status
+	}
+	assert.Eventually(t, func() bool {
+		data, err := os.ReadFile(filepath.Join(fake.dir, "stdin"))
+		return err == nil && len(data) > 0
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, "secret prompt", fake.stdin(t))
+	assert.NotContains(t, fake.args(t), "secret prompt")
+}
@@ -0,0 +1,77 @@
+package claude
+
+import "strconv"
+
+// Options are the per-run Claude CLI settings passed to
+// [Executor.ExecuteWithResult].
+//
+// The zero value runs Claude with its default model and with permission
+// checks skipped, which is how workflows run unless configured otherwise.
+type Options struct {
+	// Model is the Claude model to use. If empty, the CLI default is used.
+	Model string
+
+	// AllowedTools are tool rules Claude may use without asking
+	// (e.g., "Read", "Bash(git:*)"). Passed as --allowedTools.
+	AllowedTools []string
+
+	// DisallowedTools are tool rules Claude may not use (e.g., "Edit").
+	// Passed as --disallowedTools.
+	DisallowedTools []string
+
+	// PermissionMode is passed as --permission-mode (e.g., "plan",
+	// "acceptEdits", "dontAsk"). When set, --dangerously-skip-permissions
+	// is not passed, so tools outside AllowedTools are refused.
+	PermissionMode string
+
+	// MaxTurns limits the number of agentic turns. 0 means no limit.
+	MaxTurns int
+
+	// AppendSystemPrompt is appended to Claude's default system prompt.
+	AppendSystemPrompt string
+
+	// MCPConfig is an MCP server configuration file or JSON string.
+	MCPConfig string
+
+	// AddDirs are additional directories Claude may access.
+	AddDirs []string
+
+	// ExtraArgs are passed to the CLI as-is, after all other arguments.
+	ExtraArgs []string
+}
+
+// Args returns the command-line arguments for the options, not including
+// the output format and prompt arguments every run uses.
+//
+// List options repeat their flag for each value, so a value is never
+// mistaken for the prompt or another flag's argument.
+func (o Options) Args() []string {
+	var args []string
+	if o.PermissionMode == "" {
+		args = append(args, "--dangerously-skip-permissions")
+	} else {
+		args = append(args, "--permission-mode", o.PermissionMode)
+	}
+	if o.Model != "" {
+		args = append(args, "--model", o.Model)
+	}
+	for _, tool := range o.AllowedTools {
+		args = append(args, "--allowedTools", tool)
+	}
+	for _, tool := range o.DisallowedTools {
+		args = append(args, "--disallowedTools", tool)
+	}
+	if o.MaxTurns > 0 {
+		args = append(args, "--max-turns", strconv.Itoa(o.MaxTurns))
+	}
+	if o.AppendSystemPrompt != "" {
+		args = append(args, "--append-system-prompt", o.AppendSystemPrompt)
+	}
+	if o.MCPConfig != "" {
+		args = append(args, "--mcp-config", o.MCPConfig)
+	}
+	for _, dir := range o.AddDirs {
+		args = append(args, "--add-dir", dir)
+	}
+	return append(args, o.ExtraArgs...)
+}
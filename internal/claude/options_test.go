@@ -0,0 +1,63 @@
+package claude
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptions_Args(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		want []string
+	}{
+		{
+			name: "zero value skips permissions",
+			want: []string{"--dangerously-skip-permissions"},
+		},
+		{
+			name: "model",
+			opts: Options{Model: "opus"},
+			want: []string{"--dangerously-skip-permissions", "--model", "opus"},
+		},
+		{
+			name: "permission mode replaces skip",
+			opts: Options{PermissionMode: "dontAsk", AllowedTools: []string{"Bash(git:*)", "Read"}},
+			want: []string{
+				"--permission-mode", "dontAsk",
+				"--allowedTools", "Bash(git:*)",
+				"--allowedTools", "Read",
+			},
+		},
+		{
+			name: "all options",
+			opts: Options{
+				Model:              "sonnet",
+				DisallowedTools:    []string{"Edit", "Write"},
+				MaxTurns:           20,
+				AppendSystemPrompt: "Do not modify files.",
+				MCPConfig:          ".mcp.json",
+				AddDirs:            []string{"../shared"},
+				ExtraArgs:          []string{"--fallback-model", "haiku"},
+			},
+			want: []string{
+				"--dangerously-skip-permissions",
+				"--model", "sonnet",
+				"--disallowedTools", "Edit",
+				"--disallowedTools", "Write",
+				"--max-turns", "20",
+				"--append-system-prompt", "Do not modify files.",
+				"--mcp-config", ".mcp.json",
+				"--add-dir", "../shared",
+				"--fallback-model", "haiku",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.opts.Args())
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package claude
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// projectDirPattern matches the characters Claude CLI replaces when naming a
+// project's transcript directory.
+var projectDirPattern = regexp.MustCompile(`[^a-zA-Z0-9]`)
+
+// TranscriptPath returns where Claude CLI stores the transcript of a session
+// started in projectDir: ~/.claude/projects/<project>/<session-id>.jsonl,
+// where <project> is projectDir with every non-alphanumeric character
+// replaced by "-".
+//
+// Returns an empty string if sessionID is empty or the home directory is
+// unknown. The file is written by Claude CLI and may not exist.
+func TranscriptPath(projectDir, sessionID string) string {
+	if sessionID == "" {
+		return ""
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	project := projectDirPattern.ReplaceAllString(projectDir, "-")
+	return filepath.Join(home, ".claude", "projects", project, sessionID+".jsonl")
+}
@@ -0,0 +1,25 @@
+package claude
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranscriptPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	assert.Equal(t,
+		filepath.Join(home, ".claude", "projects", "-work-my-app-v2", "abc-123.jsonl"),
+		TranscriptPath("/work/my_app.v2", "abc-123"))
+	assert.Empty(t, TranscriptPath("/work/my_app", ""))
+}
+
+func TestNewEventFromStream_SessionID(t *testing.T) {
+	event := NewEventFromStream(&StreamEvent{Type: "system", Subtype: SubtypeInit, SessionID: "abc-123"})
+
+	assert.Equal(t, "abc-123", event.SessionID)
+}
@@ -5,6 +5,7 @@
 //
 // Key types:
 //   - [Executor]: Interface for running Claude CLI commands
+//   - [Options]: Per-run CLI settings such as the model and allowed tools
 //   - [Parser]: Interface for parsing streaming JSON output
 //   - [Event]: Parsed event with convenience methods for common checks
 //   - [Usage]: Token usage information from Claude API
@@ -55,6 +56,9 @@ type StreamEvent struct {
 	Message       *MessageContent `json:"message,omitempty"`
 	ToolUseResult *ToolResult     `json:"tool_use_result,omitempty"`
 	Usage         *Usage          `json:"usage,omitempty"`
+	SessionID     string          `json:"session_id,omitempty"`
+	Result        string          `json:"result,omitempty"`
+	IsError       bool            `json:"is_error,omitempty"`
 }
 
 // MessageContent represents the content of a message in Claude's streaming output.
@@ -375,6 +379,17 @@ type Event struct {
 	// Claude session has finished.
 	SessionComplete bool
 
+	// Result is the session's final result text, populated for result events.
+	Result string
+
+	// IsError is true for result events of sessions that ended in an error,
+	// such as a usage limit or an API error.
+	IsError bool
+
+	// SessionID identifies the Claude session the event belongs to.
+	// Used to locate the session transcript (see [TranscriptPath]).
+	SessionID string
+
 	// InputTokens is the number of input tokens in this event.
 	// For assistant events, this is per-message. For result events,
 	// this is the total for the session.
@@ -393,9 +408,10 @@ type Event struct {
 // types (system, assistant, user, result) and populates the appropriate fields.
 func NewEventFromStream(raw *StreamEvent) Event {
 	e := Event{
-		Raw:     raw,
-		Type:    EventType(raw.Type),
-		Subtype: raw.Subtype,
+		Raw:       raw,
+		Type:      EventType(raw.Type),
+		Subtype:   raw.Subtype,
+		SessionID: raw.SessionID,
 	}
 
 	switch e.Type {
@@ -483,6 +499,8 @@ func NewEventFromStream(raw *StreamEvent) Event {
 
 	case EventTypeResult:
 		e.SessionComplete = true
+		e.Result = raw.Result
+		e.IsError = raw.IsError
 		// Extract final token usage from result event
 		if raw.Usage != nil {
 			e.InputTokens = raw.Usage.InputTokens
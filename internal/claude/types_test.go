@@ -95,6 +95,18 @@ func TestNewEventFromStream_Result(t *testing.T) {
 
 	assert.Equal(t, EventTypeResult, event.Type)
 	assert.True(t, event.SessionComplete)
+	assert.False(t, event.IsError)
+}
+
+func TestNewEventFromStream_ResultError(t *testing.T) {
+	var raw StreamEvent
+	require.NoError(t, json.Unmarshal([]byte(`{"type":"result","subtype":"success","is_error":true,"result":"Claude AI usage limit reached|1760000000"}`), &raw))
+
+	event := NewEventFromStream(&raw)
+
+	assert.True(t, event.SessionComplete)
+	assert.True(t, event.IsError)
+	assert.Equal(t, "Claude AI usage limit reached|1760000000", event.Result)
 }
 
 func TestEvent_IsText(t *testing.T) {
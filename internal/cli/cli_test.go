@@ -2,12 +2,15 @@ package cli
 
 import (
 	"bytes"
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"bmaduum/internal/agent"
 	"bmaduum/internal/claude"
 	"bmaduum/internal/config"
 	"bmaduum/internal/output"
@@ -48,7 +51,89 @@ func TestNewApp(t *testing.T) {
 	assert.NotNil(t, app.Printer)
 	assert.NotNil(t, app.Runner)
 	assert.NotNil(t, app.StatusReader)
+	assert.NotNil(t, app.Preflight)
+	assert.NotNil(t, app.Worktree)
+	assert.NotNil(t, app.Checkpoints)
 	assert.Equal(t, cfg, app.Config)
+
+	assert.IsType(t, &claude.DefaultExecutor{}, app.Executor)
+	assert.NoError(t, app.AgentErr)
+	assert.Nil(t, app.Brancher, "branch per story is opt-in")
+
+	cfg = config.DefaultConfig()
+	cfg.Git.Preflight = false
+	assert.Nil(t, NewApp(cfg).Preflight)
+
+	cfg = config.DefaultConfig()
+	cfg.Git.BranchPerStory = true
+	assert.NotNil(t, NewApp(cfg).Brancher)
+}
+
+func TestNewApp_AgentBackend(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Agent.Backend = config.AgentBackendCommand
+	cfg.Agent.Command.Path = "my-agent"
+
+	app := NewApp(cfg)
+	assert.NoError(t, app.AgentErr)
+	assert.IsType(t, &agent.CommandExecutor{}, app.Executor)
+
+	// An unknown backend only fails the commands that run the agent
+	cfg.Agent.Backend = "unknown"
+	app = NewApp(cfg)
+	require.Error(t, app.AgentErr)
+	assert.Contains(t, app.AgentErr.Error(), `unknown agent backend "unknown"`)
+	app.DiscoverProject = false
+
+	for _, args := range [][]string{{"raw", "hello"}, {"workflow", "dev-story", "6-1"}} {
+		rootCmd := NewRootCommand(app)
+		rootCmd.SetOut(&bytes.Buffer{})
+		rootCmd.SetArgs(args)
+		code, ok := IsExitError(rootCmd.Execute())
+		assert.True(t, ok, "%v should fail", args)
+		assert.Equal(t, 1, code)
+	}
+
+	rootCmd := NewRootCommand(app)
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"config", "path"})
+	assert.NoError(t, rootCmd.Execute())
+}
+
+func TestRootCommand_ConfigErr(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	app := setupTestApp()
+	app.ConfigErr = errors.New(`error loading config: unknown profile "fast" (no profiles defined)`)
+
+	rootCmd := NewRootCommand(app)
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetErr(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"raw", "hello"})
+	assert.Equal(t, app.ConfigErr, rootCmd.Execute(), "commands other than config report the error")
+
+	// The config commands still run, so the configuration can be repaired
+	buf := &bytes.Buffer{}
+	rootCmd = NewRootCommand(app)
+	rootCmd.SetOut(buf)
+	rootCmd.SetArgs([]string{"--profile", "fast", "config", "validate"})
+	code, ok := IsExitError(rootCmd.Execute())
+	require.True(t, ok)
+	assert.Equal(t, 1, code)
+	assert.Equal(t, "Configuration has 1 problem(s):\n  - error loading config: unknown profile \"fast\" (no profiles defined)\n", buf.String())
+
+	for _, args := range [][]string{{"config", "path"}, {"config", "init", "project"}} {
+		rootCmd = NewRootCommand(app)
+		rootCmd.SetOut(&bytes.Buffer{})
+		rootCmd.SetArgs(args)
+		assert.NoError(t, rootCmd.Execute(), "%v", args)
+	}
+
+	rootCmd = NewRootCommand(app)
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"config", "show"})
+	_, ok = IsExitError(rootCmd.Execute())
+	assert.True(t, ok, "config show has no configuration to show")
 }
 
 func TestNewRootCommand(t *testing.T) {
@@ -69,6 +154,7 @@ func TestNewRootCommand_HasAllSubcommands(t *testing.T) {
 		"epic",
 		"workflow",
 		"raw",
+		"status",
 	}
 
 	commands := rootCmd.Commands()
@@ -156,6 +242,59 @@ func TestWorkflowSubcommands(t *testing.T) {
 	}
 }
 
+func TestWorkflowCommand_InternalWorkflows(t *testing.T) {
+	app := setupTestApp()
+	workflowCmd := newWorkflowCommand(app)
+
+	for _, name := range []string{"commit-message", "fix-gates"} {
+		t.Run(name, func(t *testing.T) {
+			assert.Nil(t, findCommand(workflowCmd, name), "internal workflows are not subcommands")
+			assert.NotContains(t, workflowCmd.Long, "  - "+name)
+
+			rootCmd := NewRootCommand(setupTestApp())
+			buf := &bytes.Buffer{}
+			rootCmd.SetOut(buf)
+			rootCmd.SetErr(buf)
+			rootCmd.SetArgs([]string{"workflow", name, "TEST-123"})
+
+			err := rootCmd.Execute()
+			require.Error(t, err)
+			assert.Equal(t, "workflow "+name+" is run by bmaduum itself and cannot be run on its own", err.Error())
+		})
+	}
+}
+
+func TestWorkflowCommand_Retrospective(t *testing.T) {
+	app := setupTestApp()
+	app.Config.Workflows[config.RetrospectiveWorkflow] = config.WorkflowConfig{PromptTemplate: "Retro for epic {{.EpicID}}"}
+	workflowCmd := newWorkflowCommand(app)
+
+	assert.Nil(t, findCommand(workflowCmd, config.RetrospectiveWorkflow), "the epic retrospective is not a story subcommand")
+	assert.NotContains(t, workflowCmd.Long, "  - "+config.RetrospectiveWorkflow)
+
+	rootCmd := NewRootCommand(app)
+	buf := &bytes.Buffer{}
+	rootCmd.SetOut(buf)
+	rootCmd.SetErr(buf)
+	rootCmd.SetArgs([]string{"workflow", config.RetrospectiveWorkflow, "TEST-123"})
+
+	err := rootCmd.Execute()
+	require.Error(t, err)
+	assert.Equal(t, "workflow retrospective runs for a whole epic, not a story: use bmaduum epic <epic-id>", err.Error())
+}
+
+func TestWorkflowSubcommand_Long(t *testing.T) {
+	app := setupTestApp()
+	app.Config.Workflows["security-review"] = config.WorkflowConfig{
+		Description:    "Review the story's changes for security issues.",
+		PromptTemplate: "Review {{.StoryKey}}",
+	}
+
+	cmd := findCommand(newWorkflowCommand(app), "security-review")
+	require.NotNil(t, cmd)
+	assert.True(t, strings.HasPrefix(cmd.Long, "Review the story's changes for security issues.\n"), cmd.Long)
+}
+
 func TestStoryCommand(t *testing.T) {
 	app := setupTestApp()
 	cmd := newStoryCommand(app)
@@ -433,3 +572,36 @@ func TestExecuteResult(t *testing.T) {
 		assert.Error(t, result.Err)
 	})
 }
+
+func TestWorkflowCommand_CustomWorkflow(t *testing.T) {
+	runner := &MockWorkflowRunner{}
+	cfg := config.DefaultConfig()
+	cfg.Workflows["security-scan"] = config.WorkflowConfig{
+		Description:    "Scan the story's changes for vulnerabilities",
+		PromptTemplate: "Scan {{.StoryKey}}",
+	}
+	cfg.Workflows["dev-story"] = config.WorkflowConfig{PromptTemplate: "Custom dev {{.StoryKey}}"}
+	app := &App{Config: cfg, Runner: runner, Printer: output.NewPrinterWithWriter(&bytes.Buffer{})}
+
+	rootCmd := NewRootCommand(app)
+	workflowCmd := findCommand(rootCmd, "workflow")
+	require.NotNil(t, workflowCmd)
+	assert.Contains(t, workflowCmd.Long, "  - security-scan: Scan the story's changes for vulnerabilities")
+
+	scan := findCommand(workflowCmd, "security-scan")
+	require.NotNil(t, scan, "custom workflows get a subcommand")
+	assert.Equal(t, "Scan the story's changes for vulnerabilities", scan.Short)
+	assert.Equal(t, "Implement a story (ready-for-dev or in-progress)", findCommand(workflowCmd, "dev-story").Short,
+		"standard workflows keep their built-in description when overridden without one")
+
+	rootCmd.SetArgs([]string{"workflow", "security-scan", "6-1-first"})
+	require.NoError(t, rootCmd.Execute())
+	assert.Equal(t, []string{"security-scan"}, runner.ExecutedWorkflows)
+
+	rootCmd = NewRootCommand(app)
+	rootCmd.SetArgs([]string{"workflow", "deploy", "6-1-first"})
+	err := rootCmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown workflow: deploy")
+	assert.Contains(t, err.Error(), "security-scan")
+}
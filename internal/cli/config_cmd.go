@@ -0,0 +1,280 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"bmaduum/internal/agent"
+	"bmaduum/internal/config"
+	"bmaduum/internal/status"
+)
+
+// newConfigCommand creates the config command for creating, inspecting and
+// checking the configuration.
+func newConfigCommand(app *App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Create, show and validate the configuration",
+		Long: `Create, inspect and check the configuration bmaduum runs with.
+
+Configuration is merged in layers, each overriding the ones before it:
+built-in defaults, the user config file, the project's .bmaduum.yaml, the
+file named by BMADUUM_CONFIG_PATH, BMADUUM_ environment variables and
+command line flags.`,
+	}
+
+	cmd.AddCommand(
+		newConfigInitCommand(),
+		newConfigShowCommand(app),
+		newConfigValidateCommand(app),
+		newConfigPathCommand(),
+	)
+	return cmd
+}
+
+// newConfigInitCommand creates the config init command.
+func newConfigInitCommand() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "init [user|project]",
+		Short: "Write a commented starter config file",
+		Long: `Write a commented starter config file to the user config directory
+(default), or to .bmaduum.yaml in the project directory.
+
+Every setting in the starter file is commented out, so it changes nothing
+until you uncomment what you need. An existing file is only replaced with
+--force.
+
+Examples:
+  bmaduum config init
+  bmaduum config init project
+  bmaduum -C ../other-project config init project`,
+		Args:      cobra.MatchAll(cobra.MaximumNArgs(1), cobra.OnlyValidArgs),
+		ValidArgs: []string{config.OriginUser, config.OriginProject},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			location := config.OriginUser
+			if len(args) > 0 {
+				location = args[0]
+			}
+
+			path, err := configInitPath(location)
+			if err == nil {
+				err = writeStarterConfig(path, force)
+			}
+			if err != nil {
+				cmd.SilenceUsage = true
+				fmt.Printf("Error: %v\n", err)
+				return NewExitError(1)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Wrote starter configuration to %s\n", path)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite an existing config file")
+	return cmd
+}
+
+// configInitPath returns where config init writes for a location: the user
+// config file, or the project config in the current directory, which is the
+// project root once --project or project discovery has run.
+func configInitPath(location string) (string, error) {
+	if location == config.OriginProject {
+		wd, err := os.Getwd()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(wd, config.ProjectConfigFile), nil
+	}
+	return config.DefaultConfigPath()
+}
+
+// writeStarterConfig writes [config.StarterConfig] to path, creating its
+// directory. Returns an error if path exists, unless force is set.
+func writeStarterConfig(path string, force bool) error {
+	if _, err := os.Stat(path); err == nil && !force {
+		return fmt.Errorf("%s already exists (use --force to overwrite)", path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(config.StarterConfig), 0644)
+}
+
+// newConfigShowCommand creates the config show command.
+func newConfigShowCommand(app *App) *cobra.Command {
+	var origin bool
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "Print the effective configuration",
+		Long: `Print the effective configuration after merging all layers, as YAML or
+with --format json as JSON.
+
+Use --origin to print every setting on its own line together with where its
+value came from: default, a config file, an environment variable or a flag.
+
+Examples:
+  bmaduum config show
+  bmaduum config show --format json
+  bmaduum config show --origin
+  bmaduum -C ../other-project config show --origin`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+
+			if app.ConfigErr != nil {
+				cmd.SilenceUsage = true
+				fmt.Printf("Error: %v\n", app.ConfigErr)
+				return NewExitError(1)
+			}
+
+			if origin {
+				printConfigOrigins(cmd, app.Config)
+				return nil
+			}
+
+			var data []byte
+			var err error
+			switch format {
+			case "yaml":
+				data, err = yaml.Marshal(app.Config.AllSettings())
+			case "json":
+				data, err = json.MarshalIndent(app.Config.AllSettings(), "", "  ")
+				data = append(data, '\n')
+			default:
+				err = fmt.Errorf("unknown format %q (use yaml or json)", format)
+			}
+			if err != nil {
+				cmd.SilenceUsage = true
+				fmt.Printf("Error: %v\n", err)
+				return NewExitError(1)
+			}
+			fmt.Fprint(out, string(data))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&origin, "origin", false, "Show where each value comes from")
+	cmd.Flags().StringVar(&format, "format", "yaml", "Output format: yaml or json")
+	return cmd
+}
+
+// printConfigOrigins prints the merged config files, then every setting with
+// its value and origin.
+func printConfigOrigins(cmd *cobra.Command, cfg *config.Config) {
+	out := cmd.OutOrStdout()
+	for _, layer := range cfg.Layers() {
+		fmt.Fprintf(out, "# %s: %s\n", layer.Origin, layer.Path)
+	}
+	cfg.Settings(func(key string, value any) {
+		data, err := json.Marshal(value)
+		if err != nil {
+			data = []byte(fmt.Sprint(value))
+		}
+		fmt.Fprintf(out, "%s = %s  (%s)\n", key, data, cfg.Origin(key))
+	})
+}
+
+// newConfigValidateCommand creates the config validate command.
+func newConfigValidateCommand(app *App) *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate",
+		Short: "Check the configuration for mistakes",
+		Long: `Check the effective configuration for mistakes before a run hits them.
+
+Reports a configuration that cannot be loaded, such as a prompt_file that
+does not parse or an unknown profile. Otherwise checks that prompt and
+branch templates parse, workflows named in full_cycle and lifecycle.steps
+exist, lifecycle next_status values are story statuses, models are
+non-empty names, enumerated settings have valid values, gates and hooks
+have commands, agent.backend is known, the command agent backend is
+complete and no workflow sets Claude-only settings it ignores, and the
+agent binary (the claude binary by default) can be found.
+
+Exits with status 1 if any problem is found.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			problems := validateConfig(app.Config, app.ConfigErr)
+			out := cmd.OutOrStdout()
+			if len(problems) == 0 {
+				fmt.Fprintln(out, "Configuration is valid")
+				return nil
+			}
+
+			cmd.SilenceUsage = true
+			fmt.Fprintf(out, "Configuration has %d problem(s):\n", len(problems))
+			for _, problem := range problems {
+				fmt.Fprintf(out, "  - %s\n", problem)
+			}
+			return NewExitError(1)
+		},
+	}
+}
+
+// validateConfig runs [config.Config.Validate] and the checks that need
+// packages the config package cannot depend on: lifecycle next_status
+// values, the agent backend and the agent binary. If loading the configuration failed with
+// loadErr, that is the only problem reported, since cfg then holds the
+// defaults rather than the configuration.
+func validateConfig(cfg *config.Config, loadErr error) []string {
+	if loadErr != nil {
+		return []string{loadErr.Error()}
+	}
+
+	problems := cfg.Validate()
+
+	for i, s := range cfg.Lifecycle.Steps {
+		next := status.Status(s.NextStatus)
+		if next != "" && !next.IsValidFor(status.EntryStory) {
+			problems = append(problems, fmt.Sprintf("lifecycle.steps[%d].next_status: %q is not a story status", i, s.NextStatus))
+		}
+	}
+
+	if backend := cfg.Agent.Backend; backend != "" && !slices.Contains(agent.Backends(), backend) {
+		problems = append(problems, fmt.Sprintf("agent.backend: unknown agent backend %q (available: %s)", backend, strings.Join(agent.Backends(), ", ")))
+	}
+
+	if binary, setting := agent.Binary(cfg); binary != "" {
+		if _, err := exec.LookPath(binary); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %q cannot be found", setting, binary))
+		}
+	}
+
+	return problems
+}
+
+// newConfigPathCommand creates the config path command.
+func newConfigPathCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "path",
+		Short: "List the config file search paths",
+		Long: `List the config files bmaduum looks for, lowest precedence first, and
+whether each one exists. Files that exist are merged in this order over the
+built-in defaults.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			for _, layer := range config.NewLoader().SearchPaths() {
+				found := "not found"
+				if info, err := os.Stat(layer.Path); err == nil && !info.IsDir() {
+					found = "found"
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\n", layer.Origin, layer.Path, found)
+			}
+			return tw.Flush()
+		},
+	}
+}
@@ -0,0 +1,219 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bmaduum/internal/config"
+)
+
+func TestConfigShowCommand(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("BMADUUM_CONFIG_PATH", "")
+	t.Setenv("BMADUUM_CLAUDE_PATH", "")
+
+	project := t.TempDir()
+	projectFile := filepath.Join(project, config.ProjectConfigFile)
+	require.NoError(t, os.WriteFile(projectFile, []byte("workflows:\n  dev-story:\n    model: opus\n"), 0644))
+
+	loader := config.NewLoader()
+	loader.SetProjectDir(project)
+	cfg, err := loader.Load()
+	require.NoError(t, err)
+
+	app := newProjectTestApp(&MockWorkflowRunner{})
+	app.Config = cfg
+
+	t.Run("yaml", func(t *testing.T) {
+		var out bytes.Buffer
+		rootCmd := NewRootCommand(app)
+		rootCmd.SetOut(&out)
+		rootCmd.SetArgs([]string{"config", "show"})
+		require.NoError(t, rootCmd.Execute())
+
+		assert.Contains(t, out.String(), "binary_path: claude")
+		assert.Contains(t, out.String(), "model: opus")
+	})
+
+	t.Run("origin", func(t *testing.T) {
+		var out bytes.Buffer
+		rootCmd := NewRootCommand(app)
+		rootCmd.SetOut(&out)
+		rootCmd.SetArgs([]string{"--var", "team=core", "config", "show", "--origin"})
+		require.NoError(t, rootCmd.Execute())
+
+		assert.Contains(t, out.String(), "# project: "+projectFile+"\n")
+		assert.Contains(t, out.String(), `workflows.dev-story.model = "opus"  (project `+projectFile+")\n")
+		assert.Contains(t, out.String(), `claude.binary_path = "claude"  (default)`+"\n")
+		assert.Contains(t, out.String(), `vars.team = "core"  (flag --var)`+"\n")
+	})
+}
+
+func TestFlagValue(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"story", "6-1"}, ""},
+		{[]string{"-C", "../other", "story", "6-1"}, "../other"},
+		{[]string{"--project", "../other", "story"}, "../other"},
+		{[]string{"story", "--project=../other"}, "../other"},
+		{[]string{"-C../other", "story"}, "../other"},
+		{[]string{"-C=../other", "story"}, "../other"},
+		{[]string{"-C"}, ""},
+		{[]string{"--Config", "x", "story"}, ""},
+		{[]string{"--projects", "x", "story"}, ""},
+		{[]string{"--project-dir=x", "story"}, ""},
+		{[]string{"raw", "--", "-C", "x"}, ""},
+		{[]string{"raw", "--", "-Cx"}, ""},
+		{[]string{"raw", "--", "--project", "x"}, ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, flagValue(tt.args, "project", "C"), "args %v", tt.args)
+	}
+
+	assert.Equal(t, "fast", flagValue([]string{"--profile", "fast", "story", "6-1"}, "profile", ""))
+	assert.Equal(t, "fast", flagValue([]string{"epic", "--profile=fast", "6"}, "profile", ""))
+	assert.Equal(t, "", flagValue([]string{"-p", "fast"}, "profile", ""))
+}
+
+func TestConfigShowCommand_JSON(t *testing.T) {
+	var out bytes.Buffer
+	rootCmd := NewRootCommand(newProjectTestApp(&MockWorkflowRunner{}))
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"config", "show", "--format", "json"})
+	require.NoError(t, rootCmd.Execute())
+
+	var settings map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &settings))
+	assert.Equal(t, "claude", settings["claude"].(map[string]any)["binary_path"])
+
+	rootCmd = NewRootCommand(newProjectTestApp(&MockWorkflowRunner{}))
+	rootCmd.SetArgs([]string{"config", "show", "--format", "toml"})
+	_, isExit := IsExitError(rootCmd.Execute())
+	assert.True(t, isExit)
+}
+
+func TestConfigInitCommand(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", home)
+	project := t.TempDir()
+	createSprintStatusFile(t, project, "development_status:\n  6-1-first: backlog")
+
+	run := func(args ...string) (string, error) {
+		var out bytes.Buffer
+		rootCmd := NewRootCommand(newProjectTestApp(&MockWorkflowRunner{}))
+		rootCmd.SetOut(&out)
+		rootCmd.SetArgs(args)
+		err := rootCmd.Execute()
+		return out.String(), err
+	}
+	t.Chdir(t.TempDir())
+
+	userFile := filepath.Join(home, "bmaduum", "workflows.yaml")
+	out, err := run("config", "init")
+	require.NoError(t, err)
+	assert.Equal(t, "Wrote starter configuration to "+userFile+"\n", out)
+	data, err := os.ReadFile(userFile)
+	require.NoError(t, err)
+	assert.Equal(t, config.StarterConfig, string(data))
+
+	// An existing file is only replaced with --force
+	require.NoError(t, os.WriteFile(userFile, []byte("git:\n  push: false\n"), 0644))
+	_, err = run("config", "init")
+	_, isExit := IsExitError(err)
+	assert.True(t, isExit)
+	data, _ = os.ReadFile(userFile)
+	assert.Equal(t, "git:\n  push: false\n", string(data))
+
+	_, err = run("config", "init", "--force")
+	require.NoError(t, err)
+	data, _ = os.ReadFile(userFile)
+	assert.Equal(t, config.StarterConfig, string(data))
+
+	projectFile := filepath.Join(project, config.ProjectConfigFile)
+	out, err = run("-C", project, "config", "init", "project")
+	require.NoError(t, err)
+	assert.Contains(t, out, projectFile)
+	assert.FileExists(t, projectFile)
+
+	_, err = run("config", "init", "system")
+	assert.ErrorContains(t, err, "invalid argument")
+}
+
+func TestConfigValidateCommand(t *testing.T) {
+	run := func(cfg *config.Config) (string, error) {
+		app := newProjectTestApp(&MockWorkflowRunner{})
+		app.Config = cfg
+		var out bytes.Buffer
+		rootCmd := NewRootCommand(app)
+		rootCmd.SetOut(&out)
+		rootCmd.SetArgs([]string{"config", "validate"})
+		err := rootCmd.Execute()
+		return out.String(), err
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Claude.BinaryPath = "sh"
+	out, err := run(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "Configuration is valid\n", out)
+
+	cfg.Claude.BinaryPath = "/nonexistent/claude"
+	cfg.Lifecycle.Steps = []config.LifecycleStepConfig{{Workflow: "dev-story", NextStatus: "finished"}}
+	out, err = run(cfg)
+	code, isExit := IsExitError(err)
+	assert.True(t, isExit)
+	assert.Equal(t, 1, code)
+	assert.Equal(t, `Configuration has 2 problem(s):
+  - lifecycle.steps[0].next_status: "finished" is not a story status
+  - claude.binary_path: "/nonexistent/claude" cannot be found
+`, out)
+
+	// The command backend's binary is checked instead of claude's
+	cfg = config.DefaultConfig()
+	cfg.Claude.BinaryPath = "/nonexistent/claude"
+	cfg.Agent.Backend = config.AgentBackendCommand
+	cfg.Agent.Command.Path = "/nonexistent/agent"
+	out, _ = run(cfg)
+	assert.Equal(t, `Configuration has 2 problem(s):
+  - agent.command.events: no event mappings, so no agent output would be shown
+  - agent.command.path: "/nonexistent/agent" cannot be found
+`, out)
+
+	cfg = config.DefaultConfig()
+	cfg.Claude.BinaryPath = "sh"
+	cfg.Agent.Backend = "codex"
+	out, _ = run(cfg)
+	assert.Equal(t, `Configuration has 1 problem(s):
+  - agent.backend: unknown agent backend "codex" (available: claude, command)
+`, out)
+}
+
+func TestConfigPathCommand(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", home)
+	t.Setenv("BMADUUM_CONFIG_PATH", "")
+	project := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(project, config.ProjectConfigFile), nil, 0644))
+	t.Chdir(project)
+
+	var out bytes.Buffer
+	rootCmd := NewRootCommand(newProjectTestApp(&MockWorkflowRunner{}))
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"config", "path"})
+	require.NoError(t, rootCmd.Execute())
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 4)
+	assert.Regexp(t, `^user\s+`+regexp.QuoteMeta(filepath.Join(home, "bmaduum", "workflows.yaml"))+`\s+not found$`, lines[0])
+	assert.Regexp(t, `^project\s+.*`+regexp.QuoteMeta(config.ProjectConfigFile)+`\s+found$`, lines[3])
+}
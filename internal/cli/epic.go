@@ -1,18 +1,23 @@
 package cli
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"bmaduum/internal/config"
 	"bmaduum/internal/lifecycle"
 	"bmaduum/internal/router"
+	"bmaduum/internal/status"
 )
 
 func newEpicCommand(app *App) *cobra.Command {
-	var dryRun bool
-	var autoRetry bool
+	var opts epicOptions
+	var workspaceFile string
 
 	cmd := &cobra.Command{
 		Use:   "epic <epic-id>|all [epic-id...]",
@@ -22,6 +27,11 @@ func newEpicCommand(app *App) *cobra.Command {
 Finds all stories matching the pattern {epic-id}-{N}-* where N is numeric,
 sorts them by story number, and runs each to completion before moving to the next.
 
+Stories that declare dependencies (in the dependencies map of sprint-status.yaml
+or a "Depends on:" line in the story file) run after the stories they depend on.
+A story whose dependencies are not done is refused, and dependency cycles are
+reported as errors.
+
 For each story, executes all remaining workflows based on its current status:
   - backlog       → create-story → dev-story → code-review → git-commit → done
   - ready-for-dev → dev-story → code-review → git-commit → done
@@ -32,100 +42,267 @@ For each story, executes all remaining workflows based on its current status:
 The epic command stops on the first failure. Done stories are skipped and do not cause failure.
 Status is updated in sprint-status.yaml after each successful workflow.
 
+If a "retrospective" workflow is configured, it runs once all of an epic's
+stories are done, and the epic-N-retrospective entry is then marked done
+(or, without an entry, the retrospective is recorded as done in the status
+history so it does not run again).
+The template receives {{.EpicID}} and {{.Stories}}.
+
 Use --dry-run to preview workflows without executing them.
 Use --auto-retry to automatically retry on rate limit errors.
+Use --no-retro to skip the epic retrospective.
+Use --force to run even if the preflight safety checks fail (see story --help).
+Use --no-push to commit stories without pushing them.
+Use --workspace to run the epics in every project listed in a workspace file:
+
+  projects:
+    - ../service-a
+    - ../service-b
 
 Examples:
   bmaduum epic 6
   bmaduum epic 2 4 6
-  bmaduum epic all`,
+  bmaduum epic all
+  bmaduum epic all --workspace bmaduum-workspace.yaml`,
 		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx := cmd.Context()
+			if workspaceFile == "" {
+				return runEpics(cmd, app, args, opts)
+			}
+
+			// Run the same epics in every project listed in the workspace file
+			ws, err := config.LoadWorkspace(app.resolveInvocationPath(workspaceFile))
+			if err != nil {
+				cmd.SilenceUsage = true
+				fmt.Printf("Error: %v\n", err)
+				return NewExitError(1)
+			}
 
-			var epicIDs []string
-			if args[0] == "all" {
-				// Special case: "all" means all active epics
-				allEpics, err := app.StatusReader.GetAllEpics()
+			for i, project := range ws.Projects {
+				fmt.Printf("═══ Project %d of %d: %s\n", i+1, len(ws.Projects), project)
+				err := app.reloadConfig(project)
+				if err == nil {
+					err = app.UseProject(project)
+				}
 				if err != nil {
 					cmd.SilenceUsage = true
-					fmt.Printf("Error reading epics: %v\n", err)
+					fmt.Printf("Error: %v\n", err)
 					return NewExitError(1)
 				}
-				if len(allEpics) == 0 {
-					fmt.Println("No active epics found")
-					return nil
+				if err := runEpics(cmd, app, args, opts); err != nil {
+					return err
 				}
-				epicIDs = allEpics
-			} else {
-				epicIDs = args
+				fmt.Println()
 			}
 
-			// Create lifecycle executor with app dependencies
-			executor := lifecycle.NewExecutor(app.Runner, app.StatusReader, app.StatusWriter)
+			fmt.Printf("✓ All %d project(s) completed successfully!\n", len(ws.Projects))
+			return nil
+		},
+	}
 
-			// Handle dry-run mode
-			if dryRun {
-				return runEpicDryRun(cmd, app, executor, epicIDs)
-			}
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Preview workflows without executing them")
+	cmd.Flags().BoolVar(&opts.autoRetry, "auto-retry", false, "Automatically retry on rate limit errors")
+	cmd.Flags().BoolVar(&opts.noRetro, "no-retro", false, "Skip the epic retrospective workflow")
+	cmd.Flags().BoolVar(&opts.force, "force", false, "Run even if the preflight safety checks fail")
+	cmd.Flags().BoolVar(&opts.noPush, "no-push", false, "Commit stories without pushing them")
+	cmd.Flags().StringVar(&workspaceFile, "workspace", "", "Run the epics in every project listed in this workspace file")
 
-			// Process each epic
-			for epicIdx, epicID := range epicIDs {
-				// Set operation context for progress display
-				if len(epicIDs) == 1 && epicIDs[0] == "all" {
-					app.Runner.SetOperation("Epic all")
-				} else if len(epicIDs) > 1 {
-					app.Runner.SetOperation(fmt.Sprintf("Epic %d of %d: %s", epicIdx+1, len(epicIDs), epicID))
-				} else {
-					app.Runner.SetOperation(fmt.Sprintf("Epic %s", epicID))
-				}
+	return cmd
+}
 
-				// Get all stories for this epic
-				storyKeys, err := app.StatusReader.GetEpicStories(epicID)
-				if err != nil {
-					cmd.SilenceUsage = true
-					fmt.Printf("Error reading stories for epic %s: %v\n", epicID, err)
-					return NewExitError(1)
-				}
+// epicOptions holds the flags shared by every project in an epic run.
+type epicOptions struct {
+	dryRun    bool
+	autoRetry bool
+	noRetro   bool
+	force     bool
+	noPush    bool
+}
+
+// runEpics runs the given epics (or "all") in the current project.
+func runEpics(cmd *cobra.Command, app *App, args []string, opts epicOptions) error {
+	ctx := cmd.Context()
+	if opts.noPush {
+		app.Config.Git.Push = false
+		app.Config.SetOrigin("git.push", config.OriginFlag+" --no-push")
+	}
+
+	var epicIDs []string
+	if args[0] == "all" {
+		// Special case: "all" means all active epics
+		allEpics, err := app.StatusReader.GetAllEpics()
+		if err != nil {
+			cmd.SilenceUsage = true
+			fmt.Printf("Error reading epics: %v\n", err)
+			return NewExitError(1)
+		}
+		if len(allEpics) == 0 {
+			fmt.Println("No active epics found")
+			return nil
+		}
+		epicIDs = allEpics
+	} else {
+		epicIDs = args
+	}
+
+	// Create lifecycle executor with app dependencies
+	executor, err := newExecutor(app)
+	if err != nil {
+		cmd.SilenceUsage = true
+		fmt.Printf("Error: %v\n", err)
+		return NewExitError(1)
+	}
+
+	// Handle dry-run mode
+	if opts.dryRun {
+		return runEpicDryRun(cmd, app, executor, epicIDs, opts.noRetro)
+	}
+
+	if err := requireAgent(cmd, app); err != nil {
+		return err
+	}
+
+	if err := runPreflight(cmd, app, opts.force); err != nil {
+		return err
+	}
+
+	// Stories completed during this run count as done for dependency checks
+	completed := make(map[string]bool)
+
+	// Process each epic
+	for epicIdx, epicID := range epicIDs {
+		// Set operation context for progress display
+		if len(epicIDs) == 1 && epicIDs[0] == "all" {
+			app.Runner.SetOperation("Epic all")
+		} else if len(epicIDs) > 1 {
+			app.Runner.SetOperation(fmt.Sprintf("Epic %d of %d: %s", epicIdx+1, len(epicIDs), epicID))
+		} else {
+			app.Runner.SetOperation(fmt.Sprintf("Epic %s", epicID))
+		}
+
+		// Get all stories for this epic
+		storyKeys, err := app.StatusReader.GetEpicStories(epicID)
+		if err != nil {
+			cmd.SilenceUsage = true
+			fmt.Printf("Error reading stories for epic %s: %v\n", epicID, err)
+			return NewExitError(1)
+		}
+
+		// Execute full lifecycle for each story in order
+		for storyIdx, storyKey := range storyKeys {
+			// Update operation to show story progress within epic
+			if len(storyKeys) > 1 {
+				app.Runner.SetOperation(fmt.Sprintf("Epic %s: Story %d of %d", epicID, storyIdx+1, len(storyKeys)))
+			}
+
+			// Refuse to start stories whose dependencies are not done
+			if err := checkDependencies(app.StatusReader, storyKey, completed); err != nil {
+				cmd.SilenceUsage = true
+				fmt.Printf("Error running lifecycle for story %s: %v\n", storyKey, err)
+				return NewExitError(1)
+			}
 
-				// Execute full lifecycle for each story in order
-				for storyIdx, storyKey := range storyKeys {
-					// Update operation to show story progress within epic
-					if len(storyKeys) > 1 {
-						app.Runner.SetOperation(fmt.Sprintf("Epic %s: Story %d of %d", epicID, storyIdx+1, len(storyKeys)))
-					}
-
-					err := executeWithRetry(ctx, executor, storyKey, autoRetry, 10, func(stepIndex, totalSteps int, workflow string) {
-						app.Printer.StepStart(stepIndex, totalSteps, workflow)
-					})
-					if err != nil {
-						cmd.SilenceUsage = true
-						if errors.Is(err, router.ErrStoryComplete) {
-							fmt.Printf("Story %s is already complete, skipping\n", storyKey)
-							continue
-						}
-						fmt.Printf("Error running lifecycle for story %s: %v\n", storyKey, err)
-						return NewExitError(1)
-					}
-					fmt.Printf("Story %s completed successfully\n", storyKey)
+			start := time.Now()
+			err := executeWithRetry(ctx, executor, storyKey, opts.autoRetry, 10, func(stepIndex, totalSteps int, workflow string) {
+				app.Printer.StepStart(stepIndex, totalSteps, workflow)
+			})
+			if err != nil {
+				cmd.SilenceUsage = true
+				if errors.Is(err, router.ErrStoryComplete) {
+					completed[storyKey] = true
+					fmt.Printf("Story %s is already complete, skipping\n", storyKey)
+					continue
 				}
+				printCycleSummary(app, storyKey, executor, time.Since(start))
+				printStoryFailure(storyKey, executor, err)
+				return NewExitError(1)
+			}
+			completed[storyKey] = true
+			printCycleSummary(app, storyKey, executor, time.Since(start))
+			fmt.Printf("Story %s completed successfully\n", storyKey)
+		}
 
-				fmt.Printf("Epic %s completed (%d stories processed)\n\n", epicID, len(storyKeys))
+		// All stories are done at this point; run the retrospective if configured
+		if !opts.noRetro {
+			if err := runRetrospective(ctx, app, epicID, storyKeys); err != nil {
+				cmd.SilenceUsage = true
+				fmt.Printf("Error running retrospective for epic %s: %v\n", epicID, err)
+				return NewExitError(1)
 			}
+		}
 
-			fmt.Printf("✓ All %d epic(s) completed successfully!\n", len(epicIDs))
+		fmt.Printf("Epic %s completed (%d stories processed)\n\n", epicID, len(storyKeys))
+	}
 
-			return nil
-		},
+	fmt.Printf("✓ All %d epic(s) completed successfully!\n", len(epicIDs))
+
+	return nil
+}
+
+// runRetrospective runs the configured retrospective workflow for a completed epic.
+//
+// It is a no-op when no retrospective workflow is configured or the epic's
+// retrospective is already done (see [retrospectiveDone]). After a
+// successful run, the epic-N-retrospective entry is marked done, or if the
+// status file has none, its completion is recorded in the status history.
+func runRetrospective(ctx context.Context, app *App, epicID string, storyKeys []string) error {
+	if !app.Config.HasWorkflow(config.RetrospectiveWorkflow) {
+		return nil
+	}
+
+	epic, err := app.StatusReader.GetEpic(epicID)
+	if err != nil {
+		return err
+	}
+	if retrospectiveDone(app, epic) {
+		return nil
 	}
 
-	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview workflows without executing them")
-	cmd.Flags().BoolVar(&autoRetry, "auto-retry", false, "Automatically retry on rate limit errors")
+	app.Runner.SetOperation(fmt.Sprintf("Epic %s: Retrospective", epicID))
+	exitCode := app.Runner.RunWorkflow(ctx, config.RetrospectiveWorkflow, config.PromptData{
+		EpicID:  epicID,
+		Stories: storyKeys,
+	})
+	if exitCode != 0 {
+		return fmt.Errorf("workflow failed: %s returned exit code %d", config.RetrospectiveWorkflow, exitCode)
+	}
 
-	return cmd
+	if epic.Retrospective != nil {
+		if err := app.StatusWriter.UpdateStatus(epic.Retrospective.Key, status.StatusDone); err != nil {
+			return err
+		}
+	} else if recorder, ok := app.StatusWriter.(HistoryRecorder); ok {
+		if err := recorder.RecordTransition(status.RetrospectiveKey(epicID), status.StatusDone, config.RetrospectiveWorkflow); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Retrospective for epic %s completed\n", epicID)
+	return nil
+}
+
+// retrospectiveDone reports whether an epic's retrospective is done: its
+// epic-N-retrospective entry is done, or without an entry, the status
+// history records it as done.
+func retrospectiveDone(app *App, epic *status.Epic) bool {
+	if epic.Retrospective != nil {
+		return epic.Retrospective.Status == status.StatusDone
+	}
+	transitions, err := app.StatusReader.GetHistory(status.RetrospectiveKey(epic.ID))
+	if err != nil {
+		return false
+	}
+	for _, t := range transitions {
+		if t.To == status.StatusDone {
+			return true
+		}
+	}
+	return false
 }
 
-func runEpicDryRun(cmd *cobra.Command, app *App, executor *lifecycle.Executor, epicIDs []string) error {
+func runEpicDryRun(cmd *cobra.Command, app *App, executor *lifecycle.Executor, epicIDs []string, noRetro bool) error {
+	printDryRunProfile(app)
+
 	totalWorkflows := 0
 	storiesWithWork := 0
 	storiesComplete := 0
@@ -144,6 +321,10 @@ func runEpicDryRun(cmd *cobra.Command, app *App, executor *lifecycle.Executor, e
 		for _, storyKey := range storyKeys {
 			fmt.Printf("  Story %s:\n", storyKey)
 
+			if deps, err := app.StatusReader.GetStoryDependencies(storyKey); err == nil && len(deps) > 0 {
+				fmt.Printf("    (depends on: %s)\n", strings.Join(deps, ", "))
+			}
+
 			steps, err := executor.GetSteps(storyKey)
 			if err != nil {
 				if errors.Is(err, router.ErrStoryComplete) {
@@ -158,15 +339,21 @@ func runEpicDryRun(cmd *cobra.Command, app *App, executor *lifecycle.Executor, e
 
 			for i, step := range steps {
 				modelInfo := ""
-				model := app.Config.GetModel(step.Workflow)
-				if model != "" {
-					modelInfo = fmt.Sprintf(" (%s)", model)
+				models := app.Config.GetModels(step.Workflow)
+				if len(models) > 0 {
+					modelInfo = fmt.Sprintf(" (%s)", strings.Join(models, ", "))
 				}
 				fmt.Printf("    %d. %s%s → %s\n", i+1, step.Workflow, modelInfo, step.NextStatus)
 			}
 			totalWorkflows += len(steps)
 			storiesWithWork++
 		}
+
+		if !noRetro && app.Config.HasWorkflow(config.RetrospectiveWorkflow) {
+			if epic, err := app.StatusReader.GetEpic(epicID); err == nil && !retrospectiveDone(app, epic) {
+				fmt.Printf("  Retrospective: %s\n", config.RetrospectiveWorkflow)
+			}
+		}
 		fmt.Println()
 	}
 
@@ -178,3 +365,40 @@ func runEpicDryRun(cmd *cobra.Command, app *App, executor *lifecycle.Executor, e
 
 	return nil
 }
+
+// checkDependencies verifies that every dependency of a story is done.
+//
+// Stories already done are never blocked. A dependency counts as done if its
+// status in sprint-status.yaml is done or it completed earlier in this run.
+// Returns an error naming each unfinished or unknown dependency.
+func checkDependencies(reader StatusReader, storyKey string, completed map[string]bool) error {
+	if current, err := reader.GetStoryStatus(storyKey); err == nil && current == status.StatusDone {
+		return nil
+	}
+
+	deps, err := reader.GetStoryDependencies(storyKey)
+	if err != nil {
+		return err
+	}
+
+	var blocking []string
+	for _, dep := range deps {
+		if completed[dep] {
+			continue
+		}
+		depStatus, err := reader.GetStoryStatus(dep)
+		if err != nil {
+			blocking = append(blocking, fmt.Sprintf("%s (unknown story)", dep))
+			continue
+		}
+		if depStatus != status.StatusDone {
+			blocking = append(blocking, fmt.Sprintf("%s (%s)", dep, depStatus))
+		}
+	}
+
+	if len(blocking) > 0 {
+		return fmt.Errorf("blocked by unfinished dependencies: %s", strings.Join(blocking, ", "))
+	}
+
+	return nil
+}
@@ -456,3 +456,267 @@ func TestEpicCommand_All(t *testing.T) {
 // Note: Legacy tests removed - obsolete after lifecycle executor change.
 // The epic command now executes full lifecycle (multiple workflows per story), not single workflow routing.
 // See TestEpicCommand_FullLifecycleExecution for comprehensive lifecycle testing.
+
+func TestEpicCommand_Dependencies(t *testing.T) {
+	tests := []struct {
+		name              string
+		statusYAML        string
+		expectedWorkflows []string
+		expectedOrder     []string
+		expectError       bool
+	}{
+		{
+			name: "stories run after their dependencies",
+			statusYAML: `development_status:
+  6-1-first: review
+  6-2-second: review
+dependencies:
+  6-1: [6-2]`,
+			expectedWorkflows: []string{"code-review", "git-commit", "code-review", "git-commit"},
+			expectedOrder:     []string{"6-2-second", "6-1-first"},
+		},
+		{
+			name: "story blocked by unfinished cross-epic dependency",
+			statusYAML: `development_status:
+  5-1-lib: in-progress
+  6-1-first: review
+dependencies:
+  6-1: [5-1]`,
+			expectedWorkflows: nil,
+			expectError:       true,
+		},
+		{
+			name: "done cross-epic dependency does not block",
+			statusYAML: `development_status:
+  5-1-lib: done
+  6-1-first: review
+dependencies:
+  6-1: [5-1]`,
+			expectedWorkflows: []string{"code-review", "git-commit"},
+			expectedOrder:     []string{"6-1-first"},
+		},
+		{
+			name: "dependency cycle fails before running anything",
+			statusYAML: `development_status:
+  6-1-first: review
+  6-2-second: review
+dependencies:
+  6-1: [6-2]
+  6-2: [6-1]`,
+			expectedWorkflows: nil,
+			expectError:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			createSprintStatusFile(t, tmpDir, tt.statusYAML)
+
+			mockRunner := &MockWorkflowRunner{}
+			mockWriter := &MockStatusWriter{}
+			app := &App{
+				Config:       config.DefaultConfig(),
+				StatusReader: status.NewReader(tmpDir),
+				StatusWriter: mockWriter,
+				Runner:       mockRunner,
+				Printer:      output.NewPrinterWithWriter(&bytes.Buffer{}),
+			}
+
+			rootCmd := NewRootCommand(app)
+			outBuf := &bytes.Buffer{}
+			rootCmd.SetOut(outBuf)
+			rootCmd.SetErr(outBuf)
+			rootCmd.SetArgs([]string{"epic", "6"})
+
+			err := rootCmd.Execute()
+
+			if tt.expectError {
+				require.Error(t, err)
+				code, ok := IsExitError(err)
+				assert.True(t, ok, "error should be an ExitError")
+				assert.Equal(t, 1, code)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.expectedWorkflows, mockRunner.ExecutedWorkflows)
+
+			var order []string
+			for _, update := range mockWriter.Updates {
+				if len(order) == 0 || order[len(order)-1] != update.StoryKey {
+					order = append(order, update.StoryKey)
+				}
+			}
+			assert.Equal(t, tt.expectedOrder, order)
+		})
+	}
+}
+
+func TestEpicCommand_Retrospective(t *testing.T) {
+	tests := []struct {
+		name              string
+		statusYAML        string
+		configureRetro    bool
+		args              []string
+		expectedWorkflows []string
+		expectedStatuses  []StatusUpdate
+	}{
+		{
+			name: "retrospective runs after all stories and marks entry done",
+			statusYAML: `development_status:
+  epic-6: in-progress
+  6-1-first: review
+  6-2-second: done
+  epic-6-retrospective: optional`,
+			configureRetro:    true,
+			args:              []string{"epic", "6"},
+			expectedWorkflows: []string{"code-review", "git-commit", "retrospective"},
+			expectedStatuses: []StatusUpdate{
+				{StoryKey: "6-1-first", NewStatus: status.StatusDone},
+				{StoryKey: "6-1-first", NewStatus: status.StatusDone},
+				{StoryKey: "epic-6-retrospective", NewStatus: status.StatusDone},
+			},
+		},
+		{
+			name: "no retrospective workflow configured",
+			statusYAML: `development_status:
+  6-1-first: review
+  epic-6-retrospective: optional`,
+			args:              []string{"epic", "6"},
+			expectedWorkflows: []string{"code-review", "git-commit"},
+			expectedStatuses: []StatusUpdate{
+				{StoryKey: "6-1-first", NewStatus: status.StatusDone},
+				{StoryKey: "6-1-first", NewStatus: status.StatusDone},
+			},
+		},
+		{
+			name: "--no-retro skips retrospective",
+			statusYAML: `development_status:
+  6-1-first: done
+  epic-6-retrospective: optional`,
+			configureRetro:    true,
+			args:              []string{"epic", "--no-retro", "6"},
+			expectedWorkflows: nil,
+		},
+		{
+			name: "retrospective already done is not rerun",
+			statusYAML: `development_status:
+  6-1-first: done
+  epic-6-retrospective: done`,
+			configureRetro:    true,
+			args:              []string{"epic", "6"},
+			expectedWorkflows: nil,
+		},
+		{
+			name: "retrospective without entry runs but updates nothing",
+			statusYAML: `development_status:
+  6-1-first: done`,
+			configureRetro:    true,
+			args:              []string{"epic", "6"},
+			expectedWorkflows: []string{"retrospective"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			createSprintStatusFile(t, tmpDir, tt.statusYAML)
+
+			cfg := config.DefaultConfig()
+			if tt.configureRetro {
+				cfg.Workflows[config.RetrospectiveWorkflow] = config.WorkflowConfig{
+					PromptTemplate: "Retro for epic {{.EpicID}}",
+				}
+			}
+
+			mockRunner := &MockWorkflowRunner{}
+			mockWriter := &MockStatusWriter{}
+			app := &App{
+				Config:       cfg,
+				StatusReader: status.NewReader(tmpDir),
+				StatusWriter: mockWriter,
+				Runner:       mockRunner,
+				Printer:      output.NewPrinterWithWriter(&bytes.Buffer{}),
+			}
+
+			rootCmd := NewRootCommand(app)
+			outBuf := &bytes.Buffer{}
+			rootCmd.SetOut(outBuf)
+			rootCmd.SetErr(outBuf)
+			rootCmd.SetArgs(tt.args)
+
+			require.NoError(t, rootCmd.Execute())
+
+			assert.Equal(t, tt.expectedWorkflows, mockRunner.ExecutedWorkflows)
+			assert.Equal(t, tt.expectedStatuses, mockWriter.Updates)
+
+			for _, data := range mockRunner.WorkflowData {
+				assert.Equal(t, "6", data.EpicID)
+				assert.NotEmpty(t, data.Stories)
+			}
+		})
+	}
+}
+
+func TestEpicCommand_RetrospectiveWithoutEntryRunsOnce(t *testing.T) {
+	tmpDir := t.TempDir()
+	createSprintStatusFile(t, tmpDir, `development_status:
+  6-1-first: done`)
+
+	cfg := config.DefaultConfig()
+	cfg.Workflows[config.RetrospectiveWorkflow] = config.WorkflowConfig{PromptTemplate: "Retro"}
+
+	mockRunner := &MockWorkflowRunner{}
+	app := &App{
+		Config:       cfg,
+		StatusReader: status.NewReader(tmpDir),
+		StatusWriter: status.NewWriter(tmpDir),
+		Runner:       mockRunner,
+		Printer:      output.NewPrinterWithWriter(&bytes.Buffer{}),
+	}
+
+	for range 2 {
+		rootCmd := NewRootCommand(app)
+		rootCmd.SetOut(&bytes.Buffer{})
+		rootCmd.SetErr(&bytes.Buffer{})
+		rootCmd.SetArgs([]string{"epic", "6"})
+		require.NoError(t, rootCmd.Execute())
+	}
+
+	// The completion is recorded in the history, so the second run skips it
+	assert.Equal(t, []string{"retrospective"}, mockRunner.ExecutedWorkflows)
+}
+
+func TestEpicCommand_RetrospectiveFailure(t *testing.T) {
+	tmpDir := t.TempDir()
+	createSprintStatusFile(t, tmpDir, `development_status:
+  6-1-first: done
+  epic-6-retrospective: optional`)
+
+	cfg := config.DefaultConfig()
+	cfg.Workflows[config.RetrospectiveWorkflow] = config.WorkflowConfig{PromptTemplate: "Retro"}
+
+	mockRunner := &MockWorkflowRunner{FailOnWorkflow: config.RetrospectiveWorkflow}
+	mockWriter := &MockStatusWriter{}
+	app := &App{
+		Config:       cfg,
+		StatusReader: status.NewReader(tmpDir),
+		StatusWriter: mockWriter,
+		Runner:       mockRunner,
+		Printer:      output.NewPrinterWithWriter(&bytes.Buffer{}),
+	}
+
+	rootCmd := NewRootCommand(app)
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetErr(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"epic", "6"})
+
+	err := rootCmd.Execute()
+
+	require.Error(t, err)
+	code, ok := IsExitError(err)
+	assert.True(t, ok)
+	assert.Equal(t, 1, code)
+	assert.Empty(t, mockWriter.Updates, "retrospective entry should not be marked done on failure")
+}
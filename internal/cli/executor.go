@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"bmaduum/internal/config"
+	"bmaduum/internal/gate"
+	"bmaduum/internal/hook"
+	"bmaduum/internal/lifecycle"
+	"bmaduum/internal/output/core"
+	"bmaduum/internal/router"
+	"bmaduum/internal/status"
+	"bmaduum/internal/verify"
+)
+
+// newExecutor creates the lifecycle executor for story and epic runs.
+//
+// The executor uses the app's runner and status access, runs the lifecycle
+// configured in lifecycle.steps, verifies each step when [App.VerifySteps] is
+// set and git-commit alone when [App.VerifyCommits] is, runs the configured quality gates and hooks, and limits code review
+// cycles to lifecycle.max_review_cycles.
+//
+// Returns an error if lifecycle.steps is invalid.
+func newExecutor(app *App) (*lifecycle.Executor, error) {
+	steps, err := lifecycleSteps(app.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	executor := lifecycle.NewExecutor(app.Runner, app.StatusReader, app.StatusWriter)
+	executor.SetLifecycle(steps)
+	executor.SetMaxReviewCycles(app.Config.Lifecycle.MaxReviewCycles)
+	switch {
+	case app.VerifySteps:
+		executor.SetVerifier(verify.New(app.StatusReader, ""))
+	case app.VerifyCommits:
+		executor.SetVerifier(verify.NewCommitVerifier(""))
+	}
+	executor.SetGates(gate.NewChecker(app.Config, app.Runner, app.Printer, ""))
+	executor.SetHooks(hook.NewRunner(app.Config, app.Printer, "", app.RunID))
+	if app.Brancher != nil {
+		executor.SetBrancher(app.Brancher)
+	}
+	if app.Worktree != nil {
+		executor.SetFailurePolicy(lifecycle.FailurePolicy(app.Config.Lifecycle.FailurePolicy), app.Worktree)
+	}
+	if app.Checkpoints != nil {
+		executor.SetCheckpoints(app.Checkpoints)
+	}
+	return executor, nil
+}
+
+// lifecycleSteps converts lifecycle.steps to lifecycle steps. It returns no
+// steps, meaning the standard lifecycle, when none are configured.
+//
+// Returns an error if a step names a workflow that is not configured or a
+// next_status that is not a story status.
+func lifecycleSteps(cfg *config.Config) ([]router.LifecycleStep, error) {
+	steps := make([]router.LifecycleStep, 0, len(cfg.Lifecycle.Steps))
+	for i, s := range cfg.Lifecycle.Steps {
+		if !cfg.HasWorkflow(s.Workflow) {
+			return nil, fmt.Errorf("lifecycle.steps[%d]: unknown workflow %q (defined: %s)",
+				i, s.Workflow, strings.Join(cfg.WorkflowNames(), ", "))
+		}
+		next := status.Status(s.NextStatus)
+		if next != "" && !next.IsValidFor(status.EntryStory) {
+			return nil, fmt.Errorf("lifecycle.steps[%d]: invalid next_status %q for %s", i, s.NextStatus, s.Workflow)
+		}
+		steps = append(steps, router.LifecycleStep{Workflow: s.Workflow, NextStatus: next})
+	}
+	return steps, nil
+}
+
+// printCycleSummary prints the steps the executor ran for a story, also
+// when it failed. Nothing is printed if no step ran.
+//
+// Repeated dev-story and code-review runs are numbered by review cycle,
+// e.g. "code-review #2", and each step shows the model it ran on.
+func printCycleSummary(app *App, storyKey string, executor *lifecycle.Executor, duration time.Duration) {
+	results := executor.Results()
+	if len(results) == 0 {
+		return
+	}
+	steps := make([]core.StepResult, len(results))
+	for i, r := range results {
+		name := r.Workflow
+		if r.Cycle > 1 {
+			name = fmt.Sprintf("%s #%d", r.Workflow, r.Cycle)
+		}
+		if r.Model != "" {
+			name = fmt.Sprintf("%s (%s)", name, r.Model)
+		}
+		steps[i] = core.StepResult{Name: name, Duration: r.Duration, Success: r.Success}
+	}
+	app.Printer.CycleSummary(storyKey, steps, duration)
+}
+
+// printStoryFailure reports a failed story and where the failure policy put
+// its partial work.
+func printStoryFailure(storyKey string, executor *lifecycle.Executor, err error) {
+	fmt.Printf("Error running lifecycle for story %s: %v\n", storyKey, err)
+	if work := executor.PartialWork(); work != "" {
+		fmt.Printf("Partial work of story %s: %s\n", storyKey, work)
+	}
+}
@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// runPreflight runs the app's safety checks before a story or epic run.
+//
+// Problems refuse the run with an exit error, unless force is set, in which
+// case they are printed as warnings. Does nothing when [App.Preflight] is nil.
+func runPreflight(cmd *cobra.Command, app *App, force bool) error {
+	if app.Preflight == nil {
+		return nil
+	}
+
+	problems := app.Preflight.Check()
+	if len(problems) == 0 {
+		return nil
+	}
+
+	if force {
+		for _, problem := range problems {
+			fmt.Printf("Warning: %s (continuing because of --force)\n", problem)
+		}
+		return nil
+	}
+
+	cmd.SilenceUsage = true
+	fmt.Println("Refusing to start, preflight checks failed:")
+	for _, problem := range problems {
+		fmt.Printf("  - %s\n", problem)
+	}
+	fmt.Println("Fix these problems or use --force to run anyway.")
+	return NewExitError(1)
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bmaduum/internal/config"
+	"bmaduum/internal/output"
+	"bmaduum/internal/status"
+)
+
+func newPreflightTestApp(t *testing.T, problems []string) (*App, *MockWorkflowRunner, *MockPreflight) {
+	t.Helper()
+
+	tmpDir := t.TempDir()
+	createSprintStatusFile(t, tmpDir, `development_status:
+  epic-6: in-progress
+  6-1-first: review`)
+
+	runner := &MockWorkflowRunner{}
+	check := &MockPreflight{Problems: problems}
+	app := &App{
+		Config:       config.DefaultConfig(),
+		StatusReader: status.NewReader(tmpDir),
+		StatusWriter: &MockStatusWriter{},
+		Runner:       runner,
+		Printer:      output.NewPrinterWithWriter(&bytes.Buffer{}),
+		Preflight:    check,
+	}
+	return app, runner, check
+}
+
+func TestPreflight_RefusesToStart(t *testing.T) {
+	for _, args := range [][]string{{"story", "6-1-first"}, {"epic", "6"}} {
+		t.Run(args[0], func(t *testing.T) {
+			app, runner, check := newPreflightTestApp(t, []string{"on protected branch main; switch to a feature branch first"})
+
+			rootCmd := NewRootCommand(app)
+			rootCmd.SetArgs(args)
+			err := rootCmd.Execute()
+
+			require.Error(t, err)
+			code, ok := IsExitError(err)
+			require.True(t, ok)
+			assert.Equal(t, 1, code)
+			assert.Equal(t, 1, check.Calls)
+			assert.Empty(t, runner.ExecutedWorkflows)
+		})
+	}
+}
+
+func TestPreflight_Force(t *testing.T) {
+	for _, args := range [][]string{{"story", "--force", "6-1-first"}, {"epic", "--force", "6"}} {
+		t.Run(args[0], func(t *testing.T) {
+			app, runner, _ := newPreflightTestApp(t, []string{"working tree has uncommitted changes: main.go"})
+
+			rootCmd := NewRootCommand(app)
+			rootCmd.SetArgs(args)
+			err := rootCmd.Execute()
+
+			require.NoError(t, err)
+			assert.Equal(t, []string{"code-review", "git-commit"}, runner.ExecutedWorkflows)
+		})
+	}
+}
+
+func TestPreflight_SkippedForDryRun(t *testing.T) {
+	app, _, check := newPreflightTestApp(t, []string{"not a git repository, so the agent's changes could not be reviewed or reverted"})
+
+	rootCmd := NewRootCommand(app)
+	rootCmd.SetArgs([]string{"story", "--dry-run", "6-1-first"})
+	err := rootCmd.Execute()
+
+	require.NoError(t, err)
+	assert.Zero(t, check.Calls)
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"bmaduum/internal/branch"
+	"bmaduum/internal/preflight"
+	"bmaduum/internal/status"
+	"bmaduum/internal/worktree"
+)
+
+// UseProject switches the application to the project rooted at root.
+//
+// The process changes into root, like "git -C", so that the agent and every
+// relative path operate on that project. The status reader and writer, and
+// the worktree, preflight checks and story brancher if enabled, are rebuilt
+// for the project's sprint-status.yaml, honouring the status.path config key
+// and BMAD's output folder settings (see [status.ResolveStatusPath]).
+//
+// Returns an error if root is not a directory.
+func (a *App) UseProject(root string) error {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return fmt.Errorf("invalid project directory %s: %w", root, err)
+	}
+
+	if err := os.Chdir(absRoot); err != nil {
+		return fmt.Errorf("cannot use project directory %s: %w", root, err)
+	}
+
+	statusPath := status.ResolveStatusPath("", a.Config.Status.Path)
+	a.StatusReader = status.NewReaderWithPath("", statusPath)
+	writer := status.NewWriterWithPath("", statusPath)
+	writer.SetRunID(a.RunID)
+	a.StatusWriter = writer
+	if a.Preflight != nil {
+		a.Preflight = preflight.New(a.Config, statusPath)
+	}
+	if a.Brancher != nil {
+		a.Brancher = branch.NewManager(a.Config, a.Printer, statusPath)
+	}
+	if a.Worktree != nil {
+		a.Worktree = worktree.New(statusPath)
+	}
+	a.ProjectRoot = absRoot
+
+	return nil
+}
+
+// reloadConfig loads the configuration for the project at root through
+// [App.Loader], so that each project of an "epic --workspace" run uses its
+// own .bmaduum.yaml, applies the --var assignments again and rebuilds the
+// agent executor and workflow runner for it. Preflight checks and the story
+// brancher are enabled or disabled by the project's configuration;
+// [App.UseProject] then points them at the project's status file. Without a
+// Loader, the configuration is kept.
+//
+// Returns an error if the project's configuration cannot be loaded.
+func (a *App) reloadConfig(root string) error {
+	if a.Loader == nil {
+		return nil
+	}
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return fmt.Errorf("invalid project directory %s: %w", root, err)
+	}
+
+	a.Loader.SetProjectDir(absRoot)
+	cfg, err := a.Loader.Load()
+	if err != nil {
+		return fmt.Errorf("error loading config for %s: %w", root, err)
+	}
+	if err := cfg.SetVars(a.vars); err != nil {
+		return err
+	}
+	executor, runner, err := newAgentRunner(cfg, a.Printer)
+
+	a.Config = cfg
+	a.Executor, a.Runner, a.AgentErr = executor, runner, err
+	a.VerifySteps = cfg.Lifecycle.Verify
+	a.Preflight, a.Brancher = nil, nil
+	if cfg.Git.Preflight {
+		a.Preflight = preflight.New(cfg, "")
+	}
+	if cfg.Git.BranchPerStory {
+		a.Brancher = branch.NewManager(cfg, a.Printer, "")
+	}
+	return nil
+}
+
+// resolveInvocationPath resolves a relative path against the directory
+// bmaduum was started in, which may differ from the working directory after
+// project discovery changed into the project root.
+func (a *App) resolveInvocationPath(path string) string {
+	if filepath.IsAbs(path) || a.invocationDir == "" {
+		return path
+	}
+	return filepath.Join(a.invocationDir, path)
+}
@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bmaduum/internal/config"
+	"bmaduum/internal/output"
+	"bmaduum/internal/status"
+	"bmaduum/internal/workflow"
+)
+
+// newProjectTestApp builds an App whose status access is decided by --project.
+func newProjectTestApp(runner *MockWorkflowRunner) *App {
+	return &App{
+		Config:  config.DefaultConfig(),
+		Runner:  runner,
+		Printer: output.NewPrinterWithWriter(&bytes.Buffer{}),
+	}
+}
+
+func TestRootCommand_ProjectFlag(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	project := t.TempDir()
+	createSprintStatusFile(t, project, `development_status:
+  6-1-first: review`)
+
+	runner := &MockWorkflowRunner{}
+	app := newProjectTestApp(runner)
+
+	rootCmd := NewRootCommand(app)
+	rootCmd.SetArgs([]string{"-C", project, "story", "6-1-first"})
+
+	require.NoError(t, rootCmd.Execute())
+
+	assert.Equal(t, []string{"code-review", "git-commit"}, runner.ExecutedWorkflows)
+	resolved, err := filepath.EvalSymlinks(project)
+	require.NoError(t, err)
+	actual, err := filepath.EvalSymlinks(app.ProjectRoot)
+	require.NoError(t, err)
+	assert.Equal(t, resolved, actual)
+
+	s, err := status.NewReader(project).GetStoryStatus("6-1-first")
+	require.NoError(t, err)
+	assert.Equal(t, status.StatusDone, s, "status is written to the selected project")
+}
+
+func TestRootCommand_DiscoversProjectRoot(t *testing.T) {
+	project := t.TempDir()
+	createSprintStatusFile(t, project, `development_status:
+  6-1-first: review`)
+	nested := filepath.Join(project, "src", "pkg")
+	require.NoError(t, os.MkdirAll(nested, 0755))
+	t.Chdir(nested)
+
+	runner := &MockWorkflowRunner{}
+	app := newProjectTestApp(runner)
+	app.DiscoverProject = true
+
+	rootCmd := NewRootCommand(app)
+	rootCmd.SetArgs([]string{"story", "6-1-first"})
+
+	require.NoError(t, rootCmd.Execute())
+	assert.Equal(t, []string{"code-review", "git-commit"}, runner.ExecutedWorkflows)
+}
+
+func TestEpicCommand_Workspace(t *testing.T) {
+	workspaceDir := t.TempDir()
+	t.Chdir(workspaceDir)
+
+	for _, name := range []string{"service-a", "service-b"} {
+		createSprintStatusFile(t, filepath.Join(workspaceDir, name), `development_status:
+  1-1-first: review`)
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(workspaceDir, "workspace.yaml"),
+		[]byte("projects:\n  - service-a\n  - service-b\n"), 0644))
+
+	runner := &MockWorkflowRunner{}
+	app := newProjectTestApp(runner)
+
+	rootCmd := NewRootCommand(app)
+	rootCmd.SetArgs([]string{"epic", "all", "--workspace", "workspace.yaml"})
+
+	require.NoError(t, rootCmd.Execute())
+
+	assert.Equal(t, []string{"code-review", "git-commit", "code-review", "git-commit"}, runner.ExecutedWorkflows)
+	for _, name := range []string{"service-a", "service-b"} {
+		s, err := status.NewReader(filepath.Join(workspaceDir, name)).GetStoryStatus("1-1-first")
+		require.NoError(t, err)
+		assert.Equal(t, status.StatusDone, s, "%s should be done", name)
+	}
+}
+
+func TestApp_ReloadConfig(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("BMADUUM_CONFIG_PATH", "")
+	workspaceDir := t.TempDir()
+	t.Chdir(workspaceDir)
+
+	for name, cycles := range map[string]string{"service-a": "1", "service-b": "5"} {
+		dir := filepath.Join(workspaceDir, name)
+		createSprintStatusFile(t, dir, "development_status:\n  1-1-first: review")
+		require.NoError(t, os.WriteFile(filepath.Join(dir, config.ProjectConfigFile),
+			[]byte("lifecycle:\n  max_review_cycles: "+cycles+"\ngit:\n  preflight: false\n"), 0644))
+	}
+
+	app := newProjectTestApp(&MockWorkflowRunner{})
+	app.Loader = config.NewLoader()
+	app.vars = []string{"team=core"}
+
+	require.NoError(t, app.reloadConfig(filepath.Join(workspaceDir, "service-a")))
+	assert.Equal(t, 1, app.Config.Lifecycle.MaxReviewCycles)
+	assert.Equal(t, map[string]string{"team": "core"}, app.Config.Vars, "--var assignments are applied again")
+	assert.Nil(t, app.Preflight)
+
+	assert.NoError(t, app.AgentErr)
+	assert.IsType(t, &workflow.Runner{}, app.Runner, "the runner is rebuilt for the new config")
+
+	require.NoError(t, app.reloadConfig(filepath.Join(workspaceDir, "service-b")))
+	assert.Equal(t, 5, app.Config.Lifecycle.MaxReviewCycles)
+
+	require.NoError(t, os.WriteFile(filepath.Join(workspaceDir, "service-b", config.ProjectConfigFile),
+		[]byte("profile: missing\n"), 0644))
+	assert.ErrorContains(t, app.reloadConfig(filepath.Join(workspaceDir, "service-b")), "error loading config for")
+
+	app.Loader = nil
+	cfg := app.Config
+	require.NoError(t, app.reloadConfig(filepath.Join(workspaceDir, "service-a")))
+	assert.Same(t, cfg, app.Config, "without a loader the configuration is kept")
+}
+
+func TestRootCommand_VarFlag(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	project := t.TempDir()
+	createSprintStatusFile(t, project, `development_status:
+  6-1-first: review`)
+
+	app := newProjectTestApp(&MockWorkflowRunner{})
+	app.Config.Vars = map[string]string{"team": "core", "tone": "terse"}
+
+	rootCmd := NewRootCommand(app)
+	rootCmd.SetArgs([]string{"-C", project, "--var", "team=platform", "--var", "Ticket=PROJ-1", "story", "6-1-first"})
+	require.NoError(t, rootCmd.Execute())
+
+	assert.Equal(t, map[string]string{"team": "platform", "tone": "terse", "ticket": "PROJ-1"}, app.Config.Vars)
+
+	rootCmd = NewRootCommand(newProjectTestApp(&MockWorkflowRunner{}))
+	rootCmd.SetArgs([]string{"-C", project, "--var", "team", "story", "6-1-first"})
+	rootCmd.SilenceUsage = true
+	assert.ErrorContains(t, rootCmd.Execute(), "expected name=value")
+}
+
+func TestRootCommand_ProfileFlag(t *testing.T) {
+	app := newProjectTestApp(&MockWorkflowRunner{})
+	app.Config.Profile = "fast"
+
+	rootCmd := NewRootCommand(app)
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"--profile", "Fast", "config", "path"})
+	require.NoError(t, rootCmd.Execute())
+
+	// Profiles are applied by the loader; a config loaded without one is refused
+	rootCmd = NewRootCommand(app)
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"--profile", "thorough", "config", "path"})
+	rootCmd.SilenceUsage = true
+	assert.ErrorContains(t, rootCmd.Execute(), "profile thorough was not applied")
+}
@@ -17,6 +17,9 @@ Example:
   bmaduum raw "List all Go files in the project"`,
 		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAgent(cmd, app); err != nil {
+				return err
+			}
 			prompt := strings.Join(args, " ")
 			ctx := cmd.Context()
 			exitCode := app.Runner.RunRaw(ctx, prompt)
@@ -16,6 +16,8 @@
 //   - story - Execute full story lifecycle from current status to done (one or more stories)
 //   - epic - Run all stories in an epic (or all epics with "all")
 //   - raw - Execute a raw prompt directly
+//   - status - Show sprint status or a story's status history
+//   - config - Create, show, validate and locate the configuration
 //   - create-story, dev-story, code-review, git-commit - Individual workflow commands
 package cli
 
@@ -23,15 +25,22 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
+	"bmaduum/internal/agent"
+	"bmaduum/internal/branch"
 	"bmaduum/internal/claude"
 	"bmaduum/internal/config"
+	"bmaduum/internal/lifecycle"
 	"bmaduum/internal/output"
 	"bmaduum/internal/output/core"
+	"bmaduum/internal/preflight"
+	"bmaduum/internal/state"
 	"bmaduum/internal/status"
 	"bmaduum/internal/workflow"
+	"bmaduum/internal/worktree"
 )
 
 // WorkflowRunner is the interface for executing development workflows.
@@ -43,6 +52,7 @@ import (
 // Methods:
 //   - RunSingle executes a named workflow (e.g., "create-story", "dev-story")
 //     with a story key for template expansion
+//   - RunWorkflow executes a named workflow with arbitrary template data
 //   - RunRaw executes a raw prompt directly without workflow lookup
 //   - SetOperation sets the operation context for progress display
 type WorkflowRunner interface {
@@ -50,6 +60,11 @@ type WorkflowRunner interface {
 	// Returns 0 on success, non-zero on failure.
 	RunSingle(ctx context.Context, workflowName, storyKey string) int
 
+	// RunWorkflow executes the named workflow with arbitrary template data,
+	// e.g. the epic retrospective which receives the epic ID and its stories.
+	// Returns 0 on success, non-zero on failure.
+	RunWorkflow(ctx context.Context, workflowName string, data config.PromptData) int
+
 	// RunRaw executes a raw prompt directly without workflow lookup.
 	// Returns 0 on success, non-zero on failure.
 	RunRaw(ctx context.Context, prompt string) int
@@ -74,6 +89,22 @@ type StatusReader interface {
 
 	// GetAllEpics returns all epic IDs with active status, sorted numerically.
 	GetAllEpics() ([]string, error)
+
+	// GetStoryDependencies returns the story keys the given story depends on.
+	// Dependencies come from sprint-status.yaml and the story's markdown file.
+	GetStoryDependencies(storyKey string) ([]string, error)
+
+	// GetEpic returns the typed epic with its stories and retrospective entry.
+	GetEpic(epicID string) (*status.Epic, error)
+
+	// Read returns the complete parsed sprint status.
+	Read() (*status.SprintStatus, error)
+
+	// GetHistory returns the recorded status transitions of a story, oldest first.
+	GetHistory(storyKey string) ([]status.Transition, error)
+
+	// StoryFilePath returns the path of the story's markdown file.
+	StoryFilePath(storyKey string) (string, error)
 }
 
 // StatusWriter is the interface for updating story status in sprint-status.yaml.
@@ -86,6 +117,26 @@ type StatusWriter interface {
 	UpdateStatus(storyKey string, newStatus status.Status) error
 }
 
+// HistoryRecorder is implemented by status writers that can record a
+// transition for an entry sprint-status.yaml does not list.
+//
+// The production implementation is [status.Writer].
+type HistoryRecorder interface {
+	// RecordTransition appends a transition of key to newStatus, caused by
+	// workflow, to the status history.
+	RecordTransition(key string, newStatus status.Status, workflow string) error
+}
+
+// Preflight is the interface for the safety checks run before story and
+// epic start.
+//
+// The production implementation is [preflight.Checker].
+type Preflight interface {
+	// Check returns a description of each problem found, or nil if it is
+	// safe to start.
+	Check() []string
+}
+
 // App is the main application container with dependency injection.
 //
 // All dependencies are injected via struct fields, enabling comprehensive
@@ -95,18 +146,35 @@ type StatusWriter interface {
 //
 // Fields:
 //   - Config: Application configuration loaded from workflows.yaml and environment
-//   - Executor: Claude CLI executor for subprocess management
+//   - Executor: Agent backend executor for subprocess management
 //   - Printer: Terminal output formatter using Lipgloss styles
 //   - Runner: Workflow execution engine
 //   - StatusReader: Sprint status file reader
 //   - StatusWriter: Sprint status file writer
+//   - ProjectRoot: Project root selected with --project or auto-discovery
+//   - RunID: Identifier recorded with each status transition
+//   - Preflight: Safety checks run before story and epic start
+//   - Brancher: Story branch switching when git.branch_per_story is enabled
+//   - Worktree: Git working tree the failure policy stashes or resets
+//   - Checkpoints: Failed story state for resume
 type App struct {
 	// Config holds application configuration including workflow definitions.
 	Config *config.Config
 
-	// Executor runs Claude CLI as a subprocess and streams JSON events.
+	// Executor runs the agent backend (the Claude CLI by default) as a
+	// subprocess and streams its events. Nil when AgentErr is set.
 	Executor claude.Executor
 
+	// AgentErr is why the agent.backend executor could not be created.
+	// Commands that run the agent report it instead of running.
+	AgentErr error
+
+	// ConfigErr is why the configuration could not be loaded, in which case
+	// Config holds the defaults. Only the config subcommands run, so that
+	// the configuration can be checked and repaired; other commands report
+	// the error.
+	ConfigErr error
+
 	// Printer formats and displays output to the terminal.
 	Printer core.Printer
 
@@ -118,41 +186,145 @@ type App struct {
 
 	// StatusWriter updates story status in sprint-status.yaml.
 	StatusWriter StatusWriter
+
+	// ProjectRoot is the absolute project root selected with --project or
+	// auto-discovery. Empty means the current working directory.
+	ProjectRoot string
+
+	// RunID identifies this invocation in the status history.
+	RunID string
+
+	// VerifySteps enables post-step verification of story workflows (see
+	// [verify.Verifier]). [NewApp] sets it from the lifecycle.verify config key.
+	VerifySteps bool
+
+	// VerifyCommits checks that git-commit created a commit referencing the
+	// story key when VerifySteps is off (see [verify.NewCommitVerifier]).
+	// [NewApp] enables it.
+	VerifyCommits bool
+
+	// Preflight runs the git safety checks before story and epic start.
+	// Nil disables them. [NewApp] sets it unless git.preflight is false.
+	Preflight Preflight
+
+	// Brancher moves each story onto its own git branch. Nil keeps stories
+	// on the current branch. [NewApp] sets it when git.branch_per_story is true.
+	Brancher lifecycle.StoryBrancher
+
+	// Worktree is the git working tree the lifecycle.failure_policy stashes
+	// or resets when a story fails. Nil keeps partial work in place.
+	Worktree lifecycle.Worktree
+
+	// Checkpoints stores the state of failed stories in .bmad-state.json.
+	// Nil disables checkpoints.
+	Checkpoints lifecycle.CheckpointStore
+
+	// DiscoverProject enables walking up from the working directory to find
+	// the project root when --project is not given. [NewApp] enables it.
+	DiscoverProject bool
+
+	// Loader loaded Config. When set, [App.UseProject] reloads the
+	// configuration for every project it switches to. [Run] sets it.
+	Loader *config.Loader
+
+	// invocationDir is the working directory before any project switch, used
+	// to resolve relative paths given on the command line.
+	invocationDir string
+
+	// vars are the --var assignments, applied again when the configuration
+	// is reloaded.
+	vars []string
 }
 
 // NewApp creates a new [App] with all production dependencies wired up.
 //
 // This constructor initializes:
-//   - A [claude.Executor] configured from cfg.Claude settings
+//   - A [claude.Executor] for the agent.backend selected in cfg (see
+//     [agent.New]), the Claude CLI by default
 //   - A [workflow.Runner] for workflow execution
 //   - A [status.Reader] and [status.Writer] for sprint status management
 //   - A [core.Printer] for terminal output
+//   - A [preflight.Checker] unless git.preflight is disabled
+//
+// Project root auto-discovery is enabled; it runs when a command executes
+// (see [NewRootCommand]). If agent.backend names no registered backend, the
+// error is kept in [App.AgentErr] rather than failing every command.
 //
 // For testing, construct [App] directly with mock dependencies instead.
 func NewApp(cfg *config.Config) *App {
 	printer := output.NewPrinter()
 
-	executor := claude.NewExecutor(claude.ExecutorConfig{
-		BinaryPath:   cfg.Claude.BinaryPath,
-		OutputFormat: cfg.Claude.OutputFormat,
-		StderrHandler: func(line string) {
-			// Print stderr to stderr
-			os.Stderr.WriteString("[stderr] " + line + "\n")
-		},
+	executor, runner, agentErr := newAgentRunner(cfg, printer)
+	statusPath := status.ResolveStatusPath("", cfg.Status.Path)
+	statusReader := status.NewReaderWithPath("", statusPath)
+	statusWriter := status.NewWriterWithPath("", statusPath)
+	runID := status.NewRunID()
+	statusWriter.SetRunID(runID)
+
+	app := &App{
+		Config:          cfg,
+		Executor:        executor,
+		AgentErr:        agentErr,
+		Printer:         printer,
+		Runner:          runner,
+		StatusReader:    statusReader,
+		StatusWriter:    statusWriter,
+		RunID:           runID,
+		VerifySteps:     cfg.Lifecycle.Verify,
+		VerifyCommits:   true,
+		DiscoverProject: true,
+	}
+	if cfg.Git.Preflight {
+		app.Preflight = preflight.New(cfg, statusPath)
+	}
+	if cfg.Git.BranchPerStory {
+		app.Brancher = branch.NewManager(cfg, printer, statusPath)
+	}
+	app.Worktree = worktree.New(statusPath)
+	app.Checkpoints = state.NewManager(".")
+
+	return app
+}
+
+// newAgentRunner creates the agent executor for cfg and a workflow runner
+// on it. Lines the agent writes to stderr are printed and passed to the
+// runner, so a limit reported there triggers model fallback.
+func newAgentRunner(cfg *config.Config, printer core.Printer) (claude.Executor, *workflow.Runner, error) {
+	var runner *workflow.Runner
+	executor, err := agent.New(cfg, func(line string) {
+		printAgentStderr(line)
+		runner.HandleStderr(line)
 	})
+	runner = workflow.NewRunner(executor, printer, cfg)
+	return executor, runner, err
+}
 
-	runner := workflow.NewRunner(executor, printer, cfg)
-	statusReader := status.NewReader("")
-	statusWriter := status.NewWriter("")
-
-	return &App{
-		Config:       cfg,
-		Executor:     executor,
-		Printer:      printer,
-		Runner:       runner,
-		StatusReader: statusReader,
-		StatusWriter: statusWriter,
+// printAgentStderr prints a line the agent wrote to stderr to our stderr.
+func printAgentStderr(line string) {
+	os.Stderr.WriteString("[stderr] " + line + "\n")
+}
+
+// requireAgent reports [App.AgentErr] for commands that run the agent. It
+// returns the error the command should return, or nil if the agent backend
+// is available.
+func requireAgent(cmd *cobra.Command, app *App) error {
+	if app.AgentErr == nil {
+		return nil
 	}
+	cmd.SilenceUsage = true
+	fmt.Printf("Error: %v\n", app.AgentErr)
+	return NewExitError(1)
+}
+
+// isConfigCommand reports whether cmd is the config command or one of its
+// subcommands.
+func isConfigCommand(cmd *cobra.Command) bool {
+	for ; cmd.HasParent(); cmd = cmd.Parent() {
+		if cmd.Name() == "config" && !cmd.Parent().HasParent() {
+			return true
+		}
+	}
+	return false
 }
 
 // NewRootCommand creates the root Cobra command with all subcommands attached.
@@ -162,22 +334,66 @@ func NewApp(cfg *config.Config) *App {
 //   - epic: Run all stories in an epic (or all epics)
 //   - raw: Execute a raw prompt directly
 //   - workflow: Run individual BMAD workflow steps (advanced)
+//   - status: Show sprint status or a story's status history
+//   - config: Create, show, validate and locate the configuration
 func NewRootCommand(app *App) *cobra.Command {
+	var projectDir string
+	var profile string
+	var vars []string
+
 	rootCmd := &cobra.Command{
 		Use:   "bmaduum",
 		Short: "BMAD Automation CLI",
 		Long: `BMAD Automation CLI - Automate development workflows with Claude.
 
 This tool orchestrates Claude to run development workflows including
-story creation, development, code review, and git operations.`,
+story creation, development, code review, and git operations.
+
+bmaduum works from any directory inside a BMAD project: it walks up to the
+directory containing _bmad or the sprint status file. Use --project (-C) to
+run against another project.`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if app.ConfigErr != nil {
+				// The config commands help fix a configuration that does not load
+				if !isConfigCommand(cmd) {
+					cmd.SilenceUsage = true
+					return app.ConfigErr
+				}
+			} else if profile != "" && !strings.EqualFold(profile, app.Config.Profile) {
+				// Profiles are applied while loading, see Run
+				return fmt.Errorf("profile %s was not applied when the configuration was loaded", profile)
+			}
+			if err := app.Config.SetVars(vars); err != nil {
+				return err
+			}
+			app.vars = vars
+			if wd, err := os.Getwd(); err == nil {
+				app.invocationDir = wd
+			}
+			if projectDir != "" {
+				return app.UseProject(projectDir)
+			}
+			if app.DiscoverProject {
+				if root, err := status.FindProjectRoot("."); err == nil {
+					return app.UseProject(root)
+				}
+			}
+			return nil
+		},
 	}
 
+	rootCmd.PersistentFlags().StringVarP(&projectDir, "project", "C", "", "Run as if started in this project directory")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "Use a configuration profile from profiles (or set BMADUUM_PROFILE)")
+	rootCmd.PersistentFlags().StringArrayVar(&vars, "var", nil, "Set a prompt template variable as name=value, available as {{.Vars.name}} (repeatable)")
+
 	// Add subcommands
 	rootCmd.AddCommand(
 		newStoryCommand(app),
 		newEpicCommand(app),
 		newRawCommand(app),
 		newWorkflowCommand(app),
+		newStatusCommand(app),
+		newConfigCommand(app),
 		newVersionCommand(),
 	)
 
@@ -205,10 +421,19 @@ type ExecuteResult struct {
 //
 // Exit codes:
 //   - 0: Success
-//   - 1: Config or command error
+//   - 1: Config, agent backend or command error
 //   - Non-zero from subprocess: Passed through from Claude CLI
 func RunWithConfig(cfg *config.Config) ExecuteResult {
+	return run(cfg, nil, nil)
+}
+
+// run creates the app for cfg, loaded by loader if it is not nil, and
+// executes the root command. loadErr is the error loading the configuration
+// failed with, in which case cfg holds the defaults.
+func run(cfg *config.Config, loader *config.Loader, loadErr error) ExecuteResult {
 	app := NewApp(cfg)
+	app.Loader = loader
+	app.ConfigErr = loadErr
 	rootCmd := NewRootCommand(app)
 
 	if err := rootCmd.Execute(); err != nil {
@@ -225,20 +450,57 @@ func RunWithConfig(cfg *config.Config) ExecuteResult {
 // Run loads configuration and executes the CLI, returning the result.
 //
 // This is the fully testable entry point that:
-//  1. Loads configuration via [config.NewLoader]
-//  2. Calls [RunWithConfig] with the loaded config
+//  1. Loads configuration via [config.NewLoader], looking up the project
+//     config from the --project directory and applying the --profile
+//     profile if they are given
+//  2. Runs the CLI like [RunWithConfig] with the loaded config, keeping the
+//     loader so that each project of an "epic --workspace" run is run with
+//     its own configuration
+//
+// A configuration that cannot be loaded only fails commands other than
+// config, which run with the defaults and report the error (see
+// [App.ConfigErr]).
 //
 // Use this for integration tests that need to test config loading.
 // For unit tests with custom configs, use [RunWithConfig] directly.
 func Run() ExecuteResult {
-	cfg, err := config.NewLoader().Load()
+	loader := config.NewLoader()
+	// The project config and profile are needed before flags are parsed
+	loader.SetProjectDir(flagValue(os.Args[1:], "project", "C"))
+	loader.SetProfile(flagValue(os.Args[1:], "profile", ""))
+	cfg, err := loader.Load()
 	if err != nil {
-		return ExecuteResult{
-			ExitCode: 1,
-			Err:      fmt.Errorf("error loading config: %w", err),
+		cfg = config.DefaultConfig()
+		err = fmt.Errorf("error loading config: %w", err)
+	}
+	return run(cfg, loader, err)
+}
+
+// flagValue returns the value of the string flag --name (or -short, when
+// short is set) in args, or an empty string if it is not given. It reads the
+// flags config loading depends on before the command line is parsed.
+//
+// The flag is recognised as "--name value", "--name=value", "-short value",
+// "-short=value" or "-shortvalue", as cobra parses it; longer flags that
+// merely start with the same letters are not. Arguments after "--" are not
+// flags.
+func flagValue(args []string, name, short string) string {
+	for i, arg := range args {
+		switch {
+		case arg == "--":
+			return ""
+		case arg == "--"+name || (short != "" && arg == "-"+short):
+			if i+1 < len(args) {
+				return args[i+1]
+			}
+			return ""
+		case strings.HasPrefix(arg, "--"+name+"="):
+			return strings.TrimPrefix(arg, "--"+name+"=")
+		case short != "" && !strings.HasPrefix(arg, "--") && strings.HasPrefix(arg, "-"+short):
+			return strings.TrimPrefix(arg[1+len(short):], "=")
 		}
 	}
-	return RunWithConfig(cfg)
+	return ""
 }
 
 // Execute runs the CLI application and exits the process.
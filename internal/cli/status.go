@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"bmaduum/internal/status"
+)
+
+func newStatusCommand(app *App) *cobra.Command {
+	var historyKey string
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show sprint status or a story's status history",
+		Long: `Show the current status of every epic and story in sprint-status.yaml.
+
+Every status update made by bmaduum is recorded in sprint-status.history.jsonl
+next to the status file, with the previous and new status, timestamp,
+workflow, the model it ran on, run ID and git HEAD. Use --history to show a story's timeline,
+including how long it spent in each status.
+
+Examples:
+  bmaduum status
+  bmaduum status --history 6-1`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if historyKey != "" {
+				return runStatusHistory(cmd, app, historyKey)
+			}
+
+			sprintStatus, err := app.StatusReader.Read()
+			if err != nil {
+				cmd.SilenceUsage = true
+				fmt.Printf("Error: %v\n", err)
+				return NewExitError(1)
+			}
+
+			out := cmd.OutOrStdout()
+			for _, epic := range sprintStatus.Epics() {
+				epicStatus := epic.Status
+				if epicStatus == "" {
+					epicStatus = status.DeriveEpicStatus(storyStatuses(epic.Stories))
+				}
+				fmt.Fprintf(out, "Epic %s (%s)\n", epic.ID, epicStatus)
+
+				tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+				for _, story := range epic.Stories {
+					fmt.Fprintf(tw, "  %s\t%s\n", story.Key, story.Status)
+				}
+				if epic.Retrospective != nil {
+					fmt.Fprintf(tw, "  %s\t%s\n", epic.Retrospective.Key, epic.Retrospective.Status)
+				}
+				tw.Flush()
+				fmt.Fprintln(out)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&historyKey, "history", "", "Show the status timeline of a story")
+
+	return cmd
+}
+
+// runStatusHistory prints the recorded status transitions of a story.
+func runStatusHistory(cmd *cobra.Command, app *App, storyKey string) error {
+	transitions, err := app.StatusReader.GetHistory(storyKey)
+	if err != nil {
+		cmd.SilenceUsage = true
+		fmt.Printf("Error: %v\n", err)
+		return NewExitError(1)
+	}
+
+	out := cmd.OutOrStdout()
+	if len(transitions) == 0 {
+		fmt.Fprintf(out, "No status history recorded for %s\n", storyKey)
+		return nil
+	}
+
+	fmt.Fprintf(out, "History for %s:\n", transitions[0].Key)
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for i, t := range transitions {
+		// Time spent in the previous status
+		elapsed := ""
+		if i > 0 {
+			elapsed = "+" + t.Time.Sub(transitions[i-1].Time).Round(time.Second).String()
+		}
+
+		head := t.GitHead
+		if len(head) > 7 {
+			head = head[:7]
+		}
+
+		workflow := t.Workflow
+		if t.Model != "" {
+			workflow = fmt.Sprintf("%s (%s)", t.Workflow, t.Model)
+		}
+
+		fmt.Fprintf(tw, "  %s\t%s → %s\t%s\t%s\t%s\t%s\n",
+			t.Time.Local().Format("2006-01-02 15:04:05"), t.From, t.To, workflow, elapsed, head, t.RunID)
+	}
+	tw.Flush()
+
+	total := transitions[len(transitions)-1].Time.Sub(transitions[0].Time).Round(time.Second)
+	fmt.Fprintf(out, "%d transition(s) over %s\n", len(transitions), total)
+
+	return nil
+}
+
+// storyStatuses returns the statuses of the given stories.
+func storyStatuses(stories []status.Story) []status.Status {
+	statuses := make([]status.Status, len(stories))
+	for i, s := range stories {
+		statuses[i] = s.Status
+	}
+	return statuses
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bmaduum/internal/config"
+	"bmaduum/internal/output"
+	"bmaduum/internal/status"
+)
+
+// newStatusTestApp builds an App reading and writing the status file in tmpDir.
+func newStatusTestApp(tmpDir string) *App {
+	return &App{
+		Config:       config.DefaultConfig(),
+		StatusReader: status.NewReader(tmpDir),
+		StatusWriter: status.NewWriter(tmpDir),
+		Runner:       &MockWorkflowRunner{},
+		Printer:      output.NewPrinterWithWriter(&bytes.Buffer{}),
+	}
+}
+
+func TestStatusCommand_Overview(t *testing.T) {
+	tmpDir := t.TempDir()
+	createSprintStatusFile(t, tmpDir, `development_status:
+  epic-6: in-progress
+  6-1-first: done
+  6-2-second: review
+  epic-6-retrospective: optional
+  7-1-other: backlog`)
+
+	buf := &bytes.Buffer{}
+	rootCmd := NewRootCommand(newStatusTestApp(tmpDir))
+	rootCmd.SetOut(buf)
+	rootCmd.SetArgs([]string{"status"})
+
+	require.NoError(t, rootCmd.Execute())
+
+	out := buf.String()
+	assert.Contains(t, out, "Epic 6 (in-progress)")
+	assert.Regexp(t, `6-1-first\s+done`, out)
+	assert.Regexp(t, `6-2-second\s+review`, out)
+	assert.Regexp(t, `epic-6-retrospective\s+optional`, out)
+	// Epic without its own entry shows the derived status
+	assert.Contains(t, out, "Epic 7 (backlog)")
+}
+
+func TestStatusCommand_History(t *testing.T) {
+	tmpDir := t.TempDir()
+	createSprintStatusFile(t, tmpDir, `development_status:
+  6-1-first: backlog
+  6-2-second: backlog`)
+
+	writer := status.NewWriter(tmpDir)
+	writer.SetRunID("run-1")
+	require.NoError(t, writer.UpdateStatusForWorkflow("6-1-first", status.StatusReadyForDev, "create-story", ""))
+	require.NoError(t, writer.UpdateStatusForWorkflow("6-1-first", status.StatusReview, "dev-story", "opus"))
+	require.NoError(t, writer.UpdateStatusForWorkflow("6-2-second", status.StatusReadyForDev, "create-story", ""))
+
+	buf := &bytes.Buffer{}
+	rootCmd := NewRootCommand(newStatusTestApp(tmpDir))
+	rootCmd.SetOut(buf)
+	rootCmd.SetArgs([]string{"status", "--history", "6-1"})
+
+	require.NoError(t, rootCmd.Execute())
+
+	out := buf.String()
+	assert.Contains(t, out, "History for 6-1-first:")
+	assert.Regexp(t, `backlog → ready-for-dev\s+create-story`, out)
+	assert.Regexp(t, `ready-for-dev → review\s+dev-story \(opus\)\s+\+\d+s`, out)
+	assert.Contains(t, out, "run-1")
+	assert.Contains(t, out, "2 transition(s)")
+	assert.NotContains(t, out, "6-2-second")
+}
+
+func TestStatusCommand_HistoryEmpty(t *testing.T) {
+	tmpDir := t.TempDir()
+	createSprintStatusFile(t, tmpDir, `development_status:
+  6-1-first: backlog`)
+
+	buf := &bytes.Buffer{}
+	rootCmd := NewRootCommand(newStatusTestApp(tmpDir))
+	rootCmd.SetOut(buf)
+	rootCmd.SetArgs([]string{"status", "--history", "6-1-first"})
+
+	require.NoError(t, rootCmd.Execute())
+	assert.Contains(t, buf.String(), "No status history recorded for 6-1-first")
+}
@@ -3,9 +3,12 @@ package cli
 import (
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"bmaduum/internal/config"
 	"bmaduum/internal/lifecycle"
 	"bmaduum/internal/router"
 )
@@ -13,6 +16,8 @@ import (
 func newStoryCommand(app *App) *cobra.Command {
 	var dryRun bool
 	var autoRetry bool
+	var force bool
+	var noPush bool
 
 	cmd := &cobra.Command{
 		Use:   "story <story-key> [story-key...]",
@@ -28,12 +33,24 @@ For each story, executes all remaining workflows based on its current status:
   - review        → code-review → git-commit → done
   - done          → skipped (story already complete)
 
+If code-review requests changes, the story goes back through dev-story and
+code-review until a review comes back clean (up to lifecycle.max_review_cycles).
+
 The command stops on the first failure. Done stories are skipped and do not cause failure.
 Status is updated in sprint-status.yaml after each successful workflow.
 
 Use --dry-run to preview workflows without executing them.
 Use --auto-retry to automatically retry on rate limit errors.
 
+Before starting, preflight checks refuse to run on a dirty working tree
+(sprint status changes are allowed), a protected branch (git.protected_branches),
+during a rebase or merge, or without a resolvable claude binary.
+Use --force to run anyway.
+
+git-commit must create a commit referencing the story key. With
+git.commit_mode: native, bmaduum stages and commits itself and only asks
+Claude for the commit message. Use --no-push to commit without pushing.
+
 Examples:
   bmaduum story 6-1
   bmaduum story 6-1 6-2 6-3`,
@@ -41,15 +58,32 @@ Examples:
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			storyKeys := args
+			if noPush {
+				app.Config.Git.Push = false
+				app.Config.SetOrigin("git.push", config.OriginFlag+" --no-push")
+			}
 
 			// Create lifecycle executor with app dependencies
-			executor := lifecycle.NewExecutor(app.Runner, app.StatusReader, app.StatusWriter)
+			executor, err := newExecutor(app)
+			if err != nil {
+				cmd.SilenceUsage = true
+				fmt.Printf("Error: %v\n", err)
+				return NewExitError(1)
+			}
 
 			// Handle dry-run mode
 			if dryRun {
 				return runStoryDryRun(cmd, app, executor, storyKeys)
 			}
 
+			if err := requireAgent(cmd, app); err != nil {
+				return err
+			}
+
+			if err := runPreflight(cmd, app, force); err != nil {
+				return err
+			}
+
 			// Execute full lifecycle for each story in order
 			for i, storyKey := range storyKeys {
 				// Set operation context for progress display
@@ -61,6 +95,7 @@ Examples:
 					app.Runner.SetOperation(fmt.Sprintf("Story %s", storyKey))
 				}
 
+				start := time.Now()
 				err := executeWithRetry(ctx, executor, storyKey, autoRetry, 10, func(stepIndex, totalSteps int, workflow string) {
 					app.Printer.StepStart(stepIndex, totalSteps, workflow)
 				})
@@ -70,10 +105,13 @@ Examples:
 						fmt.Printf("Story %s is already complete, skipping\n", storyKey)
 						continue
 					}
-					fmt.Printf("Error running lifecycle for story %s: %v\n", storyKey, err)
+					printCycleSummary(app, storyKey, executor, time.Since(start))
+					printStoryFailure(storyKey, executor, err)
 					return NewExitError(1)
 				}
 
+				printCycleSummary(app, storyKey, executor, time.Since(start))
+
 				// Show completion message
 				if len(storyKeys) > 1 {
 					fmt.Printf("Story %s completed successfully\n\n", storyKey)
@@ -90,11 +128,23 @@ Examples:
 
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview workflows without executing them")
 	cmd.Flags().BoolVar(&autoRetry, "auto-retry", false, "Automatically retry on rate limit errors")
+	cmd.Flags().BoolVar(&force, "force", false, "Run even if the preflight safety checks fail")
+	cmd.Flags().BoolVar(&noPush, "no-push", false, "Commit stories without pushing them")
 
 	return cmd
 }
 
+// printDryRunProfile prints the active configuration profile, if any, at
+// the top of dry-run output.
+func printDryRunProfile(app *App) {
+	if app.Config.Profile != "" {
+		fmt.Printf("Profile: %s\n", app.Config.Profile)
+	}
+}
+
 func runStoryDryRun(cmd *cobra.Command, app *App, executor *lifecycle.Executor, storyKeys []string) error {
+	printDryRunProfile(app)
+
 	// Single story dry-run - simpler output
 	if len(storyKeys) == 1 {
 		storyKey := storyKeys[0]
@@ -112,9 +162,9 @@ func runStoryDryRun(cmd *cobra.Command, app *App, executor *lifecycle.Executor,
 		fmt.Printf("Dry run for story %s:\n", storyKey)
 		for i, step := range steps {
 			modelInfo := ""
-			model := app.Config.GetModel(step.Workflow)
-			if model != "" {
-				modelInfo = fmt.Sprintf(" (%s)", model)
+			models := app.Config.GetModels(step.Workflow)
+			if len(models) > 0 {
+				modelInfo = fmt.Sprintf(" (%s)", strings.Join(models, ", "))
 			}
 			fmt.Printf("  %d. %s%s → %s\n", i+1, step.Workflow, modelInfo, step.NextStatus)
 		}
@@ -146,9 +196,9 @@ func runStoryDryRun(cmd *cobra.Command, app *App, executor *lifecycle.Executor,
 
 		for i, step := range steps {
 			modelInfo := ""
-			model := app.Config.GetModel(step.Workflow)
-			if model != "" {
-				modelInfo = fmt.Sprintf(" (%s)", model)
+			models := app.Config.GetModels(step.Workflow)
+			if len(models) > 0 {
+				modelInfo = fmt.Sprintf(" (%s)", strings.Join(models, ", "))
 			}
 			fmt.Printf("  %d. %s%s → %s\n", i+1, step.Workflow, modelInfo, step.NextStatus)
 		}
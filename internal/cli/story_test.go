@@ -2,6 +2,7 @@ package cli
 
 import (
 	"bytes"
+	"os/exec"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -288,3 +289,326 @@ func TestStoryCommand_DryRun(t *testing.T) {
 		})
 	}
 }
+
+func TestStoryCommand_VerificationFailure(t *testing.T) {
+	tmpDir := t.TempDir()
+	createSprintStatusFile(t, tmpDir, `development_status:
+  6-1-first: backlog`)
+
+	runner := &MockWorkflowRunner{}
+	statusWriter := &MockStatusWriter{}
+	app := &App{
+		Config:       config.DefaultConfig(),
+		StatusReader: status.NewReader(tmpDir),
+		StatusWriter: statusWriter,
+		Runner:       runner,
+		Printer:      output.NewPrinterWithWriter(&bytes.Buffer{}),
+		VerifySteps:  true,
+	}
+
+	rootCmd := NewRootCommand(app)
+	rootCmd.SetArgs([]string{"story", "6-1-first"})
+	err := rootCmd.Execute()
+
+	// create-story "succeeded" without writing the story file
+	require.Error(t, err)
+	code, ok := IsExitError(err)
+	require.True(t, ok)
+	assert.Equal(t, 1, code)
+	assert.Equal(t, []string{"create-story"}, runner.ExecutedWorkflows)
+	assert.Empty(t, statusWriter.Updates)
+}
+
+func TestStoryCommand_CommitVerification(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	tmpDir := t.TempDir()
+	createSprintStatusFile(t, tmpDir, `development_status:
+  6-1-first: review`)
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "Test"},
+		{"add", "-A"},
+		{"commit", "-q", "-m", "initial"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = tmpDir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	t.Chdir(tmpDir)
+
+	runner := &MockWorkflowRunner{}
+	app := &App{
+		Config:        config.DefaultConfig(),
+		StatusReader:  status.NewReader(tmpDir),
+		StatusWriter:  &MockStatusWriter{},
+		Runner:        runner,
+		Printer:       output.NewPrinterWithWriter(&bytes.Buffer{}),
+		VerifyCommits: true,
+	}
+	require.False(t, app.Config.Lifecycle.Verify)
+
+	rootCmd := NewRootCommand(app)
+	rootCmd.SetArgs([]string{"story", "6-1-first"})
+	err := rootCmd.Execute()
+
+	// git-commit "succeeded" without committing, which fails the story even
+	// though lifecycle.verify is off
+	require.Error(t, err)
+	code, ok := IsExitError(err)
+	require.True(t, ok)
+	assert.Equal(t, 1, code)
+	assert.Equal(t, []string{"code-review", "git-commit"}, runner.ExecutedWorkflows)
+}
+
+func TestStoryCommand_GateFailure(t *testing.T) {
+	tmpDir := t.TempDir()
+	createSprintStatusFile(t, tmpDir, `development_status:
+  6-1-first: ready-for-dev`)
+
+	cfg := config.DefaultConfig()
+	devStory := cfg.Workflows["dev-story"]
+	devStory.Gates = []config.GateConfig{{Name: "tests", Command: "exit 1"}}
+	devStory.FixAttempts = 1
+	cfg.Workflows["dev-story"] = devStory
+
+	runner := &MockWorkflowRunner{}
+	statusWriter := &MockStatusWriter{}
+	app := &App{
+		Config:       cfg,
+		StatusReader: status.NewReader(tmpDir),
+		StatusWriter: statusWriter,
+		Runner:       runner,
+		Printer:      output.NewPrinterWithWriter(&bytes.Buffer{}),
+	}
+
+	rootCmd := NewRootCommand(app)
+	rootCmd.SetArgs([]string{"story", "6-1-first"})
+	err := rootCmd.Execute()
+
+	// The gate keeps failing, so one fix session runs and the story stays put
+	require.Error(t, err)
+	code, ok := IsExitError(err)
+	require.True(t, ok)
+	assert.Equal(t, 1, code)
+	assert.Equal(t, []string{"dev-story", config.FixGatesWorkflow}, runner.ExecutedWorkflows)
+	assert.Empty(t, statusWriter.Updates)
+}
+
+func TestStoryCommand_ReviewCycles(t *testing.T) {
+	tmpDir := t.TempDir()
+	createSprintStatusFile(t, tmpDir, `development_status:
+  6-1-first: review`)
+
+	runner := &MockWorkflowRunner{Messages: map[string][]string{
+		"code-review": {"REVIEW_RESULT: CHANGES_REQUESTED", "REVIEW_RESULT: APPROVED"},
+	}}
+	statusWriter := &MockStatusWriter{}
+	buf := &bytes.Buffer{}
+	app := &App{
+		Config:       config.DefaultConfig(),
+		StatusReader: status.NewReader(tmpDir),
+		StatusWriter: statusWriter,
+		Runner:       runner,
+		Printer:      output.NewPrinterWithWriter(buf),
+	}
+
+	rootCmd := NewRootCommand(app)
+	rootCmd.SetArgs([]string{"story", "6-1-first"})
+	err := rootCmd.Execute()
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"code-review", "dev-story", "code-review", "git-commit"}, runner.ExecutedWorkflows)
+	assert.Equal(t, status.StatusInProgress, statusWriter.Updates[0].NewStatus)
+	assert.Contains(t, buf.String(), "CYCLE COMPLETE")
+	assert.Contains(t, buf.String(), "dev-story #2")
+	assert.Contains(t, buf.String(), "code-review #2")
+}
+
+func TestStoryCommand_ReviewCyclesExhausted(t *testing.T) {
+	tmpDir := t.TempDir()
+	createSprintStatusFile(t, tmpDir, `development_status:
+  6-1-first: review`)
+
+	cfg := config.DefaultConfig()
+	cfg.Lifecycle.MaxReviewCycles = 1
+	runner := &MockWorkflowRunner{Messages: map[string][]string{
+		"code-review": {"REVIEW_RESULT: CHANGES_REQUESTED"},
+	}}
+	statusWriter := &MockStatusWriter{}
+	buf := &bytes.Buffer{}
+	app := &App{
+		Config:       cfg,
+		StatusReader: status.NewReader(tmpDir),
+		StatusWriter: statusWriter,
+		Runner:       runner,
+		Printer:      output.NewPrinterWithWriter(buf),
+	}
+
+	rootCmd := NewRootCommand(app)
+	rootCmd.SetArgs([]string{"story", "6-1-first"})
+	err := rootCmd.Execute()
+
+	require.Error(t, err)
+	code, ok := IsExitError(err)
+	require.True(t, ok)
+	assert.Equal(t, 1, code)
+	assert.Equal(t, []string{"code-review"}, runner.ExecutedWorkflows)
+	assert.Equal(t, []StatusUpdate{{StoryKey: "6-1-first", NewStatus: status.StatusInProgress}}, statusWriter.Updates)
+	assert.Contains(t, buf.String(), "CYCLE FAILED", "the cycle summary is shown for failed stories")
+	assert.Contains(t, buf.String(), "code-review")
+}
+
+func TestStoryCommand_PreHookFailure(t *testing.T) {
+	tmpDir := t.TempDir()
+	createSprintStatusFile(t, tmpDir, `development_status:
+  6-1-first: ready-for-dev`)
+
+	cfg := config.DefaultConfig()
+	devStory := cfg.Workflows["dev-story"]
+	devStory.Hooks.Pre = []config.HookConfig{{Name: "database", Command: "exit 1"}}
+	cfg.Workflows["dev-story"] = devStory
+
+	runner := &MockWorkflowRunner{}
+	statusWriter := &MockStatusWriter{}
+	app := &App{
+		Config:       cfg,
+		StatusReader: status.NewReader(tmpDir),
+		StatusWriter: statusWriter,
+		Runner:       runner,
+		Printer:      output.NewPrinterWithWriter(&bytes.Buffer{}),
+	}
+
+	rootCmd := NewRootCommand(app)
+	rootCmd.SetArgs([]string{"story", "6-1-first"})
+	err := rootCmd.Execute()
+
+	require.Error(t, err)
+	code, ok := IsExitError(err)
+	require.True(t, ok)
+	assert.Equal(t, 1, code)
+	assert.Empty(t, runner.ExecutedWorkflows)
+	assert.Empty(t, statusWriter.Updates)
+}
+
+func TestStoryCommand_BranchPerStory(t *testing.T) {
+	tmpDir := t.TempDir()
+	createSprintStatusFile(t, tmpDir, `development_status:
+  6-1-first: review
+  6-2-second: done
+  6-3-third: review`)
+
+	brancher := &MockBrancher{}
+	app := &App{
+		Config:       config.DefaultConfig(),
+		StatusReader: status.NewReader(tmpDir),
+		StatusWriter: &MockStatusWriter{},
+		Runner:       &MockWorkflowRunner{},
+		Printer:      output.NewPrinterWithWriter(&bytes.Buffer{}),
+		Brancher:     brancher,
+	}
+
+	rootCmd := NewRootCommand(app)
+	rootCmd.SetArgs([]string{"story", "6-1-first", "6-2-second", "6-3-third"})
+	err := rootCmd.Execute()
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"start:6-1-first", "finish:6-1-first",
+		"start:6-3-third", "finish:6-3-third",
+	}, brancher.Calls, "done stories get no branch")
+}
+
+func TestStoryCommand_FailurePolicyStash(t *testing.T) {
+	tmpDir := t.TempDir()
+	createSprintStatusFile(t, tmpDir, `development_status:
+  6-1-first: in-progress`)
+
+	cfg := config.DefaultConfig()
+	cfg.Lifecycle.FailurePolicy = config.FailurePolicyStash
+
+	ws := &MockWorktree{}
+	app := &App{
+		Config:       cfg,
+		StatusReader: status.NewReader(tmpDir),
+		StatusWriter: &MockStatusWriter{},
+		Runner:       &MockWorkflowRunner{FailOnWorkflow: "dev-story"},
+		Printer:      output.NewPrinterWithWriter(&bytes.Buffer{}),
+		Worktree:     ws,
+	}
+
+	rootCmd := NewRootCommand(app)
+	rootCmd.SetArgs([]string{"story", "6-1-first"})
+	err := rootCmd.Execute()
+
+	require.Error(t, err)
+	assert.Equal(t, []string{"bmaduum: 6-1-first partial work"}, ws.Stashes)
+	assert.Empty(t, ws.ResetTo)
+}
+
+func TestStoryCommand_NoPush(t *testing.T) {
+	tmpDir := t.TempDir()
+	createSprintStatusFile(t, tmpDir, `development_status:
+  6-1-first: review`)
+
+	app := &App{
+		Config:       config.DefaultConfig(),
+		StatusReader: status.NewReader(tmpDir),
+		StatusWriter: &MockStatusWriter{},
+		Runner:       &MockWorkflowRunner{},
+		Printer:      output.NewPrinterWithWriter(&bytes.Buffer{}),
+	}
+	require.True(t, app.Config.Git.Push)
+
+	rootCmd := NewRootCommand(app)
+	rootCmd.SetArgs([]string{"story", "--no-push", "6-1-first"})
+	require.NoError(t, rootCmd.Execute())
+
+	assert.False(t, app.Config.Git.Push)
+}
+
+func TestStoryCommand_LifecycleSteps(t *testing.T) {
+	tmpDir := t.TempDir()
+	createSprintStatusFile(t, tmpDir, `development_status:
+  6-1-first: ready-for-dev`)
+
+	cfg := config.DefaultConfig()
+	cfg.Workflows["security-scan"] = config.WorkflowConfig{PromptTemplate: "Scan {{.StoryKey}}"}
+	cfg.Lifecycle.Steps = []config.LifecycleStepConfig{
+		{Workflow: "create-story", NextStatus: "ready-for-dev"},
+		{Workflow: "dev-story", NextStatus: "review"},
+		{Workflow: "security-scan"},
+		{Workflow: "code-review", NextStatus: "done"},
+	}
+	runner := &MockWorkflowRunner{}
+	app := &App{
+		Config:       cfg,
+		StatusReader: status.NewReader(tmpDir),
+		StatusWriter: &MockStatusWriter{},
+		Runner:       runner,
+		Printer:      output.NewPrinterWithWriter(&bytes.Buffer{}),
+	}
+
+	rootCmd := NewRootCommand(app)
+	rootCmd.SetArgs([]string{"story", "6-1-first"})
+	require.NoError(t, rootCmd.Execute())
+	assert.Equal(t, []string{"dev-story", "security-scan", "code-review"}, runner.ExecutedWorkflows)
+
+	cfg.Lifecycle.Steps = append(cfg.Lifecycle.Steps, config.LifecycleStepConfig{Workflow: "deploy"})
+	runner.ExecutedWorkflows = nil
+	rootCmd = NewRootCommand(app)
+	rootCmd.SetArgs([]string{"story", "6-1-first"})
+	err := rootCmd.Execute()
+	require.Error(t, err)
+	assert.Empty(t, runner.ExecutedWorkflows, "an invalid lifecycle runs nothing")
+
+	cfg.Lifecycle.Steps[4] = config.LifecycleStepConfig{Workflow: "git-commit", NextStatus: "shipped"}
+	_, err = lifecycleSteps(cfg)
+	assert.ErrorContains(t, err, `lifecycle.steps[4]: invalid next_status "shipped"`)
+	cfg.Lifecycle.Steps[4] = config.LifecycleStepConfig{Workflow: "deploy"}
+	_, err = lifecycleSteps(cfg)
+	assert.ErrorContains(t, err, `lifecycle.steps[4]: unknown workflow "deploy" (defined: `)
+}
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"bmaduum/internal/config"
 	"bmaduum/internal/status"
 )
 
@@ -21,16 +22,40 @@ type MockWorkflowRunner struct {
 	ExecutedWorkflows []string
 	// FailOnWorkflow specifies which workflow should fail (returns exit code 1).
 	FailOnWorkflow string
+	// WorkflowData records the template data passed to RunWorkflow.
+	WorkflowData []config.PromptData
+	// Messages holds the final messages of successive runs of each workflow,
+	// returned by LastMessage.
+	Messages map[string][]string
+
+	lastMessage string
 }
 
 func (m *MockWorkflowRunner) RunSingle(ctx context.Context, workflowName, storyKey string) int {
 	m.ExecutedWorkflows = append(m.ExecutedWorkflows, workflowName)
+	m.lastMessage = ""
+	if messages := m.Messages[workflowName]; len(messages) > 0 {
+		m.lastMessage, m.Messages[workflowName] = messages[0], messages[1:]
+	}
+	if m.FailOnWorkflow == workflowName {
+		return 1
+	}
+	return 0
+}
+
+func (m *MockWorkflowRunner) RunWorkflow(ctx context.Context, workflowName string, data config.PromptData) int {
+	m.ExecutedWorkflows = append(m.ExecutedWorkflows, workflowName)
+	m.WorkflowData = append(m.WorkflowData, data)
 	if m.FailOnWorkflow == workflowName {
 		return 1
 	}
 	return 0
 }
 
+func (m *MockWorkflowRunner) LastMessage() string {
+	return m.lastMessage
+}
+
 func (m *MockWorkflowRunner) RunRaw(ctx context.Context, prompt string) int {
 	return 0
 }
@@ -50,6 +75,57 @@ func (m *MockStatusWriter) UpdateStatus(storyKey string, newStatus status.Status
 	return nil
 }
 
+// MockPreflight is a mock for testing.
+type MockPreflight struct {
+	// Problems is returned by Check.
+	Problems []string
+	// Calls counts Check calls.
+	Calls int
+}
+
+func (m *MockPreflight) Check() []string {
+	m.Calls++
+	return m.Problems
+}
+
+// MockBrancher is a mock for testing.
+type MockBrancher struct {
+	// Calls records "start:key" and "finish:key" in call order.
+	Calls []string
+}
+
+func (m *MockBrancher) Start(storyKey string) error {
+	m.Calls = append(m.Calls, "start:"+storyKey)
+	return nil
+}
+
+func (m *MockBrancher) Finish(storyKey string) error {
+	m.Calls = append(m.Calls, "finish:"+storyKey)
+	return nil
+}
+
+// MockWorktree is a mock for testing.
+type MockWorktree struct {
+	// Stashes records stash messages.
+	Stashes []string
+	// ResetTo records reset commits.
+	ResetTo []string
+}
+
+func (m *MockWorktree) Head() (string, error) {
+	return "0123456789abcdef", nil
+}
+
+func (m *MockWorktree) Stash(message string) (bool, error) {
+	m.Stashes = append(m.Stashes, message)
+	return true, nil
+}
+
+func (m *MockWorktree) Reset(commit string) error {
+	m.ResetTo = append(m.ResetTo, commit)
+	return nil
+}
+
 // createSprintStatusFile creates a sprint-status.yaml file in a temporary directory for testing.
 func createSprintStatusFile(t *testing.T, tmpDir string, content string) {
 	t.Helper()
@@ -3,10 +3,18 @@ package cli
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/spf13/cobra"
+
+	"bmaduum/internal/config"
 )
 
+// newWorkflowCommand creates the workflow command with a subcommand for every
+// workflow defined in the configuration, including user-defined ones, except
+// the [config.InternalWorkflows] and the epic-level
+// [config.RetrospectiveWorkflow].
 func newWorkflowCommand(app *App) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "workflow <workflow-name> <story-key>",
@@ -17,12 +25,12 @@ These are the same workflow commands that are used in BMAD-METHOD and are
 automatically executed by the 'story' and 'epic' commands. Use these commands
 to run individual workflow steps outside of the full lifecycle automation.
 
-Available workflows:
-  - create-story: Create a story definition from backlog
-  - dev-story: Implement a story (ready-for-dev or in-progress)
-  - code-review: Review code changes (review status)
-  - git-commit: Commit and push changes after review
+Every workflow defined in the configuration can be run, including your own:
+add an entry under workflows with a prompt_template (or prompt_file) and an
+optional description, which is shown in this help and in shell completions.
 
+Available workflows:
+` + workflowList(app) + `
 Most users should use 'story' or 'epic' commands instead, which automatically
 run the appropriate workflows based on story status.
 
@@ -38,74 +46,53 @@ Example:
   bmaduum workflow git-commit PROJ-123`,
 		Args: cobra.MinimumNArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			workflowName := args[0]
-			storyKey := args[1]
-
-			// Get the subcommand that matches the workflow name
-			for _, subcmd := range cmd.Commands() {
-				if subcmd.Name() == workflowName {
-					// Call the subcommand directly with remaining args
-					_ = storyKey // avoid unused variable warning
-					return subcmd.RunE(subcmd, args[1:])
-				}
+			// Runnable workflows are subcommands, so only unknown, internal
+			// and epic-level names get here
+			cmd.SilenceUsage = true
+			if slices.Contains(config.InternalWorkflows, args[0]) {
+				return fmt.Errorf("workflow %s is run by bmaduum itself and cannot be run on its own", args[0])
 			}
-
-			return fmt.Errorf("unknown workflow: %s (valid workflows: create-story, dev-story, code-review, git-commit)", workflowName)
+			if args[0] == config.RetrospectiveWorkflow && app.Config.HasWorkflow(args[0]) {
+				return fmt.Errorf("workflow %s runs for a whole epic, not a story: use bmaduum epic <epic-id>", args[0])
+			}
+			return fmt.Errorf("unknown workflow: %s (configured workflows: %s)",
+				args[0], strings.Join(runnableWorkflows(app), ", "))
 		},
 	}
 
-	// Add subcommands for each workflow
-	cmd.AddCommand(
-		newCreateStoryWorkflowCommand(app),
-		newDevStoryWorkflowCommand(app),
-		newCodeReviewWorkflowCommand(app),
-		newGitCommitWorkflowCommand(app),
-	)
+	for _, name := range runnableWorkflows(app) {
+		cmd.AddCommand(newNamedWorkflowCommand(app, name))
+	}
 
 	return cmd
 }
 
-// newCreateStoryWorkflowCommand creates the create-story workflow subcommand
-func newCreateStoryWorkflowCommand(app *App) *cobra.Command {
+// newNamedWorkflowCommand creates the subcommand that runs one configured
+// workflow for a story.
+func newNamedWorkflowCommand(app *App, name string) *cobra.Command {
 	var autoRetry bool
 
-	cmd := &cobra.Command{
-		Use:   "create-story <story-key>",
-		Short: "Create a story definition from backlog",
-		Long: `Create a story definition from backlog status.
-
-This workflow generates a detailed story definition including acceptance criteria,
-technical requirements, and implementation plan.`,
-		Args: cobra.ExactArgs(1),
-		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx := cmd.Context()
-			storyKey := args[0]
-
-			return executeWorkflowWithRetry(ctx, cmd, app, "create-story", storyKey, autoRetry)
-		},
+	description := app.Config.GetDescription(name)
+	if description == "" {
+		description = fmt.Sprintf("Run the %s workflow", name)
 	}
 
-	cmd.Flags().BoolVar(&autoRetry, "auto-retry", false, "Automatically retry on rate limit errors")
-	return cmd
-}
-
-// newDevStoryWorkflowCommand creates the dev-story workflow subcommand
-func newDevStoryWorkflowCommand(app *App) *cobra.Command {
-	var autoRetry bool
-
 	cmd := &cobra.Command{
-		Use:   "dev-story <story-key>",
-		Short: "Implement a story",
-		Long: `Implement a story through development.
+		Use:   name + " <story-key>",
+		Short: description,
+		Long: strings.TrimSuffix(description, ".") + `.
 
-This workflow handles stories with status ready-for-dev or in-progress,
-running the actual implementation work.`,
-		Args: cobra.ExactArgs(1),
+The prompt comes from workflows.` + name + ` in the configuration.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeStoryKeys(app),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireAgent(cmd, app); err != nil {
+				return err
+			}
 			ctx := cmd.Context()
 			storyKey := args[0]
 
-			return executeWorkflowWithRetry(ctx, cmd, app, "dev-story", storyKey, autoRetry)
+			return executeWorkflowWithRetry(ctx, cmd, app, name, storyKey, autoRetry)
 		},
 	}
 
@@ -113,50 +100,50 @@ running the actual implementation work.`,
 	return cmd
 }
 
-// newCodeReviewWorkflowCommand creates the code-review workflow subcommand
-func newCodeReviewWorkflowCommand(app *App) *cobra.Command {
-	var autoRetry bool
-
-	cmd := &cobra.Command{
-		Use:   "code-review <story-key>",
-		Short: "Review code changes",
-		Long: `Review code changes for a story.
-
-This workflow reviews the implementation and generates feedback.`,
-		Args: cobra.ExactArgs(1),
-		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx := cmd.Context()
-			storyKey := args[0]
+// runnableWorkflows returns the configured workflows that can be run for a
+// story with the workflow command, in name order.
+func runnableWorkflows(app *App) []string {
+	return slices.DeleteFunc(app.Config.WorkflowNames(), func(name string) bool {
+		return slices.Contains(config.InternalWorkflows, name) || name == config.RetrospectiveWorkflow
+	})
+}
 
-			return executeWorkflowWithRetry(ctx, cmd, app, "code-review", storyKey, autoRetry)
-		},
+// workflowList formats the runnable workflows and their descriptions for
+// the workflow command's help.
+func workflowList(app *App) string {
+	var b strings.Builder
+	for _, name := range runnableWorkflows(app) {
+		if description := app.Config.GetDescription(name); description != "" {
+			fmt.Fprintf(&b, "  - %s: %s\n", name, description)
+		} else {
+			fmt.Fprintf(&b, "  - %s\n", name)
+		}
 	}
-
-	cmd.Flags().BoolVar(&autoRetry, "auto-retry", false, "Automatically retry on rate limit errors")
-	return cmd
+	return b.String()
 }
 
-// newGitCommitWorkflowCommand creates the git-commit workflow subcommand
-func newGitCommitWorkflowCommand(app *App) *cobra.Command {
-	var autoRetry bool
-
-	cmd := &cobra.Command{
-		Use:   "git-commit <story-key>",
-		Short: "Commit and push changes",
-		Long: `Commit and push changes for a story.
-
-This workflow commits the changes and pushes them to the remote repository.`,
-		Args: cobra.ExactArgs(1),
-		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx := cmd.Context()
-			storyKey := args[0]
-
-			return executeWorkflowWithRetry(ctx, cmd, app, "git-commit", storyKey, autoRetry)
-		},
+// completeStoryKeys completes the story key argument with the stories in the
+// sprint status file.
+func completeStoryKeys(app *App) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 || app.StatusReader == nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		sprintStatus, err := app.StatusReader.Read()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		var keys []string
+		for _, epic := range sprintStatus.Epics() {
+			for _, story := range epic.Stories {
+				if strings.HasPrefix(story.Key, toComplete) {
+					keys = append(keys, fmt.Sprintf("%s\t%s", story.Key, story.Status))
+				}
+			}
+		}
+		return keys, cobra.ShellCompDirectiveNoFileComp
 	}
-
-	cmd.Flags().BoolVar(&autoRetry, "auto-retry", false, "Automatically retry on rate limit errors")
-	return cmd
 }
 
 // executeWorkflowWithRetry executes a single workflow with optional retry logic
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
 
@@ -13,12 +14,19 @@ import (
 
 // Loader handles configuration loading from files and environment.
 //
-// Loader uses Viper to load configuration from YAML files and environment
-// variables, merging them with default values. The loader supports the
-// BMADUUM_ environment variable prefix for all configuration options.
+// Loader merges the built-in defaults, the config files found for the user
+// and the project, and BMADUUM_ environment variables into one [Config],
+// recording where every value came from.
 type Loader struct {
-	// v is the Viper instance used for configuration loading.
+	// v is the Viper instance used to decode the merged configuration.
 	v *viper.Viper
+
+	// projectDir is where the project config search starts. Defaults to the
+	// current directory.
+	projectDir string
+
+	// profile is the profile selected with --profile. See [Loader.SetProfile].
+	profile string
 }
 
 // NewLoader creates a new configuration loader.
@@ -31,98 +39,64 @@ func NewLoader() *Loader {
 	}
 }
 
+// SetProjectDir sets the directory the project config files are looked up
+// from, e.g. the directory given with --project. Defaults to the current
+// directory.
+func (l *Loader) SetProjectDir(dir string) {
+	l.projectDir = dir
+}
+
+// SetProfile selects the profile to apply, e.g. the one given with
+// --profile. It takes precedence over BMADUUM_PROFILE and the profile key.
+func (l *Loader) SetProfile(name string) {
+	l.profile = name
+}
+
 // Load loads configuration from the default locations and environment.
 //
-// Configuration is loaded and merged with the following priority (highest first):
-//  1. Environment variables with BMADUUM_ prefix (e.g., BMADUUM_CLAUDE_BINARY_PATH)
-//  2. Config file specified by BMADUUM_CONFIG_PATH environment variable
-//  3. User config directory: ~/.config/bmaduum/workflows.yaml (Linux),
+// Configuration is merged in layers, each overriding the ones before it:
+//  1. [DefaultConfig] built-in defaults
+//  2. User config: ~/.config/bmaduum/workflows.yaml (Linux),
 //     ~/Library/Application Support/bmaduum/workflows.yaml (macOS),
 //     %APPDATA%\bmaduum\workflows.yaml (Windows)
-//  4. ./config/workflows.yaml in the current directory (legacy fallback)
-//  5. ./workflows.yaml in the current directory (legacy fallback)
-//  6. [DefaultConfig] built-in defaults
+//  3. ./workflows.yaml and ./config/workflows.yaml in the project directory
+//     (legacy locations)
+//  4. Project config: the closest .bmaduum.yaml in the project directory or
+//     its parents
+//  5. Config file specified by BMADUUM_CONFIG_PATH environment variable
+//  6. The active profile: the entry of profiles named by [Loader.SetProfile],
+//     BMADUUM_PROFILE or the profile key
+//  7. Environment variables with BMADUUM_ prefix (e.g., BMADUUM_CLAUDE_BINARY_PATH)
+//
+// Maps are merged key by key, so a layer can change one setting of a
+// workflow and keep the rest; lists replace the list below them. Setting a
+// workflow's model or models, or its prompt_template or prompt_file, clears
+// the other key of the pair set below. Command
+// line flags are applied on top by the CLI. Use [Config.Origin] to find the
+// layer a value came from.
 //
 // Environment variable names use underscores for nested keys. For example,
 // claude.binary_path becomes BMADUUM_CLAUDE_BINARY_PATH.
 //
-// Returns an error if a config file exists but cannot be parsed. Missing
-// config files are not an error; the loader falls back to defaults.
+// Returns an error if a config file exists but cannot be parsed, if
+// BMADUUM_CONFIG_PATH names a file that cannot be read, or if the selected
+// profile is not defined. Missing config files are not an error; the loader
+// falls back to defaults.
 func (l *Loader) Load() (*Config, error) {
-	// Start with defaults
-	cfg := DefaultConfig()
-
-	// Set up Viper
-	l.v.SetConfigType("yaml")
-	l.v.SetEnvPrefix("BMADUUM")
-	l.v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
-	l.v.AutomaticEnv()
-
-	// Try to find and read config file
-	configPath := os.Getenv("BMADUUM_CONFIG_PATH")
-	if configPath != "" {
-		// User explicitly set a config path
-		l.v.SetConfigFile(configPath)
-	} else {
-		// Search for config in multiple locations (in priority order)
-		l.v.SetConfigName("workflows")
-
-		// 1. User config directory (platform-standard location)
-		userConfigDir, err := os.UserConfigDir()
-		if err == nil {
-			appConfigDir := filepath.Join(userConfigDir, "bmaduum")
-			l.v.AddConfigPath(appConfigDir)
-		}
-
-		// 2. Legacy local directories (for backward compatibility)
-		l.v.AddConfigPath("./config")
-		l.v.AddConfigPath(".")
-	}
-
-	// Read config file if it exists
-	if err := l.v.ReadInConfig(); err != nil {
-		// Config file not found is okay, we'll use defaults
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-			// Some other error occurred
-			return nil, fmt.Errorf("error reading config file: %w", err)
-		}
-	}
-
-	// Unmarshal into config struct
-	if err := l.v.Unmarshal(cfg); err != nil {
-		return nil, fmt.Errorf("error unmarshaling config: %w", err)
-	}
-
-	// Override Claude binary path from env if set
-	if binaryPath := os.Getenv("BMADUUM_CLAUDE_PATH"); binaryPath != "" {
-		cfg.Claude.BinaryPath = binaryPath
-	}
-
-	return cfg, nil
+	return l.load(l.layers(), true)
 }
 
 // LoadFromFile loads configuration from a specific file path.
 //
-// Unlike [Loader.Load], this method loads from an explicit file path without
-// searching default locations or checking environment variables. The file
-// extension determines the expected format (yaml, json, etc.).
+// Unlike [Loader.Load], this method merges only the given file over the
+// defaults, without searching default locations or checking environment
+// variables. The profile selected with [Loader.SetProfile] or the file's
+// profile key is applied. The file extension determines the expected format (yaml, json,
+// etc.).
 //
 // Returns an error if the file cannot be read or parsed.
 func (l *Loader) LoadFromFile(path string) (*Config, error) {
-	cfg := DefaultConfig()
-
-	l.v.SetConfigFile(path)
-	l.v.SetConfigType(filepath.Ext(path)[1:]) // Remove the dot
-
-	if err := l.v.ReadInConfig(); err != nil {
-		return nil, fmt.Errorf("error reading config file %s: %w", path, err)
-	}
-
-	if err := l.v.Unmarshal(cfg); err != nil {
-		return nil, fmt.Errorf("error unmarshaling config: %w", err)
-	}
-
-	return cfg, nil
+	return l.load([]Layer{{Origin: OriginFile, Path: path}}, false)
 }
 
 // GetPrompt returns the expanded prompt for a workflow and story key.
@@ -132,12 +106,40 @@ func (l *Loader) LoadFromFile(path string) (*Config, error) {
 //
 // Returns an error if the workflow is not found or if template expansion fails.
 func (c *Config) GetPrompt(workflowName, storyKey string) (string, error) {
+	return c.GetPromptWithData(workflowName, PromptData{StoryKey: storyKey})
+}
+
+// GetPromptWithData returns the expanded prompt for a workflow and arbitrary [PromptData].
+//
+// Use this for workflows that need more than a story key, such as the epic
+// retrospective which receives the epic ID and its stories.
+//
+// The configured [Config.Vars] are used when data carries no variables.
+//
+// Returns an error if the workflow is not found or if template expansion fails.
+func (c *Config) GetPromptWithData(workflowName string, data PromptData) (string, error) {
 	workflow, ok := c.Workflows[workflowName]
 	if !ok {
 		return "", fmt.Errorf("unknown workflow: %s", workflowName)
 	}
+	if data.Vars == nil {
+		data.Vars = c.Vars
+	}
 
-	return expandTemplate(workflow.PromptTemplate, PromptData{StoryKey: storyKey})
+	if workflow.prompt != nil {
+		var buf bytes.Buffer
+		if err := workflow.prompt.Execute(&buf, data); err != nil {
+			return "", fmt.Errorf("error executing template: %w", err)
+		}
+		return buf.String(), nil
+	}
+	return expandTemplate(workflow.PromptTemplate, data)
+}
+
+// HasWorkflow reports whether a workflow with the given name is configured.
+func (c *Config) HasWorkflow(workflowName string) bool {
+	_, ok := c.Workflows[workflowName]
+	return ok
 }
 
 // GetFullCycleSteps returns the list of workflow steps for a full lifecycle.
@@ -149,19 +151,113 @@ func (c *Config) GetFullCycleSteps() []string {
 }
 
 // GetModel returns the model configured for a workflow, or empty string if not set.
+// With a models fallback chain, it returns the first model of the chain.
 //
 // When empty, the Claude CLI will use its default model.
 func (c *Config) GetModel(workflowName string) string {
+	if models := c.GetModels(workflowName); len(models) > 0 {
+		return models[0]
+	}
+	return ""
+}
+
+// GetModels returns the models a workflow may run on, in the order they are
+// tried: its models fallback chain, or else its model. Returns nil if
+// neither is set.
+func (c *Config) GetModels(workflowName string) []string {
+	workflow, ok := c.Workflows[workflowName]
+	switch {
+	case !ok:
+		return nil
+	case len(workflow.Models) > 0:
+		return workflow.Models
+	case workflow.Model != "":
+		return []string{workflow.Model}
+	}
+	return nil
+}
+
+// GetModelFallback returns when a workflow moves on to the next model of
+// its chain (see [WorkflowConfig.ModelFallback]), defaulting to
+// [ModelFallbackRateLimit].
+func (c *Config) GetModelFallback(workflowName string) string {
+	if policy := c.Workflows[workflowName].ModelFallback; policy != "" {
+		return policy
+	}
+	return ModelFallbackRateLimit
+}
+
+// GetDescription returns the description of a workflow for help output.
+//
+// Standard workflows whose config entry has no description fall back to
+// their built-in one. Returns an empty string for unknown workflows.
+func (c *Config) GetDescription(workflowName string) string {
 	workflow, ok := c.Workflows[workflowName]
 	if !ok {
 		return ""
 	}
-	return workflow.Model
+	if workflow.Description != "" {
+		return workflow.Description
+	}
+	return DefaultConfig().Workflows[workflowName].Description
+}
+
+// WorkflowNames returns the names of all configured workflows, sorted.
+func (c *Config) WorkflowNames() []string {
+	names := make([]string, 0, len(c.Workflows))
+	for name := range c.Workflows {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetBranchName returns the story branch name for data, expanded from
+// [GitConfig.BranchTemplate].
+//
+// Returns an error if template expansion fails or yields an empty name.
+func (c *Config) GetBranchName(data PromptData) (string, error) {
+	if data.Vars == nil {
+		data.Vars = c.Vars
+	}
+	name, err := expandTemplate(c.Git.BranchTemplate, data)
+	if err != nil {
+		return "", fmt.Errorf("branch_template: %w", err)
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("branch_template %q expands to an empty name", c.Git.BranchTemplate)
+	}
+	return name, nil
+}
+
+// SetVars applies name=value assignments, such as those given with --var, on
+// top of the configured [Config.Vars]. Names are lowercased to match keys
+// read from the config file.
+//
+// Returns an error if an assignment has no "=" or an empty name.
+func (c *Config) SetVars(assignments []string) error {
+	for _, a := range assignments {
+		name, value, ok := strings.Cut(a, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !ok || name == "" {
+			return fmt.Errorf("invalid variable %q: expected name=value", a)
+		}
+		if c.Vars == nil {
+			c.Vars = make(map[string]string)
+		}
+		c.Vars[name] = value
+		c.SetOrigin("vars."+name, OriginFlag+" --var")
+	}
+	return nil
 }
 
 // expandTemplate expands a Go template string with the given data.
+//
+// Templates can use the helper functions in [templateFuncs].
 func expandTemplate(tmpl string, data PromptData) (string, error) {
-	t, err := template.New("prompt").Parse(tmpl)
+	t, err := template.New("prompt").Funcs(templateFuncs).Option("missingkey=zero").Parse(tmpl)
 	if err != nil {
 		return "", fmt.Errorf("error parsing template: %w", err)
 	}
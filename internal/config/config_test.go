@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -26,6 +27,17 @@ func TestDefaultConfig(t *testing.T) {
 	assert.Equal(t, "claude", cfg.Claude.BinaryPath)
 	assert.Equal(t, 20, cfg.Output.TruncateLines)
 	assert.Equal(t, 60, cfg.Output.TruncateLength)
+	assert.False(t, cfg.Lifecycle.Verify, "verification is opt-in")
+	assert.Equal(t, 3, cfg.Lifecycle.MaxReviewCycles)
+	assert.Equal(t, FailurePolicyKeep, cfg.Lifecycle.FailurePolicy)
+	assert.True(t, cfg.Git.Preflight)
+	assert.Equal(t, []string{"main", "master"}, cfg.Git.ProtectedBranches)
+	assert.False(t, cfg.Git.BranchPerStory)
+	assert.Equal(t, "story/{{.StoryKey}}", cfg.Git.BranchTemplate)
+	assert.True(t, cfg.Git.ReturnToBase)
+	assert.Equal(t, 1, cfg.Workflows[CommitMessageWorkflow].MaxTurns, "commit-message is single-turn")
+	assert.Equal(t, CommitModeClaude, cfg.Git.CommitMode)
+	assert.True(t, cfg.Git.Push)
 }
 
 func TestConfig_GetPrompt(t *testing.T) {
@@ -80,6 +92,23 @@ func TestConfig_GetFullCycleSteps(t *testing.T) {
 	assert.Equal(t, []string{"create-story", "dev-story", "code-review", "git-commit"}, steps)
 }
 
+func TestConfig_GetModels(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Workflows["dev-story"] = WorkflowConfig{Models: []string{"opus", "sonnet"}, ModelFallback: ModelFallbackOverload}
+	cfg.Workflows["code-review"] = WorkflowConfig{Model: "sonnet"}
+
+	assert.Equal(t, []string{"opus", "sonnet"}, cfg.GetModels("dev-story"))
+	assert.Equal(t, "opus", cfg.GetModel("dev-story"))
+	assert.Equal(t, ModelFallbackOverload, cfg.GetModelFallback("dev-story"))
+
+	assert.Equal(t, []string{"sonnet"}, cfg.GetModels("code-review"))
+	assert.Equal(t, ModelFallbackRateLimit, cfg.GetModelFallback("code-review"))
+
+	assert.Nil(t, cfg.GetModels("create-story"))
+	assert.Nil(t, cfg.GetModels("unknown"))
+	assert.Empty(t, cfg.GetModel("unknown"))
+}
+
 func TestLoader_LoadFromFile(t *testing.T) {
 	// Create a temporary config file
 	tmpDir := t.TempDir()
@@ -89,6 +118,20 @@ func TestLoader_LoadFromFile(t *testing.T) {
 workflows:
   custom-workflow:
     prompt_template: "Custom: {{.StoryKey}}"
+    gates:
+      - name: tests
+        command: go test ./...
+        timeout: 5m
+      - command: golangci-lint run
+        exit_codes: [0, 2]
+    fix_attempts: 2
+    hooks:
+      pre:
+        - command: docker compose up -d db
+      post:
+        - name: format
+          command: gofmt -w .
+          on_error: warn
 full_cycle:
   steps:
     - custom-workflow
@@ -96,6 +139,21 @@ claude:
   binary_path: /custom/path/claude
 output:
   truncate_lines: 50
+lifecycle:
+  verify: true
+  max_review_cycles: 5
+  failure_policy: stash
+  hooks:
+    on_failure:
+      - command: curl -s localhost:8080/failed
+        timeout: 10s
+git:
+  protected_branches: [release]
+  branch_per_story: true
+  base_branch: develop
+  return_to_base: false
+  commit_mode: native
+  push: false
 `
 	err := os.WriteFile(configPath, []byte(configContent), 0644)
 	require.NoError(t, err)
@@ -105,9 +163,30 @@ output:
 
 	require.NoError(t, err)
 	assert.Contains(t, cfg.Workflows, "custom-workflow")
+	assert.Equal(t, []GateConfig{
+		{Name: "tests", Command: "go test ./...", Timeout: 5 * time.Minute},
+		{Command: "golangci-lint run", ExitCodes: []int{0, 2}},
+	}, cfg.Workflows["custom-workflow"].Gates)
+	assert.Equal(t, 2, cfg.Workflows["custom-workflow"].FixAttempts)
 	assert.Equal(t, []string{"custom-workflow"}, cfg.FullCycle.Steps)
 	assert.Equal(t, "/custom/path/claude", cfg.Claude.BinaryPath)
 	assert.Equal(t, 50, cfg.Output.TruncateLines)
+	assert.True(t, cfg.Lifecycle.Verify)
+	assert.Equal(t, 5, cfg.Lifecycle.MaxReviewCycles)
+	assert.Equal(t, FailurePolicyStash, cfg.Lifecycle.FailurePolicy)
+	assert.Equal(t, HooksConfig{
+		Pre:  []HookConfig{{Command: "docker compose up -d db"}},
+		Post: []HookConfig{{Name: "format", Command: "gofmt -w .", OnError: HookOnErrorWarn}},
+	}, cfg.Workflows["custom-workflow"].Hooks)
+	assert.Equal(t, []HookConfig{{Command: "curl -s localhost:8080/failed", Timeout: 10 * time.Second}}, cfg.Lifecycle.Hooks.OnFailure)
+	assert.Equal(t, []string{"release"}, cfg.Git.ProtectedBranches)
+	assert.True(t, cfg.Git.Preflight)
+	assert.True(t, cfg.Git.BranchPerStory)
+	assert.Equal(t, "develop", cfg.Git.BaseBranch)
+	assert.False(t, cfg.Git.ReturnToBase)
+	assert.Equal(t, "story/{{.StoryKey}}", cfg.Git.BranchTemplate)
+	assert.Equal(t, CommitModeNative, cfg.Git.CommitMode)
+	assert.False(t, cfg.Git.Push)
 }
 
 func TestLoader_Load_WithEnvOverride(t *testing.T) {
@@ -178,6 +257,40 @@ func TestNewLoader(t *testing.T) {
 	assert.NotNil(t, loader.v)
 }
 
+func TestLoader_LoadFromFile_ClaudeOptions(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`
+workflows:
+  git-commit:
+    permission_mode: dontAsk
+    allowed_tools: ["Bash(git:*)"]
+    max_turns: 10
+    append_system_prompt: Only commit, never push.
+    mcp_config: .mcp.json
+    add_dirs: [../shared]
+    extra_args: [--fallback-model, haiku]
+  code-review:
+    disallowed_tools: [Edit, Write, NotebookEdit]
+`), 0644))
+
+	cfg, err := NewLoader().LoadFromFile(configPath)
+	require.NoError(t, err)
+
+	commit := cfg.Workflows["git-commit"]
+	assert.Equal(t, "dontAsk", commit.PermissionMode)
+	assert.Equal(t, []string{"Bash(git:*)"}, commit.AllowedTools)
+	assert.Equal(t, 10, commit.MaxTurns)
+	assert.Equal(t, "Only commit, never push.", commit.AppendSystemPrompt)
+	assert.Equal(t, ".mcp.json", commit.MCPConfig)
+	assert.Equal(t, []string{"../shared"}, commit.AddDirs)
+	assert.Equal(t, []string{"--fallback-model", "haiku"}, commit.ExtraArgs)
+	assert.NotEmpty(t, commit.PromptTemplate, "built-in prompt is kept")
+
+	assert.Equal(t, []string{"Edit", "Write", "NotebookEdit"}, cfg.Workflows["code-review"].DisallowedTools)
+	assert.Empty(t, cfg.Validate())
+}
+
 func TestLoader_LoadFromFile_NonExistent(t *testing.T) {
 	loader := NewLoader()
 	_, err := loader.LoadFromFile("/nonexistent/path/config.yaml")
@@ -369,3 +482,103 @@ func TestEnsureConfigDir(t *testing.T) {
 		assert.NotContains(t, err.Error(), "not implemented")
 	}
 }
+
+func TestConfig_GetPromptWithData(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Workflows[RetrospectiveWorkflow] = WorkflowConfig{
+		PromptTemplate: "Epic {{.EpicID}} stories:{{range .Stories}} {{.}}{{end}}",
+	}
+
+	prompt, err := cfg.GetPromptWithData(RetrospectiveWorkflow, PromptData{
+		EpicID:  "6",
+		Stories: []string{"6-1-a", "6-2-b"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Epic 6 stories: 6-1-a 6-2-b", prompt)
+
+	_, err = cfg.GetPromptWithData("unknown", PromptData{})
+	assert.Error(t, err)
+}
+
+func TestConfig_GetBranchName(t *testing.T) {
+	cfg := DefaultConfig()
+
+	name, err := cfg.GetBranchName(PromptData{StoryKey: "6-2-add-feature", EpicID: "6"})
+	require.NoError(t, err)
+	assert.Equal(t, "story/6-2-add-feature", name)
+
+	cfg.Git.BranchTemplate = "feature/epic-{{.EpicID}}/{{.StoryKey}}"
+	name, err = cfg.GetBranchName(PromptData{StoryKey: "6-2-add-feature", EpicID: "6"})
+	require.NoError(t, err)
+	assert.Equal(t, "feature/epic-6/6-2-add-feature", name)
+
+	cfg.Git.BranchTemplate = "{{.Workflow}}"
+	_, err = cfg.GetBranchName(PromptData{StoryKey: "6-2-add-feature"})
+	assert.ErrorContains(t, err, "empty name")
+
+	cfg.Git.BranchTemplate = "story/{{.StoryKey"
+	_, err = cfg.GetBranchName(PromptData{StoryKey: "6-2-add-feature"})
+	assert.ErrorContains(t, err, "branch_template")
+}
+
+func TestConfig_HasWorkflow(t *testing.T) {
+	cfg := DefaultConfig()
+
+	assert.True(t, cfg.HasWorkflow("dev-story"))
+	assert.False(t, cfg.HasWorkflow(RetrospectiveWorkflow), "retrospective is optional and not configured by default")
+}
+
+func TestConfig_SetVars(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Vars = map[string]string{"team": "core", "jira": "PROJ"}
+
+	require.NoError(t, cfg.SetVars([]string{"Team=platform", "tone=terse", "empty="}))
+	assert.Equal(t, map[string]string{"team": "platform", "jira": "PROJ", "tone": "terse", "empty": ""}, cfg.Vars)
+
+	assert.ErrorContains(t, cfg.SetVars([]string{"novalue"}), "expected name=value")
+	assert.ErrorContains(t, cfg.SetVars([]string{"=x"}), "expected name=value")
+}
+
+func TestConfig_GetPromptWithData_Vars(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Vars = map[string]string{"jira": "PROJ"}
+	cfg.Workflows["dev-story"] = WorkflowConfig{PromptTemplate: "{{.Vars.jira}}-{{.StoryKey}}{{.Vars.missing}}"}
+
+	prompt, err := cfg.GetPromptWithData("dev-story", PromptData{StoryKey: "6-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "PROJ-6-1", prompt, "configured vars are used and unknown vars are empty")
+
+	prompt, err = cfg.GetPromptWithData("dev-story", PromptData{StoryKey: "6-1", Vars: map[string]string{"jira": "OPS"}})
+	require.NoError(t, err)
+	assert.Equal(t, "OPS-6-1", prompt)
+}
+
+func TestExpandTemplate_Funcs(t *testing.T) {
+	tests := []struct {
+		template string
+		data     PromptData
+		want     string
+	}{
+		{`{{.StorySlug | replace "-" " " | title}}`, PromptData{StorySlug: "add-user-api"}, "Add User Api"},
+		{`{{.StorySlug | replace "-" " " | title}}`, PromptData{StorySlug: "écran-ünique"}, "Écran Ünique"},
+		{`{{.Status | default "unknown" | upper}}`, PromptData{}, "UNKNOWN"},
+		{`{{.Status | default "unknown"}}`, PromptData{Status: "review"}, "review"},
+		{`{{coalesce .GateOutput .PreviousMessage "none"}}`, PromptData{PreviousMessage: "fix tests"}, "fix tests"},
+		{`{{if empty .Stories}}none{{else}}{{join ", " .Stories}}{{end}}`, PromptData{Stories: []string{"6-1", "6-2"}}, "6-1, 6-2"},
+		{`{{if empty .Stories}}none{{end}}`, PromptData{}, "none"},
+		{`{{if contains "FAIL" .GateOutput}}failing{{end}}`, PromptData{GateOutput: "--- FAIL: TestX"}, "failing"},
+		{`{{.PreviousMessage | trim | trunc 5}}`, PromptData{PreviousMessage: "  findings  "}, "findi"},
+		{`Notes:{{.PreviousMessage | nindent 2}}`, PromptData{PreviousMessage: "a\nb"}, "Notes:\n  a\n  b"},
+		{`{{.StoryKey | trimPrefix "6-" | quote}}`, PromptData{StoryKey: "6-1-x"}, `"1-x"`},
+		{`{{if gt .Attempt 1}}retry{{else}}first{{end}}`, PromptData{Attempt: 2}, "retry"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			got, err := expandTemplate(tt.template, tt.data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+)
+
+// templateFuncs are the helper functions available in prompt and branch
+// templates. Names and argument order follow the Sprig library used by Helm,
+// so the piped value always comes last: {{.StorySlug | replace "-" " " | title}}.
+var templateFuncs = template.FuncMap{
+	"default":    defaultValue,
+	"empty":      empty,
+	"coalesce":   coalesce,
+	"upper":      strings.ToUpper,
+	"lower":      strings.ToLower,
+	"title":      title,
+	"trim":       strings.TrimSpace,
+	"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+	"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+	"replace":    func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+	"contains":   func(substr, s string) bool { return strings.Contains(s, substr) },
+	"hasPrefix":  func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+	"hasSuffix":  func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+	"join":       func(sep string, list []string) string { return strings.Join(list, sep) },
+	"splitList":  func(sep, s string) []string { return strings.Split(s, sep) },
+	"indent":     indent,
+	"nindent":    func(n int, s string) string { return "\n" + indent(n, s) },
+	"quote":      func(s string) string { return fmt.Sprintf("%q", s) },
+	"trunc":      trunc,
+	"env":        os.Getenv,
+}
+
+// defaultValue returns value, or def if value is empty (see [empty]).
+func defaultValue(def, value any) any {
+	if empty(value) {
+		return def
+	}
+	return value
+}
+
+// empty reports whether value is nil, false, zero, or an empty string,
+// slice or map.
+func empty(value any) bool {
+	if value == nil {
+		return true
+	}
+	return reflect.ValueOf(value).IsZero() || isEmptyCollection(reflect.ValueOf(value))
+}
+
+// isEmptyCollection reports whether v is a slice or map without elements.
+func isEmptyCollection(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	default:
+		return false
+	}
+}
+
+// coalesce returns the first value that is not empty, or nil.
+func coalesce(values ...any) any {
+	for _, v := range values {
+		if !empty(v) {
+			return v
+		}
+	}
+	return nil
+}
+
+// title upper-cases the first letter of each space-separated word.
+func title(s string) string {
+	words := strings.Split(s, " ")
+	for i, w := range words {
+		if w != "" {
+			r, size := utf8.DecodeRuneInString(w)
+			words[i] = string(unicode.ToUpper(r)) + w[size:]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// indent prefixes every line of s with n spaces.
+func indent(n int, s string) string {
+	pad := strings.Repeat(" ", n)
+	return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+}
+
+// trunc shortens s to at most n bytes.
+func trunc(n int, s string) string {
+	if n < 0 || len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
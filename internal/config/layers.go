@@ -0,0 +1,451 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// ProjectConfigFile is the name of the project configuration file, looked up
+// in the project directory and its parents.
+const ProjectConfigFile = ".bmaduum.yaml"
+
+// Origins of configuration values, as reported by [Config.Origin].
+const (
+	OriginDefault = "default"
+	OriginUser    = "user"
+	OriginProject = "project"
+	OriginFile    = "file"
+	OriginEnv     = "env"
+	OriginFlag    = "flag"
+	OriginProfile = "profile"
+)
+
+// Layer is one configuration file merged by [Loader.Load].
+type Layer struct {
+	// Origin is the kind of layer: [OriginUser], [OriginProject] or [OriginFile].
+	Origin string
+
+	// Path is the file the layer is read from.
+	Path string
+}
+
+// SearchPaths returns every configuration file [Loader.Load] looks for,
+// lowest precedence first, whether or not it exists. The project config is
+// the closest .bmaduum.yaml in the project directory or a parent, or the one
+// that would be created in the project directory. BMADUUM_CONFIG_PATH is
+// only included when set.
+func (l *Loader) SearchPaths() []Layer {
+	var paths []Layer
+
+	if path, err := DefaultConfigPath(); err == nil {
+		paths = append(paths, Layer{Origin: OriginUser, Path: path})
+	}
+
+	dir := l.projectDir
+	if dir == "" {
+		dir = "."
+	}
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	// Legacy project-local files, below the project config
+	for _, legacy := range []string{"workflows.yaml", filepath.Join("config", "workflows.yaml")} {
+		paths = append(paths, Layer{Origin: OriginProject, Path: filepath.Join(dir, legacy)})
+	}
+	project := findProjectConfig(dir)
+	if project == "" {
+		project = filepath.Join(dir, ProjectConfigFile)
+	}
+	paths = append(paths, Layer{Origin: OriginProject, Path: project})
+
+	if path := os.Getenv("BMADUUM_CONFIG_PATH"); path != "" {
+		paths = append(paths, Layer{Origin: OriginFile, Path: path})
+	}
+
+	return paths
+}
+
+// layers returns the configuration files merged by [Loader.Load]: the
+// [Loader.SearchPaths] that exist, plus BMADUUM_CONFIG_PATH, which must
+// exist.
+func (l *Loader) layers() []Layer {
+	var layers []Layer
+	for _, layer := range l.SearchPaths() {
+		if layer.Origin == OriginFile || fileExists(layer.Path) {
+			layers = append(layers, layer)
+		}
+	}
+	return layers
+}
+
+// findProjectConfig returns the [ProjectConfigFile] in dir or its closest
+// parent that has one, or an empty string if there is none.
+func findProjectConfig(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		if path := filepath.Join(dir, ProjectConfigFile); fileExists(path) {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// fileExists reports whether path exists and is not a directory.
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// load merges the given layers over the defaults, applies environment
+// variables and decodes the result, recording where each value came from.
+func (l *Loader) load(layers []Layer, env bool) (*Config, error) {
+	merged := toMap(reflect.ValueOf(DefaultConfig())).(map[string]any)
+	origins := make(map[string]string)
+
+	for _, layer := range layers {
+		values, err := readLayer(layer.Path)
+		if err != nil {
+			return nil, err
+		}
+		mergeLayer(merged, values, "", layer.Origin+" "+layer.Path, origins)
+	}
+
+	profile, profileOrigin := l.selectedProfile(merged, origins, env)
+	if profile != "" {
+		if err := applyProfile(merged, profile, origins); err != nil {
+			return nil, err
+		}
+	}
+
+	if env {
+		applyEnv(merged, origins)
+	}
+	if profile != "" {
+		merged["profile"] = profile
+		origins["profile"] = profileOrigin
+	}
+
+	if err := l.v.MergeConfigMap(merged); err != nil {
+		return nil, fmt.Errorf("error merging config: %w", err)
+	}
+	cfg := &Config{}
+	if err := l.v.Unmarshal(cfg); err != nil {
+		return nil, fmt.Errorf("error unmarshaling config: %w", err)
+	}
+	cfg.origins = origins
+	cfg.layers = layers
+
+	if env {
+		// Override Claude binary path from env if set
+		if binaryPath := os.Getenv("BMADUUM_CLAUDE_PATH"); binaryPath != "" {
+			cfg.Claude.BinaryPath = binaryPath
+			cfg.SetOrigin("claude.binary_path", OriginEnv+" BMADUUM_CLAUDE_PATH")
+		}
+	}
+
+	if err := cfg.loadPromptFiles(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// selectedProfile returns the profile to apply and where it was selected:
+// [Loader.SetProfile], then BMADUUM_PROFILE when env is set, then the
+// profile key of the merged files.
+func (l *Loader) selectedProfile(merged map[string]any, origins map[string]string, env bool) (name, origin string) {
+	if l.profile != "" {
+		return strings.ToLower(l.profile), OriginFlag + " --profile"
+	}
+	if p := os.Getenv("BMADUUM_PROFILE"); env && p != "" {
+		return strings.ToLower(p), OriginEnv + " BMADUUM_PROFILE"
+	}
+	p, _ := merged["profile"].(string)
+	return strings.ToLower(p), origins["profile"]
+}
+
+// applyProfile merges the named entry of profiles into merged. A profile
+// cannot select or define other profiles, so those keys are ignored.
+//
+// Returns an error if the profile is not defined.
+func applyProfile(merged map[string]any, name string, origins map[string]string) error {
+	profiles, _ := merged["profiles"].(map[string]any)
+	values, ok := profiles[name].(map[string]any)
+	if !ok {
+		if _, defined := profiles[name]; !defined {
+			names := make([]string, 0, len(profiles))
+			for n := range profiles {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+			if len(names) == 0 {
+				return fmt.Errorf("unknown profile %q (no profiles defined)", name)
+			}
+			return fmt.Errorf("unknown profile %q (defined: %s)", name, strings.Join(names, ", "))
+		}
+		// An empty profile changes nothing
+		values = nil
+	}
+
+	overrides := make(map[string]any, len(values))
+	for key, value := range values {
+		if key != "profile" && key != "profiles" {
+			overrides[key] = value
+		}
+	}
+	mergeLayer(merged, overrides, "", OriginProfile+" "+name, origins)
+	return nil
+}
+
+// readLayer reads a config file into a map with lowercase keys. The file
+// extension determines the format (yaml, yml, json, toml).
+func readLayer(path string) (map[string]any, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if ext := filepath.Ext(path); ext != "" {
+		v.SetConfigType(ext[1:])
+	} else {
+		v.SetConfigType("yaml")
+	}
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("error reading config file %s: %w", path, err)
+	}
+	return v.AllSettings(), nil
+}
+
+// mergeLayer deep-merges src into dst. Maps are merged key by key; any other
+// value, lists included, replaces the value below it. The origin of every
+// value src sets is recorded under its dotted key. A workflow's model or
+// prompt set with one key of [pairedKeys] clears the other key below it.
+func mergeLayer(dst, src map[string]any, prefix, origin string, origins map[string]string) {
+	for key := range src {
+		if pair, ok := pairedKeys[key]; ok {
+			if _, both := src[pair.other]; !both {
+				clearPaired(dst, prefix, key, origins)
+			}
+		}
+	}
+	for key, value := range src {
+		path := prefix + key
+		if srcMap, ok := value.(map[string]any); ok {
+			dstMap, ok := dst[key].(map[string]any)
+			if !ok {
+				dstMap = make(map[string]any)
+				dst[key] = dstMap
+			}
+			mergeLayer(dstMap, srcMap, path+".", origin, origins)
+			continue
+		}
+		dst[key] = value
+		origins[path] = origin
+	}
+}
+
+// pairedKeys are the workflow settings that replace each other, with the
+// value that clears the other one: nil removes it.
+var pairedKeys = map[string]struct {
+	other string
+	zero  any
+}{
+	"prompt_file":     {"prompt_template", ""},
+	"prompt_template": {"prompt_file", ""},
+	"model":           {"models", nil},
+	"models":          {"model", ""},
+}
+
+// clearPaired clears the setting paired with key in the workflow map wf,
+// whose settings have the dotted prefix "workflows.<name>.", so that a layer
+// setting a workflow's model or prompt replaces the one of the layers below
+// it even when they used the other key. The cleared setting reports its
+// default origin again. Other prefixes are left alone.
+func clearPaired(wf map[string]any, prefix, key string, origins map[string]string) {
+	parts := strings.Split(prefix, ".")
+	pair, ok := pairedKeys[key]
+	if !ok || len(parts) != 3 || parts[0] != "workflows" {
+		return
+	}
+	if _, set := wf[pair.other]; !set {
+		return
+	}
+	if pair.zero == nil {
+		delete(wf, pair.other)
+	} else {
+		wf[pair.other] = pair.zero
+	}
+	delete(origins, prefix+pair.other)
+}
+
+// applyEnv overrides values with BMADUUM_ environment variables, named after
+// the dotted key with dots replaced by underscores, e.g. claude.binary_path
+// is BMADUUM_CLAUDE_BINARY_PATH.
+func applyEnv(merged map[string]any, origins map[string]string) {
+	flatten(merged, "", func(key string, value any) {
+		if _, ok := value.(map[string]any); ok {
+			return
+		}
+		name := "BMADUUM_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+		if value, ok := os.LookupEnv(name); ok {
+			setKey(merged, key, value)
+			origins[key] = OriginEnv + " " + name
+			if parts := strings.Split(key, "."); len(parts) == 3 && parts[0] == "workflows" {
+				wf := merged["workflows"].(map[string]any)[parts[1]].(map[string]any)
+				clearPaired(wf, "workflows."+parts[1]+".", parts[2], origins)
+			}
+		}
+	})
+}
+
+// setKey sets the value of a dotted key in nested maps.
+func setKey(m map[string]any, key string, value any) {
+	parts := strings.Split(key, ".")
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := m[part].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			m[part] = next
+		}
+		m = next
+	}
+	m[parts[len(parts)-1]] = value
+}
+
+// flatten calls fn for every leaf value of nested maps with its dotted key,
+// in key order.
+func flatten(m map[string]any, prefix string, fn func(key string, value any)) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if sub, ok := m[k].(map[string]any); ok && len(sub) > 0 {
+			flatten(sub, prefix+k+".", fn)
+			continue
+		}
+		fn(prefix+k, m[k])
+	}
+}
+
+// toMap converts a configuration value to the generic form config files are
+// read into: structs become maps keyed by their mapstructure tags, and maps
+// and slices are converted element by element. Unexported fields and nil
+// maps, slices and pointers are left out. Durations become strings such as
+// "5m0s".
+func toMap(v reflect.Value) any {
+	if d, ok := v.Interface().(time.Duration); ok {
+		return d.String()
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return toMap(v.Elem())
+	case reflect.Struct:
+		m := make(map[string]any)
+		t := v.Type()
+		for i := range t.NumField() {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name := strings.Split(field.Tag.Get("mapstructure"), ",")[0]
+			if name == "" || name == "-" {
+				continue
+			}
+			if value := toMap(v.Field(i)); value != nil {
+				m[name] = value
+			}
+		}
+		return m
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		m := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			m[strings.ToLower(fmt.Sprint(iter.Key().Interface()))] = toMap(iter.Value())
+		}
+		return m
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+		s := make([]any, v.Len())
+		for i := range v.Len() {
+			s[i] = toMap(v.Index(i))
+		}
+		return s
+	default:
+		return v.Interface()
+	}
+}
+
+// Origin returns where the effective value of a dotted key, such as
+// "git.push" or "workflows.dev-story.model", came from: [OriginDefault], or
+// an origin kind followed by the file, environment variable or flag that set
+// it, e.g. "project /repo/.bmaduum.yaml".
+func (c *Config) Origin(key string) string {
+	if origin, ok := c.origins[key]; ok {
+		return origin
+	}
+	return OriginDefault
+}
+
+// SetOrigin records where the value of a dotted key came from, for values
+// changed after loading such as command line flags.
+func (c *Config) SetOrigin(key, origin string) {
+	if c.origins == nil {
+		c.origins = make(map[string]string)
+	}
+	c.origins[key] = origin
+}
+
+// Layers returns the configuration files the config was merged from, lowest
+// precedence first.
+func (c *Config) Layers() []Layer {
+	return c.layers
+}
+
+// originDir returns the directory of the config file that set a dotted key,
+// or an empty string if no file set it. A value set by a profile comes from
+// the file that defined it under profiles.<name>.
+func (c *Config) originDir(key string) string {
+	origin := c.Origin(key)
+	if name, ok := strings.CutPrefix(origin, OriginProfile+" "); ok {
+		origin = c.Origin("profiles." + name + "." + key)
+	}
+	for _, layer := range c.layers {
+		if origin == layer.Origin+" "+layer.Path {
+			return filepath.Dir(layer.Path)
+		}
+	}
+	return ""
+}
+
+// Settings calls fn for every effective setting with its dotted key, in key
+// order. Lists and empty maps are reported as single values.
+func (c *Config) Settings(fn func(key string, value any)) {
+	flatten(toMap(reflect.ValueOf(c)).(map[string]any), "", fn)
+}
+
+// AllSettings returns the effective configuration as nested maps keyed like
+// the config file.
+func (c *Config) AllSettings() map[string]any {
+	return toMap(reflect.ValueOf(c)).(map[string]any)
+}
@@ -0,0 +1,323 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// isolateLayers points the user config directory and the project directory
+// at empty temp directories and clears the config environment variables.
+func isolateLayers(t *testing.T) (userDir, projectDir string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", home)
+	t.Setenv("BMADUUM_CONFIG_PATH", "")
+	t.Setenv("BMADUUM_CLAUDE_PATH", "")
+	return filepath.Join(home, "bmaduum"), t.TempDir()
+}
+
+func TestLoader_Load_DefaultsRoundTrip(t *testing.T) {
+	_, projectDir := isolateLayers(t)
+
+	loader := NewLoader()
+	loader.SetProjectDir(projectDir)
+	cfg, err := loader.Load()
+	require.NoError(t, err)
+
+	assert.Equal(t, OriginDefault, cfg.Origin("claude.binary_path"))
+	assert.Empty(t, cfg.Layers())
+
+	// Merging no layers decodes back to the defaults
+	cfg.origins = nil
+	cfg.layers = nil
+	assert.Equal(t, DefaultConfig(), cfg)
+}
+
+func TestLoader_Load_Layers(t *testing.T) {
+	userDir, projectDir := isolateLayers(t)
+	writeFiles(t, userDir, map[string]string{
+		"workflows.yaml": `
+claude:
+  binary_path: /user/claude
+  output_format: json
+workflows:
+  dev-story:
+    model: opus
+`,
+	})
+	writeFiles(t, projectDir, map[string]string{
+		ProjectConfigFile: `
+claude:
+  binary_path: /project/claude
+workflows:
+  code-review:
+    description: Project review
+`,
+	})
+
+	loader := NewLoader()
+	loader.SetProjectDir(projectDir)
+	cfg, err := loader.Load()
+	require.NoError(t, err)
+
+	userFile := filepath.Join(userDir, "workflows.yaml")
+	projectFile := filepath.Join(projectDir, ProjectConfigFile)
+
+	assert.Equal(t, "/project/claude", cfg.Claude.BinaryPath)
+	assert.Equal(t, "project "+projectFile, cfg.Origin("claude.binary_path"))
+	assert.Equal(t, "json", cfg.Claude.OutputFormat)
+	assert.Equal(t, "user "+userFile, cfg.Origin("claude.output_format"))
+
+	// Workflows are merged key by key and keep their default prompts
+	assert.Equal(t, "opus", cfg.Workflows["dev-story"].Model)
+	assert.Equal(t, DefaultConfig().Workflows["dev-story"].PromptTemplate, cfg.Workflows["dev-story"].PromptTemplate)
+	assert.Equal(t, "Project review", cfg.Workflows["code-review"].Description)
+	assert.NotEmpty(t, cfg.Workflows["code-review"].PromptTemplate)
+	assert.Equal(t, OriginDefault, cfg.Origin("workflows.dev-story.prompt_template"))
+
+	assert.Equal(t, []Layer{
+		{Origin: OriginUser, Path: userFile},
+		{Origin: OriginProject, Path: projectFile},
+	}, cfg.Layers())
+}
+
+func TestLoader_Load_ProjectConfigInParent(t *testing.T) {
+	_, projectDir := isolateLayers(t)
+	writeFiles(t, projectDir, map[string]string{
+		ProjectConfigFile: "git:\n  protected_branches: [release]\n",
+	})
+	subDir := filepath.Join(projectDir, "sub", "dir")
+	writeFiles(t, subDir, map[string]string{"keep": ""})
+
+	loader := NewLoader()
+	loader.SetProjectDir(subDir)
+	cfg, err := loader.Load()
+	require.NoError(t, err)
+
+	// Lists replace the default list
+	assert.Equal(t, []string{"release"}, cfg.Git.ProtectedBranches)
+}
+
+func TestLoader_Load_ConfigPathOverridesProject(t *testing.T) {
+	_, projectDir := isolateLayers(t)
+	writeFiles(t, projectDir, map[string]string{
+		ProjectConfigFile: "claude:\n  binary_path: /project/claude\n",
+		"explicit.yaml":   "claude:\n  binary_path: /explicit/claude\n",
+	})
+	t.Setenv("BMADUUM_CONFIG_PATH", filepath.Join(projectDir, "explicit.yaml"))
+
+	loader := NewLoader()
+	loader.SetProjectDir(projectDir)
+	cfg, err := loader.Load()
+	require.NoError(t, err)
+
+	assert.Equal(t, "/explicit/claude", cfg.Claude.BinaryPath)
+	assert.Equal(t, "file "+filepath.Join(projectDir, "explicit.yaml"), cfg.Origin("claude.binary_path"))
+}
+
+func TestLoader_Load_ConfigPathMissing(t *testing.T) {
+	_, projectDir := isolateLayers(t)
+	t.Setenv("BMADUUM_CONFIG_PATH", filepath.Join(projectDir, "missing.yaml"))
+
+	_, err := NewLoader().Load()
+	assert.ErrorContains(t, err, "error reading config file")
+}
+
+func TestLoader_Load_EnvLayer(t *testing.T) {
+	_, projectDir := isolateLayers(t)
+	writeFiles(t, projectDir, map[string]string{
+		ProjectConfigFile: "workflows:\n  dev-story:\n    model: sonnet\n",
+	})
+	t.Setenv("BMADUUM_WORKFLOWS_DEV-STORY_MODEL", "opus")
+	t.Setenv("BMADUUM_LIFECYCLE_MAX_REVIEW_CYCLES", "5")
+	t.Setenv("BMADUUM_GIT_PROTECTED_BRANCHES", "main,trunk")
+
+	loader := NewLoader()
+	loader.SetProjectDir(projectDir)
+	cfg, err := loader.Load()
+	require.NoError(t, err)
+
+	assert.Equal(t, "opus", cfg.Workflows["dev-story"].Model)
+	assert.Equal(t, "env BMADUUM_WORKFLOWS_DEV-STORY_MODEL", cfg.Origin("workflows.dev-story.model"))
+	assert.Equal(t, 5, cfg.Lifecycle.MaxReviewCycles)
+	assert.Equal(t, []string{"main", "trunk"}, cfg.Git.ProtectedBranches)
+}
+
+func TestLoader_Load_PairedWorkflowKeys(t *testing.T) {
+	userDir, projectDir := isolateLayers(t)
+	writeFiles(t, userDir, map[string]string{
+		"workflows.yaml": `
+workflows:
+  dev-story:
+    models: [opus, sonnet]
+  code-review:
+    prompt_file: review.tmpl
+  create-story:
+    models: [opus, sonnet]
+`,
+		"review.tmpl": "User review {{.StoryKey}}",
+	})
+	writeFiles(t, projectDir, map[string]string{
+		ProjectConfigFile: `
+workflows:
+  dev-story:
+    model: haiku
+  code-review:
+    prompt_template: "Project review {{.StoryKey}}"
+`,
+	})
+	t.Setenv("BMADUUM_WORKFLOWS_CREATE-STORY_MODEL", "haiku")
+
+	loader := NewLoader()
+	loader.SetProjectDir(projectDir)
+	cfg, err := loader.Load()
+	require.NoError(t, err)
+
+	// A layer setting one key of a pair replaces the other key below it
+	assert.Equal(t, []string{"haiku"}, cfg.GetModels("dev-story"))
+	assert.Empty(t, cfg.Workflows["dev-story"].Models)
+	assert.Equal(t, OriginDefault, cfg.Origin("workflows.dev-story.models"))
+
+	assert.Empty(t, cfg.Workflows["code-review"].PromptFile)
+	prompt, err := cfg.GetPrompt("code-review", "6-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Project review 6-1", prompt)
+
+	// So does an environment variable
+	assert.Equal(t, []string{"haiku"}, cfg.GetModels("create-story"))
+
+	assert.Empty(t, cfg.Validate())
+}
+
+func TestConfig_SetVars_Origin(t *testing.T) {
+	cfg := DefaultConfig()
+	require.NoError(t, cfg.SetVars([]string{"Team=core"}))
+
+	assert.Equal(t, "flag --var", cfg.Origin("vars.team"))
+	assert.Equal(t, OriginDefault, cfg.Origin("vars.other"))
+}
+
+func TestConfig_Settings(t *testing.T) {
+	cfg := DefaultConfig()
+
+	settings := make(map[string]any)
+	cfg.Settings(func(key string, value any) { settings[key] = value })
+
+	assert.Equal(t, "claude", settings["claude.binary_path"])
+	assert.Contains(t, settings, "workflows.dev-story.prompt_template")
+	assert.NotContains(t, settings, "workflows")
+}
+
+func TestLoader_SearchPaths(t *testing.T) {
+	userDir, projectDir := isolateLayers(t)
+
+	loader := NewLoader()
+	loader.SetProjectDir(projectDir)
+	assert.Equal(t, []Layer{
+		{Origin: OriginUser, Path: filepath.Join(userDir, "workflows.yaml")},
+		{Origin: OriginProject, Path: filepath.Join(projectDir, "workflows.yaml")},
+		{Origin: OriginProject, Path: filepath.Join(projectDir, "config", "workflows.yaml")},
+		{Origin: OriginProject, Path: filepath.Join(projectDir, ProjectConfigFile)},
+	}, loader.SearchPaths())
+
+	t.Setenv("BMADUUM_CONFIG_PATH", "/etc/bmaduum.yaml")
+	paths := loader.SearchPaths()
+	assert.Equal(t, Layer{Origin: OriginFile, Path: "/etc/bmaduum.yaml"}, paths[len(paths)-1])
+}
+
+func TestLoader_Load_Profile(t *testing.T) {
+	_, projectDir := isolateLayers(t)
+	writeFiles(t, projectDir, map[string]string{
+		ProjectConfigFile: `
+workflows:
+  dev-story:
+    model: sonnet
+lifecycle:
+  max_review_cycles: 3
+profiles:
+  fast:
+    lifecycle:
+      max_review_cycles: 1
+  thorough:
+    workflows:
+      dev-story:
+        model: opus
+      code-review:
+        model: opus
+`,
+	})
+	projectFile := filepath.Join(projectDir, ProjectConfigFile)
+
+	load := func(profile string) (*Config, error) {
+		loader := NewLoader()
+		loader.SetProjectDir(projectDir)
+		loader.SetProfile(profile)
+		return loader.Load()
+	}
+
+	cfg, err := load("")
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Profile)
+	assert.Equal(t, "sonnet", cfg.Workflows["dev-story"].Model)
+	assert.ElementsMatch(t, []string{"fast", "thorough"}, func() []string {
+		var names []string
+		for name := range cfg.Profiles {
+			names = append(names, name)
+		}
+		return names
+	}())
+
+	cfg, err = load("Thorough")
+	require.NoError(t, err)
+	assert.Equal(t, "thorough", cfg.Profile)
+	assert.Equal(t, "flag --profile", cfg.Origin("profile"))
+	assert.Equal(t, "opus", cfg.Workflows["dev-story"].Model)
+	assert.Equal(t, "profile thorough", cfg.Origin("workflows.dev-story.model"))
+	assert.Equal(t, DefaultConfig().Workflows["code-review"].PromptTemplate, cfg.Workflows["code-review"].PromptTemplate)
+	assert.Equal(t, 3, cfg.Lifecycle.MaxReviewCycles)
+	assert.Equal(t, "project "+projectFile, cfg.Origin("lifecycle.max_review_cycles"))
+
+	// The flag wins over BMADUUM_PROFILE, and environment variables over the profile
+	t.Setenv("BMADUUM_PROFILE", "thorough")
+	t.Setenv("BMADUUM_LIFECYCLE_MAX_REVIEW_CYCLES", "2")
+	cfg, err = load("fast")
+	require.NoError(t, err)
+	assert.Equal(t, "fast", cfg.Profile)
+	assert.Equal(t, "sonnet", cfg.Workflows["dev-story"].Model)
+	assert.Equal(t, 2, cfg.Lifecycle.MaxReviewCycles)
+
+	os.Unsetenv("BMADUUM_LIFECYCLE_MAX_REVIEW_CYCLES")
+	cfg, err = load("")
+	require.NoError(t, err)
+	assert.Equal(t, "thorough", cfg.Profile)
+	assert.Equal(t, "env BMADUUM_PROFILE", cfg.Origin("profile"))
+
+	_, err = load("turbo")
+	assert.EqualError(t, err, `unknown profile "turbo" (defined: fast, thorough)`)
+}
+
+func TestLoader_LoadFromFile_DefaultProfile(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"bmaduum.yaml": `
+profile: fast
+profiles:
+  fast:
+    git:
+      push: false
+`,
+	})
+
+	cfg, err := NewLoader().LoadFromFile(filepath.Join(dir, "bmaduum.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "fast", cfg.Profile)
+	assert.False(t, cfg.Git.Push)
+
+	writeFiles(t, dir, map[string]string{"none.yaml": "profile: fast\n"})
+	_, err = NewLoader().LoadFromFile(filepath.Join(dir, "none.yaml"))
+	assert.EqualError(t, err, `unknown profile "fast" (no profiles defined)`)
+}
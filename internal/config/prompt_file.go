@@ -0,0 +1,72 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"text/template"
+)
+
+// partialPattern matches the partials parsed with a prompt file. Only files
+// starting with an underscore are partials, so other prompt files in the
+// same directory are not parsed into each other.
+const partialPattern = "_*.tmpl"
+
+// loadPromptFiles parses the prompt_file of every workflow that sets one.
+//
+// Relative paths are resolved against the directory of the config file that
+// set the prompt_file, or the current directory for other sources. The
+// partials next to a prompt file (see [partialPattern]) are parsed with it,
+// so they can be included by file name ({{template "_footer.tmpl" .}}) or
+// through {{define}} blocks. A prompt file replaces the workflow's
+// prompt_template.
+//
+// Returns an error naming the workflow, file and line if a file cannot be
+// read or parsed.
+func (c *Config) loadPromptFiles() error {
+	for _, name := range c.WorkflowNames() {
+		wf := c.Workflows[name]
+		if wf.PromptFile == "" {
+			continue
+		}
+
+		path := wf.PromptFile
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(c.originDir("workflows."+name+".prompt_file"), path)
+		}
+
+		t, err := parsePromptFile(path)
+		if err != nil {
+			return fmt.Errorf("workflow %s: prompt_file: %w", name, err)
+		}
+		wf.prompt = t
+		c.Workflows[name] = wf
+	}
+	return nil
+}
+
+// parsePromptFile parses the template in path together with the partials in
+// its directory. The returned template executes path.
+//
+// Parse errors from text/template already name the file and line, e.g.
+// "template: code-review.md.tmpl:12: unexpected EOF".
+func parsePromptFile(path string) (*template.Template, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+
+	files, err := filepath.Glob(filepath.Join(filepath.Dir(path), partialPattern))
+	if err != nil {
+		return nil, err
+	}
+	// Parse the prompt file last so its own definitions win over partials
+	files = slices.DeleteFunc(files, func(f string) bool { return filepath.Base(f) == filepath.Base(path) })
+	files = append(files, path)
+
+	t, err := template.New(filepath.Base(path)).Funcs(templateFuncs).Option("missingkey=zero").ParseFiles(files...)
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
@@ -0,0 +1,133 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeFiles writes name → content pairs below dir, creating directories.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func TestLoader_LoadFromFile_PromptFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"bmaduum.yaml": `
+workflows:
+  code-review:
+    prompt_file: prompts/code-review.md.tmpl
+  dev-story:
+    prompt_file: prompts/dev-story.md.tmpl
+`,
+		"prompts/code-review.md.tmpl": "Review {{.StoryKey}}.\n{{template \"_footer.tmpl\" .}}",
+		"prompts/dev-story.md.tmpl":   "Develop {{.StoryKey}}.\n{{template \"no-questions\"}}",
+		"prompts/_footer.tmpl":        "Story {{.StoryKey}}: {{template \"no-questions\"}}",
+		"prompts/_partials.tmpl":      `{{define "no-questions"}}Do not ask questions.{{end}}`,
+		"prompts/draft.tmpl":          "{{if .StoryKey}}not a partial, never parsed",
+	})
+
+	cfg, err := NewLoader().LoadFromFile(filepath.Join(dir, "bmaduum.yaml"))
+	require.NoError(t, err)
+
+	prompt, err := cfg.GetPrompt("code-review", "6-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Review 6-1.\nStory 6-1: Do not ask questions.", prompt, "prompt_file replaces the default prompt_template")
+
+	prompt, err = cfg.GetPrompt("dev-story", "6-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Develop 6-1.\nDo not ask questions.", prompt)
+
+	prompt, err = cfg.GetPrompt("create-story", "6-1")
+	require.NoError(t, err)
+	assert.Contains(t, prompt, "6-1", "workflows without prompt_file keep their prompt_template")
+}
+
+func TestLoader_LoadFromFile_PromptFileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr []string
+	}{
+		{
+			name: "parse error names file and line",
+			files: map[string]string{
+				"prompts/review.md.tmpl": "line one\nline two {{.StoryKey\n",
+			},
+			wantErr: []string{"workflow code-review", "review.md.tmpl:2"},
+		},
+		{
+			name: "parse error in a partial",
+			files: map[string]string{
+				"prompts/review.md.tmpl": "Review {{template \"_footer.tmpl\" .}}",
+				"prompts/_footer.tmpl":   "{{if .StoryKey}}unterminated",
+			},
+			wantErr: []string{"_footer.tmpl:1"},
+		},
+		{
+			name:    "missing file",
+			files:   map[string]string{},
+			wantErr: []string{"workflow code-review", "review.md.tmpl"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			tt.files["bmaduum.yaml"] = "workflows:\n  code-review:\n    prompt_file: prompts/review.md.tmpl\n"
+			writeFiles(t, dir, tt.files)
+
+			_, err := NewLoader().LoadFromFile(filepath.Join(dir, "bmaduum.yaml"))
+			require.Error(t, err)
+			for _, want := range tt.wantErr {
+				assert.ErrorContains(t, err, want)
+			}
+		})
+	}
+}
+
+func TestLoader_Load_PromptFileRelativeToConfig(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"conf/workflows.yaml": "workflows:\n  dev-story:\n    prompt_file: dev.tmpl\n",
+		"conf/dev.tmpl":       "Dev {{.StoryKey}}",
+	})
+	t.Chdir(t.TempDir())
+	t.Setenv("BMADUUM_CONFIG_PATH", filepath.Join(dir, "conf", "workflows.yaml"))
+
+	cfg, err := NewLoader().Load()
+	require.NoError(t, err)
+
+	prompt, err := cfg.GetPrompt("dev-story", "6-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Dev 6-1", prompt)
+}
+
+func TestLoader_Load_ProfilePromptFileRelativeToConfig(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"conf/workflows.yaml":    "profiles:\n  fast:\n    workflows:\n      dev-story:\n        prompt_file: prompts/fast.tmpl\n",
+		"conf/prompts/fast.tmpl": "Fast {{.StoryKey}}",
+	})
+	t.Chdir(t.TempDir())
+	t.Setenv("BMADUUM_CONFIG_PATH", filepath.Join(dir, "conf", "workflows.yaml"))
+
+	loader := NewLoader()
+	loader.SetProfile("fast")
+	cfg, err := loader.Load()
+	require.NoError(t, err)
+
+	prompt, err := cfg.GetPrompt("dev-story", "6-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Fast 6-1", prompt)
+	assert.Equal(t, "profile fast", cfg.Origin("workflows.dev-story.prompt_file"))
+}
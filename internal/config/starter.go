@@ -0,0 +1,99 @@
+package config
+
+// StarterConfig is the commented configuration file written by
+// "bmaduum config init".
+//
+// Every setting is commented out, so the file starts out changing nothing:
+// configuration files are merged over the built-in defaults, and settings
+// left out keep their default value.
+const StarterConfig = `# bmaduum configuration
+#
+# This file is merged over the built-in defaults and the other config layers
+# (run "bmaduum config path" to list them). Maps are merged key by key, so
+# only the settings you change need to be here. Uncomment to override.
+#
+# Run "bmaduum config show --origin" to see the effective configuration and
+# "bmaduum config validate" to check it.
+
+# workflows:
+#   # Change one setting of a built-in workflow and keep its prompt
+#   dev-story:
+#     models: [opus, sonnet] # Falls back to sonnet when opus hits its limit
+#     model_fallback: rate_limit # rate_limit, overload or never
+#     gates: # Commands that must pass before the status advances
+#       - name: tests
+#         command: go test ./...
+#     fix_attempts: 2
+#
+#   # Claude CLI options: keep code review read-only
+#   code-review:
+#     disallowed_tools: [Edit, Write, NotebookEdit]
+#     max_turns: 40
+#
+#   # Only allow git commands; permission_mode replaces
+#   # --dangerously-skip-permissions
+#   git-commit:
+#     permission_mode: dontAsk
+#     allowed_tools: ["Bash(git:*)"]
+#     # append_system_prompt, mcp_config, add_dirs, extra_args
+#
+#   # Add your own workflow, runnable as "bmaduum workflow security-review <story>"
+#   security-review:
+#     description: Review the story's changes for security issues
+#     prompt_template: "Review the changes for story {{.StoryKey}} for security issues."
+#     # prompt_file: prompts/security-review.md.tmpl
+
+# claude:
+#   binary_path: claude
+#   prompt_stdin: auto # auto (over stdin_threshold bytes), always or never
+#   stdin_threshold: 16384
+
+# agent:
+#   backend: claude # claude, or command for another agent CLI:
+#   command:
+#     path: my-agent
+#     args: [exec, --json]
+#     events: # Map its JSON output lines to events
+#       - match: [{field: type, equals: message}]
+#         kind: text
+#         fields: {text: content}
+
+# lifecycle:
+#   verify: true # Check create-story and dev-story advanced the story (off by default)
+#   max_review_cycles: 3
+#   failure_policy: keep # keep, stash or reset
+#   steps: # Replaces the standard lifecycle
+#     - workflow: create-story
+#       next_status: ready-for-dev
+#     - workflow: dev-story
+#       next_status: review
+#     - workflow: code-review
+#       next_status: done
+#     - workflow: git-commit
+
+# git:
+#   preflight: true
+#   protected_branches: [main, master]
+#   branch_per_story: false
+#   branch_template: "story/{{.StoryKey}}"
+#   commit_mode: claude # claude or native
+#   push: true
+
+# status:
+#   path: "" # sprint-status.yaml location relative to the project root
+
+# vars: # Available as {{.Vars.name}} in every template
+#   ticket_prefix: PROJ
+
+# profiles: # Selected with --profile or BMADUUM_PROFILE
+#   fast:
+#     workflows:
+#       dev-story: { model: sonnet }
+#       code-review: { model: sonnet }
+#     lifecycle:
+#       max_review_cycles: 1
+#   thorough:
+#     workflows:
+#       dev-story: { model: opus }
+#       code-review: { model: opus }
+`
@@ -10,19 +10,29 @@
 //   - [Loader] handles Viper-based configuration loading
 //   - [WorkflowConfig] defines a single workflow's prompt template
 //   - [ClaudeConfig] contains Claude CLI binary settings
+//   - [AgentConfig] selects the agent backend that runs prompts
+//   - [GitConfig] contains git safety settings
+//   - [Workspace] lists project roots for multi-project runs
 //
-// Configuration priority (highest to lowest):
-//  1. Environment variables (BMADUUM_ prefix)
-//  2. Config file specified by BMADUUM_CONFIG_PATH
-//  3. User config directory (platform-standard):
+// Configuration layers (lowest to highest priority), deep-merged:
+//  1. [DefaultConfig] defaults
+//  2. User config directory (platform-standard):
 //     - Linux: ~/.config/bmaduum/workflows.yaml
 //     - macOS: ~/Library/Application Support/bmaduum/workflows.yaml
 //     - Windows: %APPDATA%\bmaduum\workflows.yaml
-//  4. ./config/workflows.yaml (legacy fallback)
-//  5. ./workflows.yaml (legacy fallback)
-//  6. [DefaultConfig] defaults
+//  3. ./workflows.yaml and ./config/workflows.yaml (legacy locations)
+//  4. Project config: .bmaduum.yaml in the project directory or a parent
+//  5. Config file specified by BMADUUM_CONFIG_PATH
+//  6. The active profile from profiles (--profile or BMADUUM_PROFILE)
+//  7. Environment variables (BMADUUM_ prefix)
+//  8. Command line flags
 package config
 
+import (
+	"text/template"
+	"time"
+)
+
 // Config represents the root configuration structure.
 //
 // This is the main configuration container loaded by [Loader] and used throughout
@@ -39,8 +49,148 @@ type Config struct {
 	// Claude contains Claude CLI binary configuration.
 	Claude ClaudeConfig `mapstructure:"claude"`
 
+	// Agent selects the coding agent backend that runs workflow prompts.
+	Agent AgentConfig `mapstructure:"agent"`
+
 	// Output contains terminal output formatting configuration.
 	Output OutputConfig `mapstructure:"output"`
+
+	// Status contains sprint status file configuration.
+	Status StatusConfig `mapstructure:"status"`
+
+	// Lifecycle contains story lifecycle execution settings.
+	Lifecycle LifecycleConfig `mapstructure:"lifecycle"`
+
+	// Git contains git safety settings for story and epic runs.
+	Git GitConfig `mapstructure:"git"`
+
+	// Vars are user-defined values available to every prompt template as
+	// {{.Vars.name}}. Names are stored lowercase, so templates must refer
+	// to them in lowercase; [Config.Validate] reports those that do not.
+	// Overridden per run with --var name=value.
+	Vars map[string]string `mapstructure:"vars"`
+
+	// Profile is the active profile, one of Profiles. Selected with
+	// --profile or BMADUUM_PROFILE, or set here to use a profile by default.
+	// Default: "" (no profile)
+	Profile string `mapstructure:"profile"`
+
+	// Profiles are named sets of overrides for any part of the
+	// configuration, such as models, templates, gates and timeouts, e.g. a
+	// "fast" and a "thorough" profile. The active profile is merged over the
+	// config files, below environment variables and flags.
+	Profiles map[string]map[string]any `mapstructure:"profiles"`
+
+	// origins maps dotted keys to the layer that set them. See [Config.Origin].
+	origins map[string]string
+
+	// layers lists the config files merged into this config.
+	layers []Layer
+}
+
+// GitConfig contains git safety settings for story and epic runs.
+type GitConfig struct {
+	// Preflight enables the checks run before story and epic start: clean
+	// working tree, no protected branch, no rebase or merge in progress and
+	// a resolvable claude binary. Failures refuse the run unless --force.
+	// Default: true
+	Preflight bool `mapstructure:"preflight"`
+
+	// ProtectedBranches lists branches the preflight refuses to run on.
+	// Default: ["main", "master"]
+	ProtectedBranches []string `mapstructure:"protected_branches"`
+
+	// BranchPerStory checks out a dedicated branch for each story before its
+	// first workflow runs. The protected branch check is skipped when set,
+	// since the agent never works on the base branch itself.
+	// Default: false
+	BranchPerStory bool `mapstructure:"branch_per_story"`
+
+	// BranchTemplate names story branches. It is a Go template expanded with
+	// [PromptData], e.g. "feature/{{.EpicID}}/{{.StoryKey}}".
+	// Default: "story/{{.StoryKey}}"
+	BranchTemplate string `mapstructure:"branch_template"`
+
+	// BaseBranch is the branch story branches are created from. When empty,
+	// the branch checked out when the run starts is used.
+	BaseBranch string `mapstructure:"base_branch"`
+
+	// ReturnToBase switches back to the base branch after a story is done.
+	// When false the story branch stays checked out for review.
+	// Default: true
+	ReturnToBase bool `mapstructure:"return_to_base"`
+
+	// CommitMode decides how the git-commit step runs: "claude" runs the
+	// git-commit workflow as a Claude session, "native" stages and commits
+	// itself and only asks Claude for the message (see
+	// [CommitMessageWorkflow]).
+	// Default: "claude"
+	CommitMode string `mapstructure:"commit_mode"`
+
+	// Push pushes the story's commit to the current branch. In claude mode it
+	// is passed to the git-commit prompt as {{.Push}}. Disabled by --no-push.
+	// Default: true
+	Push bool `mapstructure:"push"`
+}
+
+// Values for [GitConfig.CommitMode].
+const (
+	CommitModeClaude = "claude"
+	CommitModeNative = "native"
+)
+
+// LifecycleConfig contains story lifecycle execution settings.
+type LifecycleConfig struct {
+	// Verify enables post-step verification: after create-story the story
+	// file must exist and after dev-story the story must be complete. A
+	// failed check fails the step. Opt-in, since it can fail runs that used
+	// to pass. git-commit is always checked for a new commit referencing the
+	// story key.
+	// Default: false
+	Verify bool `mapstructure:"verify"`
+
+	// MaxReviewCycles is how many times code-review may run for a story.
+	// A review that requests changes sends the story back through dev-story
+	// and code-review until a review comes back clean or this many reviews
+	// have run.
+	// Default: 3
+	MaxReviewCycles int `mapstructure:"max_review_cycles"`
+
+	// FailurePolicy decides what happens to a failed story's partial work:
+	// "keep" leaves it in the working tree, "stash" stashes it under a stash
+	// named after the story and "reset" resets to the commit the story
+	// started from. Stash and reset move the story back to its start status.
+	// Default: "keep"
+	FailurePolicy string `mapstructure:"failure_policy"`
+
+	// Hooks run around each story's whole lifecycle: pre before its first
+	// step, post once it is done, and on_failure when it fails.
+	Hooks HooksConfig `mapstructure:"hooks"`
+
+	// Steps replaces the standard create-story → dev-story → code-review →
+	// git-commit lifecycle, e.g. to add a custom workflow after dev-story.
+	// A story starts at the first step that runs from its current status.
+	// Default: empty (the standard lifecycle)
+	Steps []LifecycleStepConfig `mapstructure:"steps"`
+}
+
+// LifecycleStepConfig is one step of a configured lifecycle.
+type LifecycleStepConfig struct {
+	// Workflow is the workflow to run; it must be defined in workflows.
+	Workflow string `mapstructure:"workflow"`
+
+	// NextStatus is the story status after the step succeeds. When empty
+	// the status is left as it was, so the step runs from the same status
+	// as the step after it.
+	NextStatus string `mapstructure:"next_status"`
+}
+
+// StatusConfig contains sprint status file configuration.
+type StatusConfig struct {
+	// Path is the location of sprint-status.yaml relative to the project root.
+	// If empty, BMAD's _bmad/bmm/config.yaml output folder is honoured, falling
+	// back to _bmad-output/implementation-artifacts/sprint-status.yaml.
+	Path string `mapstructure:"path"`
 }
 
 // WorkflowConfig represents a single workflow configuration.
@@ -48,17 +198,208 @@ type Config struct {
 // Each workflow has a prompt template that is expanded with story data
 // using Go's text/template package.
 type WorkflowConfig struct {
+	// Description is a one-line summary shown in "bmaduum workflow" help and
+	// shell completions.
+	Description string `mapstructure:"description"`
+
 	// PromptTemplate is the Go template string for the workflow prompt.
 	// Use {{.StoryKey}} to reference the story key.
 	// Example: "Work on story: {{.StoryKey}}"
 	PromptTemplate string `mapstructure:"prompt_template"`
 
+	// PromptFile is a file holding the prompt template, used instead of
+	// PromptTemplate. Relative paths are resolved against the directory of
+	// the config file. Other *.tmpl files in the same directory can be
+	// included with {{template "name.tmpl" .}}.
+	// Example: "prompts/code-review.md.tmpl"
+	PromptFile string `mapstructure:"prompt_file"`
+
+	// prompt is the parsed PromptFile, set when the config is loaded.
+	prompt *template.Template
+
 	// Model is the Claude model to use for this workflow.
 	// If empty, the default model is used.
 	// Examples: "opus", "sonnet", "haiku", "claude-sonnet-4-5-20250929"
 	Model string `mapstructure:"model"`
+
+	// Models is a fallback chain used instead of Model: the first model is
+	// used until it hits a limit covered by ModelFallback, then the next.
+	// Example: ["opus", "sonnet"]
+	Models []string `mapstructure:"models"`
+
+	// ModelFallback is when to move on to the next model of Models:
+	// "rate_limit" (default) on usage and rate limits, "overload" also when
+	// the API is overloaded, and "never" to wait for the limit to reset.
+	ModelFallback string `mapstructure:"model_fallback"`
+
+	// AllowedTools are the tools Claude may use, as Claude CLI permission
+	// rules. They only restrict Claude when PermissionMode is set.
+	// Example: ["Read", "Bash(git:*)"]
+	AllowedTools []string `mapstructure:"allowed_tools"`
+
+	// DisallowedTools are tools Claude may not use.
+	// Example: ["Edit", "Write"]
+	DisallowedTools []string `mapstructure:"disallowed_tools"`
+
+	// PermissionMode is the Claude CLI permission mode: default, acceptEdits,
+	// plan, dontAsk or bypassPermissions. If empty, permission checks are
+	// skipped.
+	PermissionMode string `mapstructure:"permission_mode"`
+
+	// MaxTurns limits the number of agentic turns. 0 means no limit.
+	MaxTurns int `mapstructure:"max_turns"`
+
+	// AppendSystemPrompt is text appended to Claude's system prompt.
+	AppendSystemPrompt string `mapstructure:"append_system_prompt"`
+
+	// MCPConfig is an MCP server configuration file or JSON string.
+	// Relative paths are resolved by Claude against the project root.
+	MCPConfig string `mapstructure:"mcp_config"`
+
+	// AddDirs are additional directories Claude may access.
+	AddDirs []string `mapstructure:"add_dirs"`
+
+	// ExtraArgs are passed to the Claude CLI as-is, for options without a
+	// setting of their own.
+	ExtraArgs []string `mapstructure:"extra_args"`
+
+	// Gates are shell commands that must pass after the workflow, before
+	// the story status is updated (e.g., "go test ./...").
+	Gates []GateConfig `mapstructure:"gates"`
+
+	// FixAttempts is how many follow-up fix-gates sessions may try to make
+	// failing gates pass. With 0, a failing gate stops the lifecycle.
+	FixAttempts int `mapstructure:"fix_attempts"`
+
+	// Hooks are shell commands run around the workflow in the lifecycle:
+	// pre before it starts, post after it exits successfully, and on_failure
+	// when the step fails.
+	Hooks HooksConfig `mapstructure:"hooks"`
+}
+
+// Values for [WorkflowConfig.ModelFallback].
+const (
+	ModelFallbackRateLimit = "rate_limit"
+	ModelFallbackOverload  = "overload"
+	ModelFallbackNever     = "never"
+)
+
+// GateConfig defines a quality gate: a shell command run after a workflow.
+type GateConfig struct {
+	// Name identifies the gate in output. Defaults to the command.
+	Name string `mapstructure:"name"`
+
+	// Command is run with the system shell (sh -c, or cmd /C on Windows)
+	// in the project root.
+	Command string `mapstructure:"command"`
+
+	// Timeout limits how long the command may run (e.g., "10m").
+	// Default: 10 minutes.
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// ExitCodes lists the exit codes that count as passing. Default: [0].
+	ExitCodes []int `mapstructure:"exit_codes"`
+}
+
+// DefaultGateTimeout is the timeout for gates that do not set one.
+const DefaultGateTimeout = 10 * time.Minute
+
+// DisplayName returns the gate's name, or its command when no name is set.
+func (g GateConfig) DisplayName() string {
+	if g.Name != "" {
+		return g.Name
+	}
+	return g.Command
+}
+
+// HooksConfig lists the hook commands for each stage.
+type HooksConfig struct {
+	// Pre hooks run before the workflow or story starts.
+	Pre []HookConfig `mapstructure:"pre"`
+
+	// Post hooks run after the workflow or story succeeds.
+	Post []HookConfig `mapstructure:"post"`
+
+	// OnFailure hooks run after the workflow or story fails. Their own
+	// failures are only reported.
+	OnFailure []HookConfig `mapstructure:"on_failure"`
 }
 
+// HookConfig defines a hook: a shell command run around a workflow.
+//
+// Hooks receive the context through environment variables: BMADUUM_HOOK,
+// BMADUUM_STORY_KEY, BMADUUM_WORKFLOW, BMADUUM_EXIT_CODE, BMADUUM_ERROR,
+// BMADUUM_RUN_ID and BMADUUM_TRANSCRIPT_PATH.
+type HookConfig struct {
+	// Name identifies the hook in output. Defaults to the command.
+	Name string `mapstructure:"name"`
+
+	// Command is run with the system shell in the project root.
+	Command string `mapstructure:"command"`
+
+	// Timeout limits how long the command may run. Default: 5 minutes.
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// OnError decides what a failing hook does: "fail" stops the lifecycle,
+	// "warn" prints a warning and continues, "ignore" continues silently.
+	// Default: "fail".
+	OnError string `mapstructure:"on_error"`
+}
+
+// Values for [LifecycleConfig.FailurePolicy].
+const (
+	FailurePolicyKeep  = "keep"
+	FailurePolicyStash = "stash"
+	FailurePolicyReset = "reset"
+)
+
+// Values for [HookConfig.OnError].
+const (
+	HookOnErrorFail   = "fail"
+	HookOnErrorWarn   = "warn"
+	HookOnErrorIgnore = "ignore"
+)
+
+// DefaultHookTimeout is the timeout for hooks that do not set one.
+const DefaultHookTimeout = 5 * time.Minute
+
+// DisplayName returns the hook's name, or its command when no name is set.
+func (h HookConfig) DisplayName() string {
+	if h.Name != "" {
+		return h.Name
+	}
+	return h.Command
+}
+
+// GitCommitWorkflow is the lifecycle step that commits a story's changes.
+const GitCommitWorkflow = "git-commit"
+
+// CommitMessageWorkflow is the workflow that writes the commit message in
+// native commit mode (see [GitConfig.CommitMode]).
+//
+// Its template receives {{.StoryKey}} and the staged changes as {{.Diff}}.
+// By default it runs for a single turn with the editing, shell and web tools
+// disallowed, since the message only needs the diff; setting a small model
+// keeps it cheap.
+const CommitMessageWorkflow = "commit-message"
+
+// FixGatesWorkflow is the workflow run to fix failing quality gates.
+//
+// Its template receives {{.StoryKey}}, the {{.Workflow}} the gates ran after,
+// and the failing gates' output as {{.GateOutput}}.
+const FixGatesWorkflow = "fix-gates"
+
+// InternalWorkflows are run by bmaduum itself as part of another step, with
+// template data only that step provides. "bmaduum workflow" does not run
+// them on their own.
+var InternalWorkflows = []string{CommitMessageWorkflow, FixGatesWorkflow}
+
+// RetrospectiveWorkflow is the name of the optional epic retrospective workflow.
+//
+// When a workflow with this name is configured, the epic command runs it once
+// all of an epic's stories are done. It is not part of [DefaultConfig].
+const RetrospectiveWorkflow = "retrospective"
+
 // FullCycleConfig defines the steps for a full development cycle.
 //
 // This configuration is used by the run, queue, and epic commands
@@ -81,6 +422,119 @@ type ClaudeConfig struct {
 	// Default: "claude" (assumes Claude is in PATH).
 	// Can be overridden with BMADUUM_CLAUDE_PATH environment variable.
 	BinaryPath string `mapstructure:"binary_path"`
+
+	// PromptStdin decides when prompts are written to Claude's stdin instead
+	// of being passed on the command line, where they are limited in size
+	// and visible to other users in the process list: "auto" above
+	// StdinThreshold, "always" or "never".
+	// Default: "auto"
+	PromptStdin string `mapstructure:"prompt_stdin"`
+
+	// StdinThreshold is the prompt size in bytes above which "auto" sends
+	// the prompt over stdin.
+	// Default: 16384
+	StdinThreshold int `mapstructure:"stdin_threshold"`
+}
+
+// Values for [ClaudeConfig.PromptStdin].
+const (
+	PromptStdinAuto   = "auto"
+	PromptStdinAlways = "always"
+	PromptStdinNever  = "never"
+)
+
+// AgentConfig selects the coding agent backend that runs workflow prompts.
+type AgentConfig struct {
+	// Backend names the agent backend: "claude" runs the Claude CLI
+	// configured under claude, "command" runs the agent CLI configured
+	// under Command.
+	// Default: "claude"
+	Backend string `mapstructure:"backend"`
+
+	// Command configures the "command" backend.
+	Command CommandAgentConfig `mapstructure:"command"`
+}
+
+// Built-in values for [AgentConfig.Backend].
+const (
+	AgentBackendClaude  = "claude"
+	AgentBackendCommand = "command"
+)
+
+// CommandAgentConfig configures the "command" agent backend, which runs any
+// agent CLI that writes one JSON object per line to stdout and maps those
+// lines to events with Events.
+type CommandAgentConfig struct {
+	// Path is the agent binary, looked up in PATH when it has no directory.
+	Path string `mapstructure:"path"`
+
+	// Args are passed to the agent before the prompt.
+	Args []string `mapstructure:"args"`
+
+	// Prompt decides how the prompt is passed: "stdin" writes it to the
+	// agent's stdin, "arg" passes it as the last argument.
+	// Default: "stdin"
+	Prompt string `mapstructure:"prompt"`
+
+	// ModelFlag is the flag that selects the model, e.g. "--model". When
+	// empty, workflow models are not passed to the agent.
+	ModelFlag string `mapstructure:"model_flag"`
+
+	// Events map the agent's output lines to events. Each line is checked
+	// against the rules in order and the first match is used; lines that
+	// match no rule are ignored.
+	Events []EventMapping `mapstructure:"events"`
+}
+
+// Values for [CommandAgentConfig.Prompt].
+const (
+	AgentPromptStdin = "stdin"
+	AgentPromptArg   = "arg"
+)
+
+// EventMapping maps the agent output lines it matches to an event.
+type EventMapping struct {
+	// Match lists the conditions a line must meet, all of them. An empty
+	// list matches every line.
+	Match []FieldMatch `mapstructure:"match"`
+
+	// Kind is the event the line becomes: session_start, text, tool_use,
+	// tool_result or result (see [EventKinds]).
+	Kind string `mapstructure:"kind"`
+
+	// Fields map event fields (see [EventFields]) to dotted paths into the
+	// line, e.g. text: item.text. Array elements are selected by index,
+	// e.g. content.0.text.
+	Fields map[string]string `mapstructure:"fields"`
+}
+
+// FieldMatch is a condition on a field of an agent output line.
+type FieldMatch struct {
+	// Field is the dotted path of the field, e.g. item.type.
+	Field string `mapstructure:"field"`
+
+	// Equals is the value the field must have. Numbers and booleans are
+	// compared in their JSON form, e.g. "true" or "42".
+	Equals string `mapstructure:"equals"`
+}
+
+// Values for [EventMapping.Kind].
+const (
+	EventKindSessionStart = "session_start"
+	EventKindText         = "text"
+	EventKindToolUse      = "tool_use"
+	EventKindToolResult   = "tool_result"
+	EventKindResult       = "result"
+)
+
+// EventKinds are the valid values for [EventMapping.Kind].
+var EventKinds = []string{EventKindSessionStart, EventKindText, EventKindToolUse, EventKindToolResult, EventKindResult}
+
+// EventFields are the event fields an [EventMapping] can set.
+var EventFields = []string{
+	"text", "tool_id", "tool_use_id", "tool_name", "tool_description", "tool_command",
+	"tool_file_path", "tool_stdout", "tool_stderr", "session_id", "result", "is_error",
+	"input_tokens", "output_tokens",
 }
 
 // OutputConfig contains terminal output formatting configuration.
@@ -134,24 +588,46 @@ func DefaultConfig() *Config {
 	return &Config{
 		Workflows: map[string]WorkflowConfig{
 			"create-story": {
+				Description:    "Create a story definition from backlog",
 				PromptTemplate: "/bmad-bmm-create-story - Create story: {{.StoryKey}}. Do not ask questions.",
 			},
 			"dev-story": {
+				Description:    "Implement a story (ready-for-dev or in-progress)",
 				PromptTemplate: "/bmad-bmm-dev-story - Work on story: {{.StoryKey}}. Complete all tasks. Run tests after each implementation. Do not ask clarifying questions - use best judgment based on existing patterns.",
 			},
 			"code-review": {
-				PromptTemplate: "/bmad-bmm-code-review - Review story: {{.StoryKey}}. When presenting fix options, always choose to auto-fix all issues immediately. Do not wait for user input.",
+				Description:    "Review code changes (review status)",
+				PromptTemplate: "/bmad-bmm-code-review - Review story: {{.StoryKey}}. When presenting fix options, always choose to auto-fix all issues immediately. Do not wait for user input. End your final message with REVIEW_RESULT: APPROVED if no issues remain, or REVIEW_RESULT: CHANGES_REQUESTED if action items remain.",
 			},
-			"git-commit": {
-				PromptTemplate: "Commit all changes for story {{.StoryKey}} with a descriptive commit message following conventional commits format. Then push to the current branch. Do not ask questions.",
+			GitCommitWorkflow: {
+				Description:    "Commit and push changes after review",
+				PromptTemplate: "Commit all changes for story {{.StoryKey}} with a descriptive commit message following conventional commits format that references {{.StoryKey}}.{{if .Push}} Then push to the current branch.{{end}} Do not ask questions.",
+			},
+			CommitMessageWorkflow: {
+				Description:     "Write the commit message for native commits",
+				PromptTemplate:  "Write a commit message for the staged changes of story {{.StoryKey}} below, following the conventional commits format. Reference {{.StoryKey}} in the message. Reply with the commit message only, without code fences or commentary, and do not use any tools.\n\n{{.Diff}}",
+				DisallowedTools: []string{"Bash", "Edit", "Write", "NotebookEdit", "Task", "WebFetch", "WebSearch"},
+				MaxTurns:        1,
+			},
+			FixGatesWorkflow: {
+				Description:    "Fix the code after quality gates failed",
+				PromptTemplate: "Story {{.StoryKey}}: these checks failed after {{.Workflow}}. Fix the code so they pass. Do not weaken or skip the checks. Do not ask questions.\n\n{{.GateOutput}}",
 			},
 		},
 		FullCycle: FullCycleConfig{
 			Steps: []string{"create-story", "dev-story", "code-review", "git-commit"},
 		},
 		Claude: ClaudeConfig{
-			OutputFormat: "stream-json",
-			BinaryPath:   "claude",
+			OutputFormat:   "stream-json",
+			BinaryPath:     "claude",
+			PromptStdin:    PromptStdinAuto,
+			StdinThreshold: 16 * 1024,
+		},
+		Agent: AgentConfig{
+			Backend: AgentBackendClaude,
+			Command: CommandAgentConfig{
+				Prompt: AgentPromptStdin,
+			},
 		},
 		Output: OutputConfig{
 			TruncateLines:  20,
@@ -163,6 +639,18 @@ func DefaultConfig() *Config {
 				Emoji:    true,
 			},
 		},
+		Lifecycle: LifecycleConfig{
+			MaxReviewCycles: 3,
+			FailurePolicy:   FailurePolicyKeep,
+		},
+		Git: GitConfig{
+			Preflight:         true,
+			ProtectedBranches: []string{"main", "master"},
+			BranchTemplate:    "story/{{.StoryKey}}",
+			ReturnToBase:      true,
+			CommitMode:        CommitModeClaude,
+			Push:              true,
+		},
 	}
 }
 
@@ -174,4 +662,65 @@ type PromptData struct {
 	// StoryKey is the identifier of the story being processed.
 	// Access in templates with {{.StoryKey}}.
 	StoryKey string
+
+	// EpicID is the identifier of the epic being processed.
+	// Set for epic-level workflows such as the retrospective.
+	// Access in templates with {{.EpicID}}.
+	EpicID string
+
+	// Stories lists the story keys of the epic for epic-level workflows.
+	// Access in templates with {{range .Stories}}{{.}} {{end}}.
+	Stories []string
+
+	// Workflow is the workflow a follow-up session relates to, e.g. the
+	// workflow whose quality gates fix-gates should make pass.
+	// Access in templates with {{.Workflow}}.
+	Workflow string
+
+	// GateOutput is the output of failing quality gates for fix-gates.
+	// Access in templates with {{.GateOutput}}.
+	GateOutput string
+
+	// Diff is the diffstat and patch of the staged changes for
+	// commit-message, truncated if very large.
+	// Access in templates with {{.Diff}}.
+	Diff string
+
+	// Push reports whether git-commit should push (see [GitConfig.Push]).
+	// Access in templates with {{if .Push}}...{{end}}.
+	Push bool
+
+	// StoryNum is the story's number within its epic, e.g. 2 for "6-2-add-api".
+	// Access in templates with {{.StoryNum}}.
+	StoryNum int
+
+	// StorySlug is the title part of the story key, e.g. "add-api" for
+	// "6-2-add-api". Access in templates with {{.StorySlug}}.
+	StorySlug string
+
+	// Status is the story's status when the workflow starts.
+	// Access in templates with {{.Status}}.
+	Status string
+
+	// StoryFile is the path of the story's markdown file, which may not
+	// exist yet. Access in templates with {{.StoryFile}}.
+	StoryFile string
+
+	// ProjectRoot is the absolute path of the project directory.
+	// Access in templates with {{.ProjectRoot}}.
+	ProjectRoot string
+
+	// Attempt counts the runs of this workflow for the story in the current
+	// invocation, starting at 1. Review rework and retries increase it.
+	// Access in templates with {{if gt .Attempt 1}}...{{end}}.
+	Attempt int
+
+	// PreviousMessage is the final message of the previous workflow run for
+	// the same story, e.g. code-review's findings when dev-story reruns.
+	// Access in templates with {{.PreviousMessage}}.
+	PreviousMessage string
+
+	// Vars holds the user-defined variables from the vars config key and
+	// --var flags. Access in templates with {{.Vars.name}}.
+	Vars map[string]string
 }
@@ -0,0 +1,429 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// Validate checks the configuration for mistakes that would otherwise only
+// show up in the middle of a run, and returns a description of each problem
+// found, or nil if there are none.
+//
+// It checks that:
+//   - every workflow has a prompt, prompt and branch templates parse, and
+//     prompts only refer to lowercase {{.Vars.name}} variables
+//   - workflows named by full_cycle.steps and lifecycle.steps are defined
+//   - models that are set are non-empty and contain no whitespace, and
+//     model_fallback is valid
+//   - Claude CLI options are valid and extra_args leave the flags bmaduum
+//     relies on alone
+//   - commit_mode, failure_policy, prompt_stdin, hook on_error values,
+//     max_review_cycles and stdin_threshold are valid
+//   - the command agent backend has a path, a valid prompt mode and event
+//     mappings with known kinds and fields, and workflows set no Claude
+//     permission or turn settings it would ignore
+//   - gates and hooks have a command
+//   - profiles only set known settings
+//
+// Prompt files are parsed when the configuration is loaded, so they are not
+// checked again. Story statuses, agent backend names and the agent binary
+// are checked by the CLI, which knows about them.
+func (c *Config) Validate() []string {
+	var problems []string
+	addf := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	for _, name := range c.WorkflowNames() {
+		wf := c.Workflows[name]
+		key := "workflows." + name
+
+		switch {
+		case wf.PromptFile == "" && strings.TrimSpace(wf.PromptTemplate) == "":
+			addf("%s: no prompt_template or prompt_file", key)
+		case wf.PromptFile == "":
+			t, err := parseTemplate(wf.PromptTemplate)
+			if err != nil {
+				addf("%s.prompt_template: %v", key, err)
+				break
+			}
+			for _, name := range mixedCaseVars(t) {
+				addf("%s.prompt_template: {{.Vars.%s}} is always empty, variable names are lowercase: use {{.Vars.%s}}", key, name, strings.ToLower(name))
+			}
+		default:
+			for _, name := range mixedCaseVars(wf.prompt) {
+				addf("%s.prompt_file: {{.Vars.%s}} is always empty, variable names are lowercase: use {{.Vars.%s}}", key, name, strings.ToLower(name))
+			}
+		}
+
+		if c.Origin(key+".model") != OriginDefault || wf.Model != "" {
+			if problem := modelProblem(wf.Model); problem != "" {
+				addf("%s.model: %s", key, problem)
+			}
+		}
+		for i, model := range wf.Models {
+			if problem := modelProblem(model); problem != "" {
+				addf("%s.models[%d]: %s", key, i, problem)
+			}
+		}
+		if len(wf.Models) > 0 && wf.Model != "" {
+			addf("%s: set model or models, not both", key)
+		}
+		switch wf.ModelFallback {
+		case "", ModelFallbackRateLimit, ModelFallbackOverload, ModelFallbackNever:
+		default:
+			addf("%s.model_fallback: %q is not one of rate_limit, overload or never", key, wf.ModelFallback)
+		}
+
+		problems = append(problems, claudeOptionProblems(key, wf)...)
+
+		for i, gate := range wf.Gates {
+			if strings.TrimSpace(gate.Command) == "" {
+				addf("%s.gates[%d]: no command", key, i)
+			}
+		}
+		if wf.FixAttempts < 0 {
+			addf("%s.fix_attempts: must not be negative", key)
+		}
+		problems = append(problems, hookProblems(key+".hooks", wf.Hooks)...)
+	}
+
+	for i, step := range c.FullCycle.Steps {
+		if !c.HasWorkflow(step) {
+			addf("full_cycle.steps[%d]: unknown workflow %q", i, step)
+		}
+	}
+	for i, step := range c.Lifecycle.Steps {
+		if !c.HasWorkflow(step.Workflow) {
+			addf("lifecycle.steps[%d]: unknown workflow %q", i, step.Workflow)
+		}
+	}
+	if c.Lifecycle.MaxReviewCycles < 1 {
+		addf("lifecycle.max_review_cycles: must be at least 1, got %d", c.Lifecycle.MaxReviewCycles)
+	}
+	if !slices.Contains([]string{FailurePolicyKeep, FailurePolicyStash, FailurePolicyReset}, c.Lifecycle.FailurePolicy) {
+		addf("lifecycle.failure_policy: %q is not one of keep, stash or reset", c.Lifecycle.FailurePolicy)
+	}
+	problems = append(problems, hookProblems("lifecycle.hooks", c.Lifecycle.Hooks)...)
+
+	if _, err := parseTemplate(c.Git.BranchTemplate); err != nil {
+		addf("git.branch_template: %v", err)
+	}
+	switch c.Git.CommitMode {
+	case CommitModeClaude:
+	case CommitModeNative:
+		if !c.HasWorkflow(CommitMessageWorkflow) {
+			addf("git.commit_mode: native needs the %s workflow", CommitMessageWorkflow)
+		}
+	default:
+		addf("git.commit_mode: %q is not one of claude or native", c.Git.CommitMode)
+	}
+
+	if strings.TrimSpace(c.Claude.BinaryPath) == "" {
+		addf("claude.binary_path: must not be empty")
+	}
+	switch c.Claude.PromptStdin {
+	case PromptStdinAuto, PromptStdinAlways, PromptStdinNever:
+	default:
+		addf("claude.prompt_stdin: %q is not one of auto, always or never", c.Claude.PromptStdin)
+	}
+	if c.Claude.StdinThreshold < 1 {
+		addf("claude.stdin_threshold: must be at least 1, got %d", c.Claude.StdinThreshold)
+	}
+
+	if c.Agent.Backend == AgentBackendCommand {
+		problems = append(problems, commandAgentProblems(c.Agent.Command)...)
+		problems = append(problems, c.claudeOnlyProblems()...)
+	}
+
+	profiles := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		profiles = append(profiles, name)
+	}
+	sort.Strings(profiles)
+	for _, name := range profiles {
+		for _, key := range unknownKeys(reflect.TypeOf(Config{}), c.Profiles[name], "") {
+			addf("profiles.%s.%s: unknown setting", name, key)
+		}
+		for _, key := range []string{"profile", "profiles"} {
+			if _, ok := c.Profiles[name][key]; ok {
+				addf("profiles.%s.%s: cannot be set in a profile", name, key)
+			}
+		}
+	}
+
+	return problems
+}
+
+// unknownKeys returns the dotted keys of values that do not name a setting
+// of type t, in key order. Any key is allowed below a map setting, such as
+// a new workflow under workflows.
+func unknownKeys(t reflect.Type, values map[string]any, prefix string) []string {
+	var unknown []string
+	flatten(values, "", func(key string, _ any) {
+		if !knownKey(t, strings.Split(key, ".")) {
+			unknown = append(unknown, prefix+key)
+		}
+	})
+	return unknown
+}
+
+// knownKey reports whether the key path names a setting of type t.
+func knownKey(t reflect.Type, path []string) bool {
+	for len(path) > 0 {
+		switch t.Kind() {
+		case reflect.Pointer:
+			t = t.Elem()
+			continue
+		case reflect.Struct:
+			field, ok := fieldByTag(t, path[0])
+			if !ok {
+				return false
+			}
+			t = field.Type
+		case reflect.Map:
+			t = t.Elem()
+		case reflect.Interface:
+			return true
+		default:
+			return false
+		}
+		path = path[1:]
+	}
+	return true
+}
+
+// fieldByTag returns the exported field of struct type t with the given
+// mapstructure name.
+func fieldByTag(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if field.IsExported() && strings.Split(field.Tag.Get("mapstructure"), ",")[0] == name {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// permissionModes are the Claude CLI permission modes.
+var permissionModes = []string{"default", "acceptEdits", "plan", "dontAsk", "bypassPermissions"}
+
+// reservedArgs are Claude CLI flags bmaduum sets itself and that extra_args
+// must not change, since output parsing depends on them.
+var reservedArgs = []string{"-p", "--print", "--output-format", "--verbose", "--input-format"}
+
+// claudeOptionProblems checks the Claude CLI options of the workflow under key.
+func claudeOptionProblems(key string, wf WorkflowConfig) []string {
+	var problems []string
+	addf := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if wf.PermissionMode != "" && !slices.Contains(permissionModes, wf.PermissionMode) {
+		addf("%s.permission_mode: %q is not one of %s", key, wf.PermissionMode, strings.Join(permissionModes, ", "))
+	}
+	if wf.MaxTurns < 0 {
+		addf("%s.max_turns: must not be negative", key)
+	}
+	for setting, tools := range map[string][]string{"allowed_tools": wf.AllowedTools, "disallowed_tools": wf.DisallowedTools} {
+		for i, tool := range tools {
+			if strings.TrimSpace(tool) == "" {
+				addf("%s.%s[%d]: empty tool", key, setting, i)
+			}
+		}
+	}
+	for i, dir := range wf.AddDirs {
+		if strings.TrimSpace(dir) == "" {
+			addf("%s.add_dirs[%d]: empty directory", key, i)
+		}
+	}
+	for i, arg := range wf.ExtraArgs {
+		flag, _, _ := strings.Cut(arg, "=")
+		if slices.Contains(reservedArgs, flag) {
+			addf("%s.extra_args[%d]: %s is set by bmaduum", key, i, flag)
+		}
+	}
+	slices.Sort(problems)
+	return problems
+}
+
+// commandAgentProblems checks the configuration of the command agent backend.
+func commandAgentProblems(cmd CommandAgentConfig) []string {
+	var problems []string
+	addf := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if strings.TrimSpace(cmd.Path) == "" {
+		addf("agent.command.path: must be set for the command backend")
+	}
+	switch cmd.Prompt {
+	case AgentPromptStdin, AgentPromptArg:
+	default:
+		addf("agent.command.prompt: %q is not one of stdin or arg", cmd.Prompt)
+	}
+	if len(cmd.Events) == 0 {
+		addf("agent.command.events: no event mappings, so no agent output would be shown")
+	}
+	for i, rule := range cmd.Events {
+		key := fmt.Sprintf("agent.command.events[%d]", i)
+		if !slices.Contains(EventKinds, rule.Kind) {
+			addf("%s.kind: %q is not one of %s", key, rule.Kind, strings.Join(EventKinds, ", "))
+		}
+		for j, m := range rule.Match {
+			if strings.TrimSpace(m.Field) == "" {
+				addf("%s.match[%d]: no field", key, j)
+			}
+		}
+		fields := make([]string, 0, len(rule.Fields))
+		for field := range rule.Fields {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			switch {
+			case !slices.Contains(EventFields, field):
+				addf("%s.fields.%s: unknown event field", key, field)
+			case strings.TrimSpace(rule.Fields[field]) == "":
+				addf("%s.fields.%s: no path", key, field)
+			}
+		}
+	}
+	return problems
+}
+
+// claudeOnlyProblems reports workflow settings the command agent backend
+// ignores, since it only passes the model and extra_args to the agent.
+// Settings left at their defaults are not reported.
+func (c *Config) claudeOnlyProblems() []string {
+	var problems []string
+	defaults := DefaultConfig().Workflows
+	for _, name := range c.WorkflowNames() {
+		wf, def := c.Workflows[name], defaults[name]
+		for _, setting := range []struct {
+			key string
+			set bool
+		}{
+			{"allowed_tools", !slices.Equal(wf.AllowedTools, def.AllowedTools)},
+			{"disallowed_tools", !slices.Equal(wf.DisallowedTools, def.DisallowedTools)},
+			{"permission_mode", wf.PermissionMode != def.PermissionMode},
+			{"max_turns", wf.MaxTurns != def.MaxTurns},
+		} {
+			if setting.set {
+				problems = append(problems, fmt.Sprintf("workflows.%s.%s: ignored by the command agent backend", name, setting.key))
+			}
+		}
+	}
+	return problems
+}
+
+// hookProblems checks the hooks configured under key.
+func hookProblems(key string, hooks HooksConfig) []string {
+	var problems []string
+	for event, list := range map[string][]HookConfig{"pre": hooks.Pre, "post": hooks.Post, "on_failure": hooks.OnFailure} {
+		for i, h := range list {
+			if strings.TrimSpace(h.Command) == "" {
+				problems = append(problems, fmt.Sprintf("%s.%s[%d]: no command", key, event, i))
+			}
+			switch h.OnError {
+			case "", HookOnErrorFail, HookOnErrorWarn, HookOnErrorIgnore:
+			default:
+				problems = append(problems, fmt.Sprintf("%s.%s[%d].on_error: %q is not one of fail, warn or ignore", key, event, i, h.OnError))
+			}
+		}
+	}
+	slices.Sort(problems)
+	return problems
+}
+
+// modelProblem describes what is wrong with a model name, or returns an
+// empty string if it is usable.
+func modelProblem(model string) string {
+	switch {
+	case strings.TrimSpace(model) == "":
+		return "must be a non-empty model name"
+	case strings.ContainsAny(model, " \t\n"):
+		return fmt.Sprintf("%q must not contain whitespace", model)
+	}
+	return ""
+}
+
+// parseTemplate parses a template the way prompts and branch names are
+// expanded, without executing it.
+func parseTemplate(tmpl string) (*template.Template, error) {
+	return template.New("prompt").Funcs(templateFuncs).Option("missingkey=zero").Parse(tmpl)
+}
+
+// mixedCaseVars returns the names of the {{.Vars.name}} references in t and
+// its associated templates that are not lowercase, sorted and without
+// duplicates. Variable names are stored lowercase, so such a reference
+// always expands to nothing. A nil t has no references.
+func mixedCaseVars(t *template.Template) []string {
+	if t == nil {
+		return nil
+	}
+
+	var names []string
+	check := func(ident []string) {
+		if len(ident) >= 2 && ident[0] == "Vars" && ident[1] != strings.ToLower(ident[1]) {
+			names = append(names, ident[1])
+		}
+	}
+	var walk func(node parse.Node)
+	walk = func(node parse.Node) {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n != nil {
+				for _, child := range n.Nodes {
+					walk(child)
+				}
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe)
+		case *parse.PipeNode:
+			if n != nil {
+				for _, cmd := range n.Cmds {
+					walk(cmd)
+				}
+			}
+		case *parse.CommandNode:
+			for _, arg := range n.Args {
+				walk(arg)
+			}
+		case *parse.FieldNode:
+			check(n.Ident)
+		case *parse.VariableNode:
+			// $.Vars.name
+			if len(n.Ident) > 0 && n.Ident[0] == "$" {
+				check(n.Ident[1:])
+			}
+		case *parse.IfNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.RangeNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.WithNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.TemplateNode:
+			walk(n.Pipe)
+		}
+	}
+	for _, tmpl := range t.Templates() {
+		if tmpl.Tree != nil {
+			walk(tmpl.Tree.Root)
+		}
+	}
+
+	slices.Sort(names)
+	return slices.Compact(names)
+}
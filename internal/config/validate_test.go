@@ -0,0 +1,268 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate_Defaults(t *testing.T) {
+	assert.Empty(t, DefaultConfig().Validate())
+}
+
+func TestValidate_Problems(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"bmaduum.yaml": `
+workflows:
+  dev-story:
+    model: ""
+    gates:
+      - name: tests
+  code-review:
+    model: claude opus
+  broken:
+    prompt_template: "{{.StoryKey"
+  empty:
+    description: No prompt
+    hooks:
+      post:
+        - command: make lint
+          on_error: explode
+full_cycle:
+  steps: [create-story, missing]
+lifecycle:
+  max_review_cycles: 0
+  failure_policy: discard
+  steps:
+    - workflow: dev-story
+    - workflow: nope
+git:
+  branch_template: "story/{{.StoryKey"
+  commit_mode: manual
+`,
+	})
+
+	cfg, err := NewLoader().LoadFromFile(filepath.Join(dir, "bmaduum.yaml"))
+	require.NoError(t, err)
+
+	problems := cfg.Validate()
+	for _, want := range []string{
+		"workflows.broken.prompt_template: template: prompt:1: unclosed action",
+		"workflows.code-review.model: \"claude opus\" must not contain whitespace",
+		"workflows.dev-story.gates[0]: no command",
+		"workflows.dev-story.model: must be a non-empty model name",
+		"workflows.empty: no prompt_template or prompt_file",
+		"workflows.empty.hooks.post[0].on_error: \"explode\" is not one of fail, warn or ignore",
+		"full_cycle.steps[1]: unknown workflow \"missing\"",
+		"lifecycle.steps[1]: unknown workflow \"nope\"",
+		"lifecycle.max_review_cycles: must be at least 1, got 0",
+		"lifecycle.failure_policy: \"discard\" is not one of keep, stash or reset",
+		"git.commit_mode: \"manual\" is not one of claude or native",
+	} {
+		assert.Contains(t, problems, want)
+	}
+	assert.Contains(t, problems[len(problems)-2], "git.branch_template: template: prompt:1: unclosed action")
+	assert.Len(t, problems, 12)
+}
+
+func TestValidate_NativeCommitNeedsCommitMessage(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Git.CommitMode = CommitModeNative
+	assert.Empty(t, cfg.Validate())
+
+	delete(cfg.Workflows, CommitMessageWorkflow)
+	assert.Equal(t, []string{"git.commit_mode: native needs the commit-message workflow"}, cfg.Validate())
+}
+
+func TestValidate_MixedCaseVars(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"bmaduum.yaml": `
+vars:
+  apiBase: https://example.com
+workflows:
+  dev-story:
+    prompt_template: "{{.Vars.apiBase}} {{if .Vars.Env}}{{$.Vars.Env}}{{end}} {{.Vars.ticket}}"
+  code-review:
+    prompt_file: review.tmpl
+`,
+		"review.tmpl": `{{range .Stories}}{{$.Vars.Team}}{{end}}`,
+	})
+
+	cfg, err := NewLoader().LoadFromFile(filepath.Join(dir, "bmaduum.yaml"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"workflows.code-review.prompt_file: {{.Vars.Team}} is always empty, variable names are lowercase: use {{.Vars.team}}",
+		"workflows.dev-story.prompt_template: {{.Vars.Env}} is always empty, variable names are lowercase: use {{.Vars.env}}",
+		"workflows.dev-story.prompt_template: {{.Vars.apiBase}} is always empty, variable names are lowercase: use {{.Vars.apibase}}",
+	}, cfg.Validate())
+}
+
+func TestStarterConfig(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"workflows.yaml": StarterConfig})
+
+	// The starter file changes nothing until settings are uncommented
+	cfg, err := NewLoader().LoadFromFile(filepath.Join(dir, "workflows.yaml"))
+	require.NoError(t, err)
+	cfg.origins = nil
+	cfg.layers = nil
+	assert.Equal(t, DefaultConfig(), cfg)
+}
+
+func TestValidate_ProfileKeys(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Profiles = map[string]map[string]any{
+		"fast": {
+			"workflows": map[string]any{
+				"dev-story": map[string]any{"modle": "sonnet"},
+				"new-step":  map[string]any{"model": "haiku"},
+			},
+			"lifecycle": map[string]any{"max_review_cycles": 1},
+			"vars":      map[string]any{"anything": "goes"},
+		},
+		"broken": {
+			"claude":  map[string]any{"binary": "claude"},
+			"profile": "fast",
+		},
+	}
+
+	assert.Equal(t, []string{
+		"profiles.broken.claude.binary: unknown setting",
+		"profiles.broken.profile: cannot be set in a profile",
+		"profiles.fast.workflows.dev-story.modle: unknown setting",
+	}, cfg.Validate())
+}
+
+func TestValidate_ClaudeOptions(t *testing.T) {
+	cfg := DefaultConfig()
+	wf := cfg.Workflows["code-review"]
+	wf.PermissionMode = "readonly"
+	wf.MaxTurns = -1
+	wf.AllowedTools = []string{"Read", " "}
+	wf.AddDirs = []string{""}
+	wf.ExtraArgs = []string{"--fallback-model", "haiku", "--output-format=json"}
+	cfg.Workflows["code-review"] = wf
+
+	assert.Equal(t, []string{
+		"workflows.code-review.add_dirs[0]: empty directory",
+		"workflows.code-review.allowed_tools[1]: empty tool",
+		"workflows.code-review.extra_args[2]: --output-format is set by bmaduum",
+		"workflows.code-review.max_turns: must not be negative",
+		"workflows.code-review.permission_mode: \"readonly\" is not one of default, acceptEdits, plan, dontAsk, bypassPermissions",
+	}, cfg.Validate())
+
+	wf.PermissionMode = "plan"
+	wf.MaxTurns = 10
+	wf.AllowedTools = []string{"Read"}
+	wf.AddDirs = nil
+	wf.ExtraArgs = []string{"--fallback-model", "haiku"}
+	cfg.Workflows["code-review"] = wf
+	assert.Empty(t, cfg.Validate())
+}
+
+func TestValidate_Models(t *testing.T) {
+	cfg := DefaultConfig()
+	wf := cfg.Workflows["dev-story"]
+	wf.Models = []string{"opus", ""}
+	wf.Model = "opus"
+	wf.ModelFallback = "always"
+	cfg.Workflows["dev-story"] = wf
+
+	assert.Equal(t, []string{
+		"workflows.dev-story.models[1]: must be a non-empty model name",
+		"workflows.dev-story: set model or models, not both",
+		"workflows.dev-story.model_fallback: \"always\" is not one of rate_limit, overload or never",
+	}, cfg.Validate())
+
+	wf.Models = []string{"opus", "sonnet"}
+	wf.Model = ""
+	wf.ModelFallback = ModelFallbackOverload
+	cfg.Workflows["dev-story"] = wf
+	assert.Empty(t, cfg.Validate())
+}
+
+func TestValidate_PromptStdin(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Claude.PromptStdin = "sometimes"
+	cfg.Claude.StdinThreshold = 0
+
+	assert.Equal(t, []string{
+		"claude.prompt_stdin: \"sometimes\" is not one of auto, always or never",
+		"claude.stdin_threshold: must be at least 1, got 0",
+	}, cfg.Validate())
+
+	cfg.Claude.PromptStdin = PromptStdinAlways
+	cfg.Claude.StdinThreshold = 1024
+	assert.Empty(t, cfg.Validate())
+}
+
+func TestValidate_CommandAgent(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Agent.Backend = AgentBackendCommand
+
+	assert.Equal(t, []string{
+		"agent.command.path: must be set for the command backend",
+		"agent.command.events: no event mappings, so no agent output would be shown",
+	}, cfg.Validate())
+
+	cfg.Agent.Command = CommandAgentConfig{
+		Path:   "my-agent",
+		Prompt: "file",
+		Events: []EventMapping{
+			{Match: []FieldMatch{{Field: "type", Equals: "message"}}, Kind: EventKindText, Fields: map[string]string{"text": "content"}},
+			{Match: []FieldMatch{{Field: " "}}, Kind: "chat", Fields: map[string]string{"txt": "content", "result": ""}},
+		},
+	}
+	assert.Equal(t, []string{
+		"agent.command.prompt: \"file\" is not one of stdin or arg",
+		"agent.command.events[1].kind: \"chat\" is not one of session_start, text, tool_use, tool_result, result",
+		"agent.command.events[1].match[0]: no field",
+		"agent.command.events[1].fields.result: no path",
+		"agent.command.events[1].fields.txt: unknown event field",
+	}, cfg.Validate())
+
+	cfg.Agent.Command.Prompt = AgentPromptArg
+	cfg.Agent.Command.Events = cfg.Agent.Command.Events[:1]
+	assert.Empty(t, cfg.Validate())
+
+	cfg.Agent.Backend = AgentBackendClaude
+	cfg.Agent.Command = CommandAgentConfig{}
+	assert.Empty(t, cfg.Validate(), "command settings are only checked for the command backend")
+}
+
+func TestValidate_CommandAgent_ClaudeOnlySettings(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Agent.Backend = AgentBackendCommand
+	cfg.Agent.Command = CommandAgentConfig{
+		Path:   "my-agent",
+		Prompt: AgentPromptStdin,
+		Events: []EventMapping{{Kind: EventKindText, Fields: map[string]string{"text": "content"}}},
+	}
+	assert.Empty(t, cfg.Validate(), "defaults are not reported")
+
+	wf := cfg.Workflows["dev-story"]
+	wf.AllowedTools = []string{"Read"}
+	wf.DisallowedTools = []string{"Bash"}
+	wf.PermissionMode = "acceptEdits"
+	wf.MaxTurns = 5
+	cfg.Workflows["dev-story"] = wf
+	commit := cfg.Workflows[CommitMessageWorkflow]
+	commit.MaxTurns = 3
+	cfg.Workflows[CommitMessageWorkflow] = commit
+
+	assert.Equal(t, []string{
+		"workflows.commit-message.max_turns: ignored by the command agent backend",
+		"workflows.dev-story.allowed_tools: ignored by the command agent backend",
+		"workflows.dev-story.disallowed_tools: ignored by the command agent backend",
+		"workflows.dev-story.permission_mode: ignored by the command agent backend",
+		"workflows.dev-story.max_turns: ignored by the command agent backend",
+	}, cfg.Validate())
+
+	cfg.Agent.Backend = AgentBackendClaude
+	assert.Empty(t, cfg.Validate())
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Workspace lists several project roots for multi-project runs.
+//
+// A workspace file is a small YAML document:
+//
+//	projects:
+//	  - ../service-a
+//	  - /work/service-b
+//
+// It is used by "bmaduum epic --workspace" to run epics across every project.
+type Workspace struct {
+	// Projects are the project root directories. After [LoadWorkspace],
+	// every entry is an absolute path.
+	Projects []string `yaml:"projects"`
+}
+
+// LoadWorkspace reads a workspace file and resolves its project paths.
+//
+// Relative project paths are resolved against the directory containing the
+// workspace file, so the file can be used from any working directory.
+//
+// Returns an error if the file cannot be read or parsed, or lists no projects.
+func LoadWorkspace(path string) (*Workspace, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading workspace file: %w", err)
+	}
+
+	var ws Workspace
+	if err := yaml.Unmarshal(data, &ws); err != nil {
+		return nil, fmt.Errorf("error parsing workspace file %s: %w", path, err)
+	}
+
+	if len(ws.Projects) == 0 {
+		return nil, fmt.Errorf("workspace file %s lists no projects", path)
+	}
+
+	baseDir, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+	for i, project := range ws.Projects {
+		if !filepath.IsAbs(project) {
+			ws.Projects[i] = filepath.Join(baseDir, project)
+		}
+	}
+
+	return &ws, nil
+}
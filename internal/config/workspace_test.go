@@ -0,0 +1,38 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadWorkspace(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "workspace.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("projects:\n  - service-a\n  - /abs/service-b\n"), 0644))
+
+	ws, err := LoadWorkspace(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "service-a"), "/abs/service-b"}, ws.Projects)
+}
+
+func TestLoadWorkspace_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := LoadWorkspace(filepath.Join(dir, "missing.yaml"))
+	assert.Error(t, err)
+
+	empty := filepath.Join(dir, "empty.yaml")
+	require.NoError(t, os.WriteFile(empty, []byte("projects: []\n"), 0644))
+	_, err = LoadWorkspace(empty)
+	assert.ErrorContains(t, err, "lists no projects")
+
+	invalid := filepath.Join(dir, "invalid.yaml")
+	require.NoError(t, os.WriteFile(invalid, []byte("projects: [unclosed\n"), 0644))
+	_, err = LoadWorkspace(invalid)
+	assert.Error(t, err)
+}
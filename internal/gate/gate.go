@@ -0,0 +1,197 @@
+// Package gate runs quality gates: project commands such as tests and linters
+// that must pass after a workflow before the story may advance.
+//
+// Gates are configured per workflow (see [config.GateConfig]). [Checker] runs
+// them through the system shell, renders their output with a [core.Printer],
+// and can start a follow-up Claude session to fix failures.
+// [Checker] implements [lifecycle.GateChecker].
+package gate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"bmaduum/internal/config"
+	"bmaduum/internal/output/core"
+	"bmaduum/internal/shell"
+)
+
+// maxFeedbackBytes caps how much of a failing gate's output is passed to the
+// fix-gates prompt. The tail is kept since failures are usually reported last.
+const maxFeedbackBytes = 16 * 1024
+
+// Result is the outcome of running a single gate.
+type Result struct {
+	// Gate is the gate that was run.
+	Gate config.GateConfig
+
+	// ExitCode is the command's exit code, or -1 if it did not finish.
+	ExitCode int
+
+	// Output is the combined stdout and stderr of the command.
+	Output string
+
+	// Duration is how long the command ran.
+	Duration time.Duration
+
+	// Err is set when the command could not be started or timed out.
+	Err error
+}
+
+// Passed reports whether the gate finished with an accepted exit code.
+func (r Result) Passed() bool {
+	if r.Err != nil {
+		return false
+	}
+	codes := r.Gate.ExitCodes
+	if len(codes) == 0 {
+		codes = []int{0}
+	}
+	return slices.Contains(codes, r.ExitCode)
+}
+
+// describe summarizes why a failed gate failed.
+func (r Result) describe() string {
+	if r.Err != nil {
+		return fmt.Sprintf("%s (%v)", r.Gate.DisplayName(), r.Err)
+	}
+	return fmt.Sprintf("%s (exit code %d)", r.Gate.DisplayName(), r.ExitCode)
+}
+
+// FailureError reports the gates that failed after a workflow.
+type FailureError struct {
+	// Failed lists the results of the failing gates.
+	Failed []Result
+}
+
+// Error implements the error interface.
+func (e *FailureError) Error() string {
+	parts := make([]string, len(e.Failed))
+	for i, r := range e.Failed {
+		parts[i] = r.describe()
+	}
+	return "failed gates: " + strings.Join(parts, ", ")
+}
+
+// Feedback formats the failing commands and their output for a fix prompt.
+func (e *FailureError) Feedback() string {
+	var b strings.Builder
+	for _, r := range e.Failed {
+		fmt.Fprintf(&b, "$ %s\n", r.Gate.Command)
+		fmt.Fprintf(&b, "%s\n", tail(r.Output, maxFeedbackBytes))
+		fmt.Fprintf(&b, "[%s]\n\n", r.describe())
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// Run executes a gate's command in dir and waits for it to finish or time out.
+//
+// An empty dir means the current working directory.
+func Run(ctx context.Context, dir string, g config.GateConfig) Result {
+	timeout := g.Timeout
+	if timeout <= 0 {
+		timeout = config.DefaultGateTimeout
+	}
+
+	r := shell.Run(ctx, shell.Command{Line: g.Command, Dir: dir, Timeout: timeout})
+	return Result{Gate: g, ExitCode: r.ExitCode, Output: r.Output, Duration: r.Duration, Err: r.Err}
+}
+
+// tail returns at most n bytes from the end of s.
+func tail(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return "... (output truncated)\n" + s[len(s)-n:]
+}
+
+// FixRunner is the interface for running the fix-gates workflow.
+//
+// The [workflow.Runner] type implements this interface.
+type FixRunner interface {
+	RunWorkflow(ctx context.Context, workflowName string, data config.PromptData) int
+}
+
+// Checker runs the gates configured for each workflow.
+//
+// Use [NewChecker] to create an instance.
+type Checker struct {
+	config  *config.Config
+	runner  FixRunner
+	printer core.Printer
+	dir     string
+}
+
+// NewChecker creates a [Checker] for the gates in cfg.
+//
+// Gates run in dir (empty for the current working directory) and their
+// output is rendered with printer. Fix sessions run through runner.
+func NewChecker(cfg *config.Config, runner FixRunner, printer core.Printer, dir string) *Checker {
+	return &Checker{
+		config:  cfg,
+		runner:  runner,
+		printer: printer,
+		dir:     dir,
+	}
+}
+
+// Run executes every gate configured for workflow, in order.
+//
+// All gates run even if an earlier one fails, so a fix session sees every
+// failure at once. Returns a [*FailureError] if any gate failed, or nil if
+// all passed or none are configured.
+func (c *Checker) Run(ctx context.Context, storyKey, workflow string) error {
+	var failed []Result
+	for _, g := range c.config.Workflows[workflow].Gates {
+		c.printer.CommandHeader("gate: "+g.DisplayName(), g.Command, c.config.Output.TruncateLength)
+
+		result := Run(ctx, c.dir, g)
+		if result.Output != "" {
+			c.printer.ToolResult(result.Output, "", c.config.Output.TruncateLines)
+		}
+		if result.Err != nil {
+			c.printer.Text(fmt.Sprintf("Gate %s: %v", g.DisplayName(), result.Err))
+		}
+		c.printer.CommandFooter(result.Duration, result.Passed(), result.ExitCode)
+
+		if !result.Passed() {
+			failed = append(failed, result)
+		}
+	}
+
+	if len(failed) > 0 {
+		return &FailureError{Failed: failed}
+	}
+	return nil
+}
+
+// FixAttempts returns how many fix sessions may run for workflow's gates.
+func (c *Checker) FixAttempts(workflow string) int {
+	return c.config.Workflows[workflow].FixAttempts
+}
+
+// Fix runs the fix-gates workflow with the output of the failing gates.
+//
+// Returns an error if the fix session itself fails.
+func (c *Checker) Fix(ctx context.Context, storyKey, workflow string, failure error) error {
+	feedback := failure.Error()
+	var failureErr *FailureError
+	if errors.As(failure, &failureErr) {
+		feedback = failureErr.Feedback()
+	}
+
+	exitCode := c.runner.RunWorkflow(ctx, config.FixGatesWorkflow, config.PromptData{
+		StoryKey:   storyKey,
+		Workflow:   workflow,
+		GateOutput: feedback,
+	})
+	if exitCode != 0 {
+		return fmt.Errorf("workflow failed: %s returned exit code %d", config.FixGatesWorkflow, exitCode)
+	}
+
+	return nil
+}
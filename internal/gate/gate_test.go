@@ -0,0 +1,147 @@
+package gate
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bmaduum/internal/config"
+	"bmaduum/internal/output"
+)
+
+// mockFixRunner records fix-gates sessions.
+type mockFixRunner struct {
+	workflows []string
+	data      []config.PromptData
+	exitCode  int
+}
+
+func (m *mockFixRunner) RunWorkflow(ctx context.Context, workflowName string, data config.PromptData) int {
+	m.workflows = append(m.workflows, workflowName)
+	m.data = append(m.data, data)
+	return m.exitCode
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name       string
+		gate       config.GateConfig
+		wantCode   int
+		wantPassed bool
+		wantOutput string
+	}{
+		{
+			name:       "passing command",
+			gate:       config.GateConfig{Command: "echo all good"},
+			wantCode:   0,
+			wantPassed: true,
+			wantOutput: "all good",
+		},
+		{
+			name:     "failing command",
+			gate:     config.GateConfig{Command: "exit 3"},
+			wantCode: 3,
+		},
+		{
+			name:       "accepted non-zero exit code",
+			gate:       config.GateConfig{Command: "exit 3", ExitCodes: []int{0, 3}},
+			wantCode:   3,
+			wantPassed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Run(context.Background(), "", tt.gate)
+
+			assert.Equal(t, tt.wantCode, result.ExitCode)
+			assert.Equal(t, tt.wantPassed, result.Passed())
+			assert.Contains(t, result.Output, tt.wantOutput)
+		})
+	}
+}
+
+func TestRun_Timeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sleep")
+	}
+
+	result := Run(context.Background(), "", config.GateConfig{Command: "sleep 5", Timeout: 100 * time.Millisecond})
+
+	assert.False(t, result.Passed())
+	assert.Equal(t, -1, result.ExitCode)
+	require.Error(t, result.Err)
+	assert.Contains(t, result.Err.Error(), "timed out after 100ms")
+	assert.Less(t, result.Duration, 3*time.Second)
+}
+
+func TestChecker_Run(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Workflows["dev-story"] = config.WorkflowConfig{
+		Gates: []config.GateConfig{
+			{Name: "tests", Command: "echo FAIL: TestThing && exit 1"},
+			{Name: "lint", Command: "echo clean"},
+			{Command: "exit 2"},
+		},
+	}
+	buf := &bytes.Buffer{}
+	checker := NewChecker(cfg, &mockFixRunner{}, output.NewPrinterWithWriter(buf), "")
+
+	err := checker.Run(context.Background(), "6-1-first", "dev-story")
+
+	var failure *FailureError
+	require.True(t, errors.As(err, &failure))
+	assert.Equal(t, "failed gates: tests (exit code 1), exit 2 (exit code 2)", err.Error())
+	assert.Contains(t, failure.Feedback(), "$ echo FAIL: TestThing && exit 1\nFAIL: TestThing")
+	assert.Contains(t, buf.String(), "echo clean")
+}
+
+func TestChecker_Run_NoGates(t *testing.T) {
+	checker := NewChecker(config.DefaultConfig(), &mockFixRunner{}, output.NewPrinterWithWriter(&bytes.Buffer{}), "")
+
+	assert.NoError(t, checker.Run(context.Background(), "6-1-first", "code-review"))
+	assert.Equal(t, 0, checker.FixAttempts("code-review"))
+}
+
+func TestChecker_Fix(t *testing.T) {
+	cfg := config.DefaultConfig()
+	runner := &mockFixRunner{}
+	checker := NewChecker(cfg, runner, output.NewPrinterWithWriter(&bytes.Buffer{}), "")
+
+	failure := &FailureError{Failed: []Result{{
+		Gate:     config.GateConfig{Name: "tests", Command: "go test ./..."},
+		ExitCode: 1,
+		Output:   "--- FAIL: TestParse",
+	}}}
+	require.NoError(t, checker.Fix(context.Background(), "6-1-first", "dev-story", failure))
+
+	require.Equal(t, []string{config.FixGatesWorkflow}, runner.workflows)
+	data := runner.data[0]
+	assert.Equal(t, "6-1-first", data.StoryKey)
+	assert.Equal(t, "dev-story", data.Workflow)
+	assert.Contains(t, data.GateOutput, "$ go test ./...\n--- FAIL: TestParse")
+
+	// The default fix-gates prompt includes the failing output
+	prompt, err := cfg.GetPromptWithData(config.FixGatesWorkflow, data)
+	require.NoError(t, err)
+	assert.Contains(t, prompt, "--- FAIL: TestParse")
+
+	runner.exitCode = 1
+	err = checker.Fix(context.Background(), "6-1-first", "dev-story", failure)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "fix-gates returned exit code 1")
+}
+
+func TestTail(t *testing.T) {
+	assert.Equal(t, "short", tail("short", 10))
+
+	long := strings.Repeat("a", 10) + "END"
+	assert.Equal(t, "... (output truncated)\naaEND", tail(long, 5))
+}
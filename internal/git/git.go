@@ -0,0 +1,225 @@
+// Package git provides the small set of git queries bmaduum needs.
+//
+// All functions shell out to the git binary in a given directory; an empty
+// directory means the current working directory.
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Head returns the full hash of the commit checked out in dir.
+//
+// Returns an error if dir is not inside a git repository or the repository
+// has no commits yet.
+func Head(dir string) (string, error) {
+	return run(dir, "rev-parse", "HEAD")
+}
+
+// TopLevel returns the absolute path of the working tree root containing dir.
+//
+// Returns an error if dir is not inside a git working tree.
+func TopLevel(dir string) (string, error) {
+	return run(dir, "rev-parse", "--show-toplevel")
+}
+
+// CurrentBranch returns the name of the branch checked out in dir, or an
+// empty string if HEAD is detached.
+func CurrentBranch(dir string) (string, error) {
+	return run(dir, "branch", "--show-current")
+}
+
+// ChangedFiles returns the paths, relative to the working tree root, of
+// every modified, staged, deleted or untracked file in dir's repository.
+// Ignored files are not included. For renames both paths are returned.
+func ChangedFiles(dir string) ([]string, error) {
+	out, err := output(dir, "status", "--porcelain=v1", "-z", "--untracked-files=all")
+	if err != nil {
+		return nil, err
+	}
+
+	// Entries are "XY path", with renames and copies followed by the
+	// original path as a separate entry
+	var files []string
+	entries := strings.Split(strings.TrimSuffix(out, "\x00"), "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		files = append(files, entry[3:])
+		if entry[0] == 'R' || entry[0] == 'C' {
+			if i+1 < len(entries) {
+				i++
+				files = append(files, entries[i])
+			}
+		}
+	}
+
+	return files, nil
+}
+
+// OperationInProgress returns the name of an unfinished multi-step git
+// operation in dir's repository ("rebase", "merge", "cherry-pick" or
+// "revert"), or an empty string if there is none.
+func OperationInProgress(dir string) (string, error) {
+	gitDir, err := run(dir, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return "", err
+	}
+
+	markers := []struct {
+		name      string
+		operation string
+	}{
+		{"rebase-merge", "rebase"},
+		{"rebase-apply", "rebase"},
+		{"MERGE_HEAD", "merge"},
+		{"CHERRY_PICK_HEAD", "cherry-pick"},
+		{"REVERT_HEAD", "revert"},
+	}
+	for _, m := range markers {
+		if _, err := os.Stat(filepath.Join(gitDir, m.name)); err == nil {
+			return m.operation, nil
+		}
+	}
+
+	return "", nil
+}
+
+// BranchExists reports whether a local branch named name exists.
+func BranchExists(dir, name string) (bool, error) {
+	if _, err := TopLevel(dir); err != nil {
+		return false, err
+	}
+	_, err := run(dir, "rev-parse", "--verify", "--quiet", "refs/heads/"+name)
+	return err == nil, nil
+}
+
+// Checkout switches dir's working tree to the existing branch name.
+func Checkout(dir, name string) error {
+	_, err := run(dir, "checkout", "-q", name)
+	return err
+}
+
+// CreateBranch creates branch name from base and checks it out.
+func CreateBranch(dir, name, base string) error {
+	_, err := run(dir, "checkout", "-q", "-b", name, base)
+	return err
+}
+
+// RestoreFile discards working tree and staged changes to path, restoring
+// the version committed at HEAD. Returns an error if path is not in HEAD.
+func RestoreFile(dir, path string) error {
+	_, err := run(dir, "checkout", "-q", "HEAD", "--", path)
+	return err
+}
+
+// Stash stashes every uncommitted change in dir's repository, including
+// untracked files, under message. Files at the absolute paths in exclude are
+// left in place.
+//
+// Returns false if there was nothing to stash.
+func Stash(dir, message string, exclude ...string) (bool, error) {
+	before, _ := run(dir, "rev-parse", "--quiet", "--verify", "refs/stash")
+
+	args := []string{"stash", "push", "--quiet", "--include-untracked", "-m", message, "--", ":/"}
+	for _, path := range exclude {
+		args = append(args, ":(exclude)"+path)
+	}
+	if _, err := run(dir, args...); err != nil {
+		return false, err
+	}
+
+	after, _ := run(dir, "rev-parse", "--quiet", "--verify", "refs/stash")
+	return after != before, nil
+}
+
+// ResetHard resets dir's repository to commit, discarding every uncommitted
+// change and removing untracked files that are not ignored. Untracked files
+// at the absolute paths in exclude are left in place.
+func ResetHard(dir, commit string, exclude ...string) error {
+	if _, err := run(dir, "reset", "--quiet", "--hard", commit); err != nil {
+		return err
+	}
+	args := []string{"clean", "--quiet", "--force", "-d", "--", ":/"}
+	for _, path := range exclude {
+		args = append(args, ":(exclude)"+path)
+	}
+	_, err := run(dir, args...)
+	return err
+}
+
+// StageAll stages every change in dir's repository, including untracked
+// files that are not ignored.
+func StageAll(dir string) error {
+	_, err := run(dir, "add", "--all", ":/")
+	return err
+}
+
+// StagedDiff returns a diffstat followed by the patch of the staged changes
+// in dir's repository, or an empty string if nothing is staged.
+func StagedDiff(dir string) (string, error) {
+	return output(dir, "diff", "--cached", "--stat", "--patch")
+}
+
+// Commit commits the staged changes in dir's repository with message.
+func Commit(dir, message string) error {
+	_, err := run(dir, "commit", "--quiet", "-m", message)
+	return err
+}
+
+// Push pushes the current branch of dir's repository to its upstream, or
+// to origin with the upstream set when it has none yet.
+func Push(dir string) error {
+	if _, err := run(dir, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}"); err == nil {
+		_, err := run(dir, "push", "--quiet")
+		return err
+	}
+	_, err := run(dir, "push", "--quiet", "--set-upstream", "origin", "HEAD")
+	return err
+}
+
+// CommitMessages returns the messages of the commits reachable from HEAD
+// but not from since, newest first. An empty since means all of HEAD's
+// history.
+func CommitMessages(dir, since string) (string, error) {
+	rev := "HEAD"
+	if since != "" {
+		rev = since + "..HEAD"
+	}
+	return run(dir, "log", "--format=%B", rev)
+}
+
+// run executes git with args in dir and returns its trimmed standard output.
+//
+// On failure the error includes git's standard error output.
+func run(dir string, args ...string) (string, error) {
+	out, err := output(dir, args...)
+	return strings.TrimSpace(out), err
+}
+
+// output executes git with args in dir and returns its standard output
+// unchanged, for formats where leading whitespace is significant.
+func output(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+
+	return string(out), nil
+}
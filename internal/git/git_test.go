@@ -0,0 +1,250 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// initRepo creates a git repository in a temp dir with one commit.
+func initRepo(t *testing.T) string {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	gitCmd(t, dir, "init", "-q")
+	gitCmd(t, dir, "config", "user.email", "test@example.com")
+	gitCmd(t, dir, "config", "user.name", "Test")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("test\n"), 0644))
+	gitCmd(t, dir, "add", "-A")
+	gitCmd(t, dir, "commit", "-q", "-m", "initial")
+
+	return dir
+}
+
+// gitCmd runs git in dir, failing the test on error.
+func gitCmd(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	out, err := run(dir, args...)
+	require.NoError(t, err)
+	return out
+}
+
+func TestHead(t *testing.T) {
+	dir := initRepo(t)
+
+	head, err := Head(dir)
+
+	require.NoError(t, err)
+	assert.Len(t, head, 40)
+	assert.Equal(t, gitCmd(t, dir, "rev-parse", "HEAD"), head)
+}
+
+func TestHead_NotARepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	_, err := Head(t.TempDir())
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "git rev-parse")
+}
+
+func TestTopLevel(t *testing.T) {
+	dir := initRepo(t)
+	sub := filepath.Join(dir, "sub")
+	require.NoError(t, os.Mkdir(sub, 0755))
+
+	top, err := TopLevel(sub)
+
+	require.NoError(t, err)
+	assert.Equal(t, gitCmd(t, dir, "rev-parse", "--show-toplevel"), top)
+}
+
+func TestCurrentBranch(t *testing.T) {
+	dir := initRepo(t)
+	gitCmd(t, dir, "checkout", "-q", "-b", "feature/6-1")
+
+	branch, err := CurrentBranch(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "feature/6-1", branch)
+
+	// Detached HEAD has no branch
+	gitCmd(t, dir, "checkout", "-q", "--detach")
+	branch, err = CurrentBranch(dir)
+	require.NoError(t, err)
+	assert.Empty(t, branch)
+}
+
+func TestChangedFiles(t *testing.T) {
+	dir := initRepo(t)
+
+	files, err := ChangedFiles(dir)
+	require.NoError(t, err)
+	assert.Empty(t, files)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("changed\n"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "docs"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "docs", "new file.md"), []byte("new\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a\n"), 0644))
+	gitCmd(t, dir, "add", "a.txt")
+	gitCmd(t, dir, "commit", "-q", "-m", "add a")
+	gitCmd(t, dir, "mv", "a.txt", "b.txt")
+
+	files, err = ChangedFiles(dir)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"README.md", "docs/new file.md", "b.txt", "a.txt"}, files)
+}
+
+func TestOperationInProgress(t *testing.T) {
+	dir := initRepo(t)
+
+	op, err := OperationInProgress(dir)
+	require.NoError(t, err)
+	assert.Empty(t, op)
+
+	// Create a merge conflict and leave the merge unfinished
+	base := gitCmd(t, dir, "branch", "--show-current")
+	gitCmd(t, dir, "checkout", "-q", "-b", "other")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("other\n"), 0644))
+	gitCmd(t, dir, "commit", "-q", "-am", "other")
+	gitCmd(t, dir, "checkout", "-q", base)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("base\n"), 0644))
+	gitCmd(t, dir, "commit", "-q", "-am", "base")
+	_, err = run(dir, "merge", "-q", "other")
+	require.Error(t, err)
+
+	op, err = OperationInProgress(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "merge", op)
+}
+
+func TestBranches(t *testing.T) {
+	dir := initRepo(t)
+	base := gitCmd(t, dir, "branch", "--show-current")
+
+	exists, err := BranchExists(dir, "story/6-1")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	require.NoError(t, CreateBranch(dir, "story/6-1", base))
+	branch, err := CurrentBranch(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "story/6-1", branch)
+
+	exists, err = BranchExists(dir, "story/6-1")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	require.NoError(t, Checkout(dir, base))
+	branch, err = CurrentBranch(dir)
+	require.NoError(t, err)
+	assert.Equal(t, base, branch)
+
+	err = CreateBranch(dir, "story/6-1", base)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already exists")
+}
+
+func TestRestoreFile(t *testing.T) {
+	dir := initRepo(t)
+	path := filepath.Join(dir, "README.md")
+	require.NoError(t, os.WriteFile(path, []byte("changed\n"), 0644))
+
+	require.NoError(t, RestoreFile(dir, "README.md"))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "test\n", string(data))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "new.md"), []byte("new\n"), 0644))
+	assert.Error(t, RestoreFile(dir, "new.md"))
+}
+
+func TestStash(t *testing.T) {
+	dir := initRepo(t)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("changed\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "new.md"), []byte("new\n"), 0644))
+	keep := filepath.Join(dir, "status.yaml")
+	require.NoError(t, os.WriteFile(keep, []byte("status\n"), 0644))
+
+	stashed, err := Stash(dir, "bmaduum: 6-1-first partial work", keep)
+	require.NoError(t, err)
+	assert.True(t, stashed)
+
+	changed, err := ChangedFiles(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"status.yaml"}, changed)
+	assert.Contains(t, gitCmd(t, dir, "stash", "list"), "bmaduum: 6-1-first partial work")
+
+	stashed, err = Stash(dir, "nothing", keep)
+	require.NoError(t, err)
+	assert.False(t, stashed)
+}
+
+func TestResetHard(t *testing.T) {
+	dir := initRepo(t)
+	base := gitCmd(t, dir, "rev-parse", "HEAD")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("committed\n"), 0644))
+	gitCmd(t, dir, "commit", "-q", "-am", "partial")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "pkg"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pkg", "new.go"), []byte("package pkg\n"), 0644))
+
+	keep := filepath.Join(dir, "status.yaml.lock")
+	require.NoError(t, os.WriteFile(keep, nil, 0644))
+
+	require.NoError(t, ResetHard(dir, base, keep))
+
+	assert.Equal(t, base, gitCmd(t, dir, "rev-parse", "HEAD"))
+	changed, err := ChangedFiles(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"status.yaml.lock"}, changed)
+	assert.NoFileExists(t, filepath.Join(dir, "pkg", "new.go"))
+}
+
+func TestCommitAndPush(t *testing.T) {
+	remote := t.TempDir()
+	gitCmd(t, remote, "init", "-q", "--bare")
+
+	dir := initRepo(t)
+	gitCmd(t, dir, "remote", "add", "origin", remote)
+	base := gitCmd(t, dir, "rev-parse", "HEAD")
+
+	diff, err := StagedDiff(dir)
+	require.NoError(t, err)
+	assert.Empty(t, diff)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "feature.go"), []byte("package feature\n"), 0644))
+	require.NoError(t, StageAll(dir))
+	diff, err = StagedDiff(dir)
+	require.NoError(t, err)
+	assert.Contains(t, diff, "feature.go | 1 +")
+	assert.Contains(t, diff, "+package feature")
+
+	require.NoError(t, Commit(dir, "feat: add feature\n\nStory: 6-1-first"))
+	messages, err := CommitMessages(dir, base)
+	require.NoError(t, err)
+	assert.Equal(t, "feat: add feature\n\nStory: 6-1-first", messages)
+
+	all, err := CommitMessages(dir, "")
+	require.NoError(t, err)
+	assert.Contains(t, all, "initial")
+
+	// The first push sets the upstream, later pushes use it
+	require.NoError(t, Push(dir))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "feature.go"), []byte("package feature // v2\n"), 0644))
+	require.NoError(t, StageAll(dir))
+	require.NoError(t, Commit(dir, "fix: v2"))
+	require.NoError(t, Push(dir))
+
+	head := gitCmd(t, dir, "rev-parse", "HEAD")
+	assert.Equal(t, head, gitCmd(t, remote, "rev-parse", "HEAD"))
+}
@@ -0,0 +1,125 @@
+// Package hook runs user-defined shell hooks around workflows and stories.
+//
+// Hooks are configured per workflow and for the whole story lifecycle (see
+// [config.HooksConfig]). [Runner] runs them through the system shell with the
+// lifecycle context in environment variables and renders their output with
+// a [core.Printer]. [Runner] implements [lifecycle.HookRunner].
+package hook
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"bmaduum/internal/config"
+	"bmaduum/internal/lifecycle"
+	"bmaduum/internal/output/core"
+	"bmaduum/internal/shell"
+)
+
+// Runner runs the hooks configured for lifecycle events.
+//
+// Use [NewRunner] to create an instance.
+type Runner struct {
+	config  *config.Config
+	printer core.Printer
+	dir     string
+	runID   string
+}
+
+// NewRunner creates a [Runner] for the hooks in cfg.
+//
+// Hooks run in dir (empty for the current working directory) and receive
+// runID as BMADUUM_RUN_ID. Their output is rendered with printer.
+func NewRunner(cfg *config.Config, printer core.Printer, dir, runID string) *Runner {
+	return &Runner{
+		config:  cfg,
+		printer: printer,
+		dir:     dir,
+		runID:   runID,
+	}
+}
+
+// RunHooks runs the hooks configured for event, in order.
+//
+// Workflow hooks come from the workflow's hooks, story hooks (an empty
+// [lifecycle.HookEvent.Workflow]) from lifecycle.hooks. A failing hook with
+// on_error "fail" stops the remaining hooks and is returned as an error;
+// "warn" prints a warning and "ignore" continues silently.
+func (r *Runner) RunHooks(ctx context.Context, event lifecycle.HookEvent) error {
+	for _, h := range r.hooksFor(event) {
+		label := fmt.Sprintf("hook %s: %s", event.Stage, h.DisplayName())
+		r.printer.CommandHeader(label, h.Command, r.config.Output.TruncateLength)
+
+		timeout := h.Timeout
+		if timeout <= 0 {
+			timeout = config.DefaultHookTimeout
+		}
+		result := shell.Run(ctx, shell.Command{
+			Line:    h.Command,
+			Dir:     r.dir,
+			Env:     r.environ(event),
+			Timeout: timeout,
+		})
+
+		if result.Output != "" {
+			r.printer.ToolResult(result.Output, "", r.config.Output.TruncateLines)
+		}
+		passed := result.Err == nil && result.ExitCode == 0
+		r.printer.CommandFooter(result.Duration, passed, result.ExitCode)
+		if passed {
+			continue
+		}
+
+		err := fmt.Errorf("%s: exit code %d", h.DisplayName(), result.ExitCode)
+		if result.Err != nil {
+			err = fmt.Errorf("%s: %w", h.DisplayName(), result.Err)
+		}
+		switch h.OnError {
+		case config.HookOnErrorIgnore:
+		case config.HookOnErrorWarn:
+			r.printer.Text(fmt.Sprintf("Warning: hook %v", err))
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hooksFor returns the hooks configured for an event's stage.
+func (r *Runner) hooksFor(event lifecycle.HookEvent) []config.HookConfig {
+	hooks := r.config.Lifecycle.Hooks
+	if event.Workflow != "" {
+		hooks = r.config.Workflows[event.Workflow].Hooks
+	}
+
+	switch event.Stage {
+	case lifecycle.HookPre:
+		return hooks.Pre
+	case lifecycle.HookPost:
+		return hooks.Post
+	case lifecycle.HookOnFailure:
+		return hooks.OnFailure
+	default:
+		return nil
+	}
+}
+
+// environ returns the environment variables describing event.
+func (r *Runner) environ(event lifecycle.HookEvent) []string {
+	errText := ""
+	if event.Err != nil {
+		errText = event.Err.Error()
+	}
+
+	return []string{
+		"BMADUUM_HOOK=" + string(event.Stage),
+		"BMADUUM_STORY_KEY=" + event.StoryKey,
+		"BMADUUM_WORKFLOW=" + event.Workflow,
+		"BMADUUM_EXIT_CODE=" + strconv.Itoa(event.ExitCode),
+		"BMADUUM_ERROR=" + errText,
+		"BMADUUM_RUN_ID=" + r.runID,
+		"BMADUUM_TRANSCRIPT_PATH=" + event.TranscriptPath,
+	}
+}
@@ -0,0 +1,122 @@
+package hook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bmaduum/internal/config"
+	"bmaduum/internal/lifecycle"
+	"bmaduum/internal/output"
+)
+
+func newTestRunner(t *testing.T, cfg *config.Config) (*Runner, *bytes.Buffer, string) {
+	t.Helper()
+	dir := t.TempDir()
+	buf := &bytes.Buffer{}
+	return NewRunner(cfg, output.NewPrinterWithWriter(buf), dir, "run-1"), buf, dir
+}
+
+func TestRunner_RunHooks_Environment(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh syntax")
+	}
+	cfg := config.DefaultConfig()
+	cfg.Workflows["dev-story"] = config.WorkflowConfig{Hooks: config.HooksConfig{
+		OnFailure: []config.HookConfig{{
+			Name:    "record",
+			Command: `env | grep ^BMADUUM_ | sort > env.txt`,
+		}},
+	}}
+	runner, _, dir := newTestRunner(t, cfg)
+
+	err := runner.RunHooks(context.Background(), lifecycle.HookEvent{
+		Stage:          lifecycle.HookOnFailure,
+		StoryKey:       "6-1-first",
+		Workflow:       "dev-story",
+		ExitCode:       2,
+		Err:            errors.New("workflow failed"),
+		TranscriptPath: "/tmp/session.jsonl",
+	})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(dir, "env.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"BMADUUM_ERROR=workflow failed",
+		"BMADUUM_EXIT_CODE=2",
+		"BMADUUM_HOOK=on_failure",
+		"BMADUUM_RUN_ID=run-1",
+		"BMADUUM_STORY_KEY=6-1-first",
+		"BMADUUM_TRANSCRIPT_PATH=/tmp/session.jsonl",
+		"BMADUUM_WORKFLOW=dev-story",
+	}, strings.Split(strings.TrimSpace(string(data)), "\n"))
+}
+
+func TestRunner_RunHooks_OnError(t *testing.T) {
+	tests := []struct {
+		name        string
+		onError     string
+		wantErr     string
+		wantWarning bool
+	}{
+		{name: "fail by default", wantErr: "broken: exit code 3"},
+		{name: "fail", onError: config.HookOnErrorFail, wantErr: "broken: exit code 3"},
+		{name: "warn", onError: config.HookOnErrorWarn, wantWarning: true},
+		{name: "ignore", onError: config.HookOnErrorIgnore},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Lifecycle.Hooks.Pre = []config.HookConfig{
+				{Name: "broken", Command: "exit 3", OnError: tt.onError},
+				{Name: "after", Command: "echo after-ran"},
+			}
+			runner, buf, _ := newTestRunner(t, cfg)
+
+			err := runner.RunHooks(context.Background(), lifecycle.HookEvent{Stage: lifecycle.HookPre, StoryKey: "6-1-first"})
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Equal(t, tt.wantErr, err.Error())
+				assert.NotContains(t, buf.String(), "after-ran")
+				return
+			}
+			require.NoError(t, err)
+			assert.Contains(t, buf.String(), "after-ran")
+			assert.Equal(t, tt.wantWarning, strings.Contains(buf.String(), "Warning: hook broken: exit code 3"))
+		})
+	}
+}
+
+func TestRunner_RunHooks_SelectsHooks(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Lifecycle.Hooks.Post = []config.HookConfig{{Command: "echo story-post"}}
+	devStory := cfg.Workflows["dev-story"]
+	devStory.Hooks.Pre = []config.HookConfig{{Command: "echo dev-pre"}}
+	devStory.Hooks.Post = []config.HookConfig{{Command: "echo dev-post"}}
+	cfg.Workflows["dev-story"] = devStory
+	runner, buf, _ := newTestRunner(t, cfg)
+
+	require.NoError(t, runner.RunHooks(context.Background(), lifecycle.HookEvent{Stage: lifecycle.HookPost, StoryKey: "6-1-first", Workflow: "dev-story"}))
+	assert.Contains(t, buf.String(), "dev-post")
+	assert.NotContains(t, buf.String(), "dev-pre")
+	assert.NotContains(t, buf.String(), "story-post")
+
+	buf.Reset()
+	require.NoError(t, runner.RunHooks(context.Background(), lifecycle.HookEvent{Stage: lifecycle.HookPost, StoryKey: "6-1-first"}))
+	assert.Contains(t, buf.String(), "story-post")
+
+	buf.Reset()
+	require.NoError(t, runner.RunHooks(context.Background(), lifecycle.HookEvent{Stage: lifecycle.HookPre, StoryKey: "6-1-first", Workflow: "code-review"}))
+	assert.Empty(t, buf.String())
+}
@@ -0,0 +1,21 @@
+package lifecycle
+
+// StoryBrancher moves each story onto its own git branch.
+//
+// Start is called before a story's first workflow and checks out the story's
+// branch, creating it if needed. Finish is called once the story is done and
+// switches back to the base branch or leaves the story branch for review.
+// The [branch.Manager] type implements this interface.
+type StoryBrancher interface {
+	Start(storyKey string) error
+	Finish(storyKey string) error
+}
+
+// SetBrancher configures an optional [StoryBrancher].
+//
+// When set, stories with steps left to run are moved onto their branch before
+// the story pre hooks, and Finish runs after the story post hooks. A failed
+// story stays on its branch.
+func (e *Executor) SetBrancher(b StoryBrancher) {
+	e.brancher = b
+}
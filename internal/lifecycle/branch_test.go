@@ -0,0 +1,95 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bmaduum/internal/router"
+	"bmaduum/internal/status"
+)
+
+// MockBrancher implements StoryBrancher for testing.
+type MockBrancher struct {
+	// Calls records "start:key" and "finish:key" in call order.
+	Calls []string
+	// StartErr is returned by Start.
+	StartErr error
+}
+
+func (m *MockBrancher) Start(storyKey string) error {
+	m.Calls = append(m.Calls, "start:"+storyKey)
+	return m.StartErr
+}
+
+func (m *MockBrancher) Finish(storyKey string) error {
+	m.Calls = append(m.Calls, "finish:"+storyKey)
+	return nil
+}
+
+func newReviewStatusReader() *MockStatusReader {
+	return &MockStatusReader{
+		GetStoryStatusFunc: func(storyKey string) (status.Status, error) {
+			return status.StatusReview, nil
+		},
+	}
+}
+
+func TestExecute_Brancher(t *testing.T) {
+	runner := &MockWorkflowRunner{}
+	brancher := &MockBrancher{}
+
+	executor := NewExecutor(runner, newReviewStatusReader(), &MockStatusWriter{})
+	executor.SetBrancher(brancher)
+	err := executor.Execute(context.Background(), "6-1-story")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"start:6-1-story", "finish:6-1-story"}, brancher.Calls)
+	assert.Len(t, runner.Calls, 2)
+}
+
+func TestExecute_BrancherStartFails(t *testing.T) {
+	runner := &MockWorkflowRunner{}
+	brancher := &MockBrancher{StartErr: errors.New("branch story/6-1-story is checked out elsewhere")}
+
+	executor := NewExecutor(runner, newReviewStatusReader(), &MockStatusWriter{})
+	executor.SetBrancher(brancher)
+	err := executor.Execute(context.Background(), "6-1-story")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "preparing story branch")
+	assert.Empty(t, runner.Calls)
+}
+
+func TestExecute_BrancherStoryFails(t *testing.T) {
+	runner := &MockWorkflowRunner{
+		RunSingleFunc: func(ctx context.Context, workflowName, storyKey string) int { return 1 },
+	}
+	brancher := &MockBrancher{}
+
+	executor := NewExecutor(runner, newReviewStatusReader(), &MockStatusWriter{})
+	executor.SetBrancher(brancher)
+	err := executor.Execute(context.Background(), "6-1-story")
+
+	require.Error(t, err)
+	assert.Equal(t, []string{"start:6-1-story"}, brancher.Calls, "a failed story stays on its branch")
+}
+
+func TestExecute_BrancherSkipsDoneStories(t *testing.T) {
+	brancher := &MockBrancher{}
+	reader := &MockStatusReader{
+		GetStoryStatusFunc: func(storyKey string) (status.Status, error) {
+			return status.StatusDone, nil
+		},
+	}
+
+	executor := NewExecutor(&MockWorkflowRunner{}, reader, &MockStatusWriter{})
+	executor.SetBrancher(brancher)
+	err := executor.Execute(context.Background(), "6-1-story")
+
+	assert.ErrorIs(t, err, router.ErrStoryComplete)
+	assert.Empty(t, brancher.Calls)
+}
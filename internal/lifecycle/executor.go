@@ -5,14 +5,21 @@
 // updates the story status automatically after successful completion.
 //
 // Key concepts:
-//   - Lifecycle steps are determined by [router.GetLifecycle] based on current status
+//   - Lifecycle steps are determined by [router.GetLifecycleFrom] based on current
+//     status, from the standard lifecycle or one set with [Executor.SetLifecycle]
 //   - Each step runs a workflow then updates status via [StatusWriter]
 //   - Progress can be tracked via [ProgressCallback]
+//   - A code review that requests changes sends the story back through
+//     dev-story and code-review, up to [Executor.SetMaxReviewCycles] times
+//   - A failed story's partial work is kept, stashed or reset according to
+//     [Executor.SetFailurePolicy]
 package lifecycle
 
 import (
 	"context"
 	"fmt"
+	"slices"
+	"time"
 
 	"bmaduum/internal/router"
 	"bmaduum/internal/status"
@@ -27,6 +34,24 @@ type WorkflowRunner interface {
 	RunSingle(ctx context.Context, workflowName, storyKey string) int
 }
 
+// MessageRunner is an optional extension of [WorkflowRunner].
+//
+// Runners that implement it, such as [workflow.Runner], expose the text of
+// the last workflow's final message so outcomes reported there, like a code
+// review's REVIEW_RESULT marker, can be detected.
+type MessageRunner interface {
+	LastMessage() string
+}
+
+// ModelRunner is an optional extension of [WorkflowRunner].
+//
+// Runners that implement it, such as [workflow.Runner], report the model the
+// last workflow ran on, which differs from the configured one after a model
+// fallback. It is recorded in [StepResult.Model] and the status history.
+type ModelRunner interface {
+	LastModel() string
+}
+
 // StatusReader is the interface for looking up story status.
 //
 // GetStoryStatus retrieves the current [status.Status] for a story key.
@@ -43,6 +68,42 @@ type StatusWriter interface {
 	UpdateStatus(storyKey string, newStatus status.Status) error
 }
 
+// WorkflowStatusWriter is an optional extension of [StatusWriter].
+//
+// Writers that implement it, such as [status.Writer], are told which workflow
+// caused each update, and the model it ran on if known, so they can be
+// recorded in the status history.
+type WorkflowStatusWriter interface {
+	UpdateStatusForWorkflow(storyKey string, newStatus status.Status, workflow, model string) error
+}
+
+// StepCheck verifies the outcome of a workflow that exited successfully.
+//
+// It returns an error describing what the workflow failed to do.
+type StepCheck func() error
+
+// StepVerifier checks that a workflow actually advanced the story.
+//
+// Prepare is called before the workflow runs so the verifier can capture any
+// state the check compares against (such as git HEAD). It returns the check to
+// run after a successful exit, or nil if the workflow is not verified.
+// The [verify.Verifier] type implements this interface.
+type StepVerifier interface {
+	Prepare(storyKey, workflow string) StepCheck
+}
+
+// GateChecker runs quality gates after a workflow.
+//
+// Run returns an error describing the failing gates, or nil when all gates
+// passed or none are configured. FixAttempts is how many times Fix may start
+// a follow-up session to make failing gates pass before the step fails.
+// The [gate.Checker] type implements this interface.
+type GateChecker interface {
+	Run(ctx context.Context, storyKey, workflow string) error
+	FixAttempts(workflow string) int
+	Fix(ctx context.Context, storyKey, workflow string, failure error) error
+}
+
 // ProgressCallback is invoked before each workflow step begins execution.
 //
 // The callback receives stepIndex (1-based), totalSteps count, and the workflow name.
@@ -50,6 +111,26 @@ type StatusWriter interface {
 // via [Executor.SetProgressCallback].
 type ProgressCallback func(stepIndex, totalSteps int, workflow string)
 
+// StepResult records one workflow step run by [Executor.Execute].
+type StepResult struct {
+	// Workflow is the workflow that ran.
+	Workflow string
+
+	// Cycle is the 1-based review cycle for dev-story and code-review steps,
+	// or 0 for steps outside the review loop.
+	Cycle int
+
+	// Model is the model the workflow ran on, if the runner reports it
+	// (see [ModelRunner]).
+	Model string
+
+	// Duration is how long the step took, including verification and gates.
+	Duration time.Duration
+
+	// Success reports whether the step completed.
+	Success bool
+}
+
 // Executor orchestrates the complete story lifecycle from current status to done.
 //
 // Executor uses dependency injection for testability: [WorkflowRunner] executes workflows,
@@ -59,7 +140,20 @@ type Executor struct {
 	runner           WorkflowRunner
 	statusReader     StatusReader
 	statusWriter     StatusWriter
+	verifier         StepVerifier
+	gates            GateChecker
+	hooks            HookRunner
+	brancher         StoryBrancher
+	failurePolicy    FailurePolicy
+	worktree         Worktree
+	checkpoints      CheckpointStore
+	lifecycle        []router.LifecycleStep
 	progressCallback ProgressCallback
+	maxReviewCycles  int
+	results          []StepResult
+	lastExitCode     int
+	lastModel        string
+	partialWork      string
 }
 
 // NewExecutor creates a new Executor with the required dependencies.
@@ -69,9 +163,11 @@ type Executor struct {
 // to enable progress reporting.
 func NewExecutor(runner WorkflowRunner, reader StatusReader, writer StatusWriter) *Executor {
 	return &Executor{
-		runner:       runner,
-		statusReader: reader,
-		statusWriter: writer,
+		runner:          runner,
+		statusReader:    reader,
+		statusWriter:    writer,
+		maxReviewCycles: DefaultMaxReviewCycles,
+		lifecycle:       router.DefaultLifecycle(),
 	}
 }
 
@@ -84,16 +180,78 @@ func (e *Executor) SetProgressCallback(cb ProgressCallback) {
 	e.progressCallback = cb
 }
 
+// SetVerifier configures an optional [StepVerifier] for workflow steps.
+//
+// When set, each step's check runs after the workflow exits successfully and
+// before the status is updated; a failed check fails the step. Without a
+// verifier, exit code 0 alone counts as success.
+func (e *Executor) SetVerifier(v StepVerifier) {
+	e.verifier = v
+}
+
+// SetGates configures an optional [GateChecker] for workflow steps.
+//
+// When set, the gates run after each workflow is verified and before the
+// status is updated. Failing gates fail the step unless a fix session makes
+// them pass within the configured number of attempts.
+func (e *Executor) SetGates(g GateChecker) {
+	e.gates = g
+}
+
+// SetMaxReviewCycles sets how many times code-review may run for a story.
+//
+// When a review requests changes, the story goes back through dev-story and
+// code-review until a review comes back clean or n reviews have run, after
+// which the step fails with the story left in-progress. Values below 1 are
+// treated as 1. Default: [DefaultMaxReviewCycles].
+func (e *Executor) SetMaxReviewCycles(n int) {
+	e.maxReviewCycles = max(n, 1)
+}
+
+// SetLifecycle replaces the standard lifecycle (see [router.DefaultLifecycle])
+// with steps, e.g. to run a custom workflow after dev-story.
+//
+// Stories start at the first step that runs from their current status (see
+// [router.GetLifecycleFrom]). When a review requests changes, the steps from
+// dev-story through code-review run again. An empty steps keeps the
+// standard lifecycle.
+func (e *Executor) SetLifecycle(steps []router.LifecycleStep) {
+	if len(steps) == 0 {
+		steps = router.DefaultLifecycle()
+	}
+	e.lifecycle = steps
+}
+
+// Results returns the steps run by the last call to [Executor.Execute],
+// including the failed step if it stopped early.
+func (e *Executor) Results() []StepResult {
+	return e.results
+}
+
 // Execute runs the complete story lifecycle from current status to done.
 //
 // Execute looks up the story's current status, determines the remaining workflow steps
-// via [router.GetLifecycle], and runs each workflow in sequence. After each successful
+// via [router.GetLifecycleFrom], and runs each workflow in sequence. After each successful
 // workflow, the story status is updated to the next state.
 //
+// After code-review, the outcome is read from a REVIEW_RESULT marker in the
+// final message (see [MessageRunner]) or from the review moving the story
+// back to in-progress. If changes were requested, dev-story and code-review
+// run again, up to the limit set by [Executor.SetMaxReviewCycles].
+//
 // Execute uses fail-fast behavior: it stops on the first error and returns immediately.
 // Errors can occur from status lookup failure, workflow execution failure (non-zero exit),
-// or status update failure. For stories already done, Execute returns [router.ErrStoryComplete].
+// step verification failure (see [Executor.SetVerifier]), failing quality gates
+// (see [Executor.SetGates]), failing hooks (see [Executor.SetHooks]), story
+// branch switches (see [Executor.SetBrancher]), or status update failure.
+// When a story fails, including through a failing story pre or post hook,
+// the on_failure hooks run and its partial work is handled according to the
+// failure policy (see [Executor.SetFailurePolicy]).
+// For stories already done, Execute returns [router.ErrStoryComplete].
 func (e *Executor) Execute(ctx context.Context, storyKey string) error {
+	e.results = nil
+	e.partialWork = ""
+
 	// Get current story status
 	currentStatus, err := e.statusReader.GetStoryStatus(storyKey)
 	if err != nil {
@@ -101,36 +259,232 @@ func (e *Executor) Execute(ctx context.Context, storyKey string) error {
 	}
 
 	// Get lifecycle steps from current status
-	steps, err := router.GetLifecycle(currentStatus)
+	steps, err := router.GetLifecycleFrom(e.lifecycle, currentStatus)
 	if err != nil {
 		return err // Returns router.ErrStoryComplete for done stories
 	}
 
-	// Get total steps count for progress reporting
-	totalSteps := len(steps)
+	if e.brancher != nil {
+		if err := e.brancher.Start(storyKey); err != nil {
+			return fmt.Errorf("preparing story branch: %w", err)
+		}
+	}
+
+	base, err := e.baseCommit(storyKey)
+	if err != nil {
+		return err
+	}
+
+	// A failing story hook fails the story like a failing step
+	err = e.runHooks(ctx, HookEvent{Stage: HookPre, StoryKey: storyKey})
+	if err == nil {
+		err = e.runSteps(ctx, storyKey, steps)
+	}
+	if err == nil {
+		err = e.runHooks(ctx, HookEvent{Stage: HookPost, StoryKey: storyKey})
+	}
+	if err != nil {
+		e.runFailureHooks(ctx, storyKey, "", err)
+		if saveErr := e.handleFailure(storyKey, currentStatus, base, len(steps)); saveErr != nil {
+			return fmt.Errorf("%w (saving checkpoint: %v)", err, saveErr)
+		}
+		return err
+	}
+
+	if e.brancher != nil {
+		if err := e.brancher.Finish(storyKey); err != nil {
+			return fmt.Errorf("finishing story branch: %w", err)
+		}
+	}
+
+	return e.clearCheckpoint(storyKey)
+}
+
+// runSteps runs lifecycle steps in sequence; a review requesting changes
+// adds another dev-story → code-review cycle.
+func (e *Executor) runSteps(ctx context.Context, storyKey string, steps []router.LifecycleStep) error {
+	reviewCycle := 1
+
+	for i := 0; i < len(steps); i++ {
+		step := steps[i]
+		result := StepResult{Workflow: step.Workflow}
+		if step.Workflow == devWorkflow || step.Workflow == reviewWorkflow {
+			result.Cycle = reviewCycle
+		}
 
-	// Execute each step in sequence
-	for i, step := range steps {
 		// Call progress callback if set
 		if e.progressCallback != nil {
-			e.progressCallback(i+1, totalSteps, step.Workflow)
+			e.progressCallback(i+1, len(steps), step.Workflow)
 		}
 
-		// Run the workflow
-		exitCode := e.runner.RunSingle(ctx, step.Workflow, storyKey)
-		if exitCode != 0 {
-			return fmt.Errorf("workflow failed: %s returned exit code %d", step.Workflow, exitCode)
+		start := time.Now()
+		e.lastExitCode = 0
+		e.lastModel = ""
+		rework, err := e.runStep(ctx, storyKey, step, reviewCycle)
+		if err != nil {
+			e.runFailureHooks(ctx, storyKey, step.Workflow, err)
 		}
-
-		// Update status after successful workflow
-		if err := e.statusWriter.UpdateStatus(storyKey, step.NextStatus); err != nil {
+		result.Model = e.lastModel
+		result.Duration = time.Since(start)
+		result.Success = err == nil
+		e.results = append(e.results, result)
+		if err != nil {
 			return err
 		}
+
+		if rework {
+			reviewCycle++
+			steps = slices.Insert(steps, i+1, e.reworkSteps()...)
+		}
 	}
 
 	return nil
 }
 
+// Workflows that make up the review loop.
+const (
+	devWorkflow    = "dev-story"
+	reviewWorkflow = "code-review"
+)
+
+// reworkSteps returns the steps a review requesting changes sends the story
+// back through: the lifecycle's steps from dev-story through code-review,
+// so custom steps between them run again too.
+func (e *Executor) reworkSteps() []router.LifecycleStep {
+	steps, err := router.GetLifecycleFrom(e.lifecycle, status.StatusBacklog)
+	if err == nil {
+		start := slices.IndexFunc(steps, func(s router.LifecycleStep) bool { return s.Workflow == devWorkflow })
+		end := slices.IndexFunc(steps, func(s router.LifecycleStep) bool { return s.Workflow == reviewWorkflow })
+		if start >= 0 && end >= start {
+			return slices.Clone(steps[start : end+1])
+		}
+	}
+	return []router.LifecycleStep{
+		{Workflow: devWorkflow, NextStatus: status.StatusReview},
+		{Workflow: reviewWorkflow, NextStatus: status.StatusDone},
+	}
+}
+
+// runStep runs one lifecycle step: the workflow, its verification and gates,
+// and the status update.
+//
+// For code-review it reports whether the review requested changes and
+// another dev-story → code-review cycle should follow. In that case the story
+// is moved to in-progress instead of advancing. Once reviewCycle reaches the
+// limit, a review requesting changes is an error.
+func (e *Executor) runStep(ctx context.Context, storyKey string, step router.LifecycleStep, reviewCycle int) (bool, error) {
+	// Capture pre-step state for verification
+	var check StepCheck
+	if e.verifier != nil {
+		check = e.verifier.Prepare(storyKey, step.Workflow)
+	}
+
+	// Remember the status a review starts from to see if it sends the story back
+	var beforeReview status.Status
+	if step.Workflow == reviewWorkflow {
+		current, err := e.statusReader.GetStoryStatus(storyKey)
+		if err != nil {
+			return false, err
+		}
+		beforeReview = current
+	}
+
+	if err := e.runHooks(ctx, HookEvent{Stage: HookPre, StoryKey: storyKey, Workflow: step.Workflow}); err != nil {
+		return false, err
+	}
+
+	// Run the workflow
+	exitCode := e.runner.RunSingle(ctx, step.Workflow, storyKey)
+	e.lastExitCode = exitCode
+	if r, ok := e.runner.(ModelRunner); ok {
+		e.lastModel = r.LastModel()
+	}
+	if exitCode != 0 {
+		return false, fmt.Errorf("workflow failed: %s returned exit code %d", step.Workflow, exitCode)
+	}
+
+	if err := e.runHooks(ctx, HookEvent{Stage: HookPost, StoryKey: storyKey, Workflow: step.Workflow}); err != nil {
+		return false, err
+	}
+
+	// Make sure the workflow actually did its job
+	if check != nil {
+		if err := check(); err != nil {
+			return false, fmt.Errorf("verification failed after %s: %w", step.Workflow, err)
+		}
+	}
+
+	// Send the story back to development if the review found issues
+	if step.Workflow == reviewWorkflow {
+		outcome, err := e.reviewOutcome(storyKey, beforeReview)
+		if err != nil {
+			return false, err
+		}
+		if outcome == ReviewChangesRequested {
+			if err := e.reopenStory(storyKey); err != nil {
+				return false, err
+			}
+			if reviewCycle >= e.maxReviewCycles {
+				return false, fmt.Errorf("code review still requests changes after %d review cycle(s)", reviewCycle)
+			}
+			return true, nil
+		}
+	}
+
+	// Run quality gates, with fix sessions if configured
+	if err := e.runGates(ctx, storyKey, step.Workflow); err != nil {
+		return false, err
+	}
+
+	// Update status after successful workflow
+	return false, e.updateStatus(storyKey, step.NextStatus, step.Workflow, e.lastModel)
+}
+
+// reopenStory moves a story whose review requested changes to in-progress,
+// unless the review already did.
+func (e *Executor) reopenStory(storyKey string) error {
+	current, err := e.statusReader.GetStoryStatus(storyKey)
+	if err != nil {
+		return err
+	}
+	if current == status.StatusInProgress {
+		return nil
+	}
+	return e.updateStatus(storyKey, status.StatusInProgress, reviewWorkflow, e.lastModel)
+}
+
+// runGates runs the quality gates for a workflow.
+//
+// While gates fail and fix attempts remain, a fix session is run and the
+// gates are checked again.
+func (e *Executor) runGates(ctx context.Context, storyKey, workflow string) error {
+	if e.gates == nil {
+		return nil
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := e.gates.Run(ctx, storyKey, workflow)
+		if err == nil {
+			return nil
+		}
+		if attempt >= e.gates.FixAttempts(workflow) {
+			return fmt.Errorf("quality gates failed after %s: %w", workflow, err)
+		}
+		if fixErr := e.gates.Fix(ctx, storyKey, workflow, err); fixErr != nil {
+			return fmt.Errorf("fixing quality gates after %s: %w", workflow, fixErr)
+		}
+	}
+}
+
+// updateStatus persists a status update, passing the workflow name and model
+// to writers that implement [WorkflowStatusWriter].
+func (e *Executor) updateStatus(storyKey string, newStatus status.Status, workflow, model string) error {
+	if w, ok := e.statusWriter.(WorkflowStatusWriter); ok {
+		return w.UpdateStatusForWorkflow(storyKey, newStatus, workflow, model)
+	}
+	return e.statusWriter.UpdateStatus(storyKey, newStatus)
+}
+
 // GetSteps returns the remaining lifecycle steps for a story without executing them.
 //
 // GetSteps provides dry-run preview functionality, showing what workflows would execute
@@ -147,7 +501,7 @@ func (e *Executor) GetSteps(storyKey string) ([]router.LifecycleStep, error) {
 	}
 
 	// Get lifecycle steps from current status
-	steps, err := router.GetLifecycle(currentStatus)
+	steps, err := router.GetLifecycleFrom(e.lifecycle, currentStatus)
 	if err != nil {
 		return nil, err // Returns router.ErrStoryComplete for done stories
 	}
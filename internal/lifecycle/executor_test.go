@@ -400,3 +400,205 @@ func TestGetSteps(t *testing.T) {
 		})
 	}
 }
+
+// MockWorkflowStatusWriter implements WorkflowStatusWriter for testing.
+type MockWorkflowStatusWriter struct {
+	MockStatusWriter
+	// Workflows records the workflow passed with each update.
+	Workflows []string
+	// Models records the model passed with each update.
+	Models []string
+}
+
+func (m *MockWorkflowStatusWriter) UpdateStatusForWorkflow(storyKey string, newStatus status.Status, workflow, model string) error {
+	m.Workflows = append(m.Workflows, workflow)
+	m.Models = append(m.Models, model)
+	return m.UpdateStatus(storyKey, newStatus)
+}
+
+func TestExecute_PassesWorkflowToWorkflowStatusWriter(t *testing.T) {
+	runner := &MockWorkflowRunner{}
+	reader := &MockStatusReader{
+		GetStoryStatusFunc: func(storyKey string) (status.Status, error) {
+			return status.StatusReview, nil
+		},
+	}
+	writer := &MockWorkflowStatusWriter{}
+
+	executor := NewExecutor(runner, reader, writer)
+	err := executor.Execute(context.Background(), "EPIC-1-story")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"code-review", "git-commit"}, writer.Workflows)
+	assert.Equal(t, []string{"", ""}, writer.Models)
+	assert.Len(t, writer.Calls, 2)
+}
+
+// MockModelRunner implements ModelRunner for testing.
+type MockModelRunner struct {
+	MockWorkflowRunner
+	// Models maps workflow names to the model they report running on.
+	Models map[string]string
+	last   string
+}
+
+func (m *MockModelRunner) RunSingle(ctx context.Context, workflowName, storyKey string) int {
+	m.last = m.Models[workflowName]
+	return m.MockWorkflowRunner.RunSingle(ctx, workflowName, storyKey)
+}
+
+func (m *MockModelRunner) LastModel() string {
+	return m.last
+}
+
+func TestExecute_RecordsModel(t *testing.T) {
+	runner := &MockModelRunner{Models: map[string]string{"code-review": "sonnet"}}
+	reader := &MockStatusReader{
+		GetStoryStatusFunc: func(storyKey string) (status.Status, error) {
+			return status.StatusReview, nil
+		},
+	}
+	writer := &MockWorkflowStatusWriter{}
+
+	executor := NewExecutor(runner, reader, writer)
+	require.NoError(t, executor.Execute(context.Background(), "EPIC-1-story"))
+
+	assert.Equal(t, []string{"sonnet", ""}, writer.Models)
+	results := executor.Results()
+	require.Len(t, results, 2)
+	assert.Equal(t, "sonnet", results[0].Model)
+	assert.Empty(t, results[1].Model)
+}
+
+// MockStepVerifier implements StepVerifier for testing.
+type MockStepVerifier struct {
+	// Failures maps workflow names to the error their check returns.
+	Failures map[string]error
+	// Prepared records the workflows Prepare was called for.
+	Prepared []string
+}
+
+func (m *MockStepVerifier) Prepare(storyKey, workflow string) StepCheck {
+	m.Prepared = append(m.Prepared, workflow)
+	return func() error { return m.Failures[workflow] }
+}
+
+func TestExecute_Verification(t *testing.T) {
+	runner := &MockWorkflowRunner{}
+	reader := &MockStatusReader{
+		GetStoryStatusFunc: func(storyKey string) (status.Status, error) {
+			return status.StatusReadyForDev, nil
+		},
+	}
+	writer := &MockStatusWriter{}
+	verifier := &MockStepVerifier{
+		Failures: map[string]error{"code-review": errors.New("nothing reviewed")},
+	}
+
+	executor := NewExecutor(runner, reader, writer)
+	executor.SetVerifier(verifier)
+	err := executor.Execute(context.Background(), "EPIC-1-story")
+
+	require.Error(t, err)
+	assert.Equal(t, "verification failed after code-review: nothing reviewed", err.Error())
+	assert.Equal(t, []string{"dev-story", "code-review"}, verifier.Prepared)
+
+	// Status is only updated for the verified dev-story step
+	require.Len(t, writer.Calls, 1)
+	assert.Equal(t, status.StatusReview, writer.Calls[0].NewStatus)
+}
+
+// MockGateChecker implements GateChecker for testing.
+type MockGateChecker struct {
+	// Failures maps workflow names to how many Run calls fail before passing.
+	Failures map[string]int
+	// Attempts is returned by FixAttempts for every workflow.
+	Attempts int
+	// Runs and Fixes record the workflows gates were run and fixed for.
+	Runs  []string
+	Fixes []string
+}
+
+func (m *MockGateChecker) Run(ctx context.Context, storyKey, workflow string) error {
+	m.Runs = append(m.Runs, workflow)
+	if m.Failures[workflow] > 0 {
+		m.Failures[workflow]--
+		return errors.New("failed gates: tests (exit code 1)")
+	}
+	return nil
+}
+
+func (m *MockGateChecker) FixAttempts(workflow string) int {
+	return m.Attempts
+}
+
+func (m *MockGateChecker) Fix(ctx context.Context, storyKey, workflow string, failure error) error {
+	m.Fixes = append(m.Fixes, workflow)
+	return nil
+}
+
+func TestExecute_Gates(t *testing.T) {
+	tests := []struct {
+		name        string
+		failures    int
+		attempts    int
+		wantErr     string
+		wantFixes   int
+		wantUpdates int
+	}{
+		{
+			name:        "gates pass",
+			wantUpdates: 3,
+		},
+		{
+			name:        "fix session makes gates pass",
+			failures:    2,
+			attempts:    2,
+			wantFixes:   2,
+			wantUpdates: 3,
+		},
+		{
+			name:        "fix attempts exhausted",
+			failures:    3,
+			attempts:    2,
+			wantErr:     "quality gates failed after dev-story: failed gates: tests (exit code 1)",
+			wantFixes:   2,
+			wantUpdates: 0,
+		},
+		{
+			name:        "no fix attempts",
+			failures:    1,
+			wantErr:     "quality gates failed after dev-story: failed gates: tests (exit code 1)",
+			wantUpdates: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &MockWorkflowRunner{}
+			reader := &MockStatusReader{
+				GetStoryStatusFunc: func(storyKey string) (status.Status, error) {
+					return status.StatusReadyForDev, nil
+				},
+			}
+			writer := &MockStatusWriter{}
+			gates := &MockGateChecker{
+				Failures: map[string]int{"dev-story": tt.failures},
+				Attempts: tt.attempts,
+			}
+
+			executor := NewExecutor(runner, reader, writer)
+			executor.SetGates(gates)
+			err := executor.Execute(context.Background(), "EPIC-1-story")
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Equal(t, tt.wantErr, err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Len(t, gates.Fixes, tt.wantFixes)
+			assert.Len(t, writer.Calls, tt.wantUpdates)
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package lifecycle
+
+import (
+	"errors"
+	"fmt"
+
+	"bmaduum/internal/state"
+	"bmaduum/internal/status"
+)
+
+// FailurePolicy decides what happens to a failed story's partial work, so the
+// next story does not build on top of it.
+type FailurePolicy string
+
+const (
+	// FailureKeep leaves the partial work in the working tree.
+	FailureKeep FailurePolicy = "keep"
+
+	// FailureStash stashes uncommitted changes, including untracked files,
+	// under a stash named after the story.
+	FailureStash FailurePolicy = "stash"
+
+	// FailureReset resets the working tree to the commit the story started
+	// from, discarding uncommitted changes and commits made since.
+	FailureReset FailurePolicy = "reset"
+)
+
+// failurePolicyWorkflow is recorded in the status history when a failure
+// policy moves a story back to its start status.
+const failurePolicyWorkflow = "failure-policy"
+
+// Worktree is the project's git working tree, as seen by the failure policy.
+//
+// Stash returns false when there was nothing to stash. Neither Stash nor
+// Reset may touch the sprint status file.
+// The [worktree.Worktree] type implements this interface.
+type Worktree interface {
+	Head() (string, error)
+	Stash(message string) (bool, error)
+	Reset(commit string) error
+}
+
+// CheckpointStore persists the state of a failed story for resume.
+// The [state.Manager] type implements this interface.
+type CheckpointStore interface {
+	Load() (state.State, error)
+	Save(s state.State) error
+	Clear() error
+}
+
+// SetFailurePolicy configures what happens to a failed story's partial work.
+//
+// Before a story starts, the commit checked out in wt is recorded. When the
+// story fails, after its on_failure hooks, [FailureStash] stashes the partial
+// work and [FailureReset] resets wt to that commit; both move the story back
+// to the status it started from. Default: [FailureKeep], which leaves the
+// working tree alone.
+func (e *Executor) SetFailurePolicy(policy FailurePolicy, wt Worktree) {
+	e.failurePolicy = policy
+	e.worktree = wt
+}
+
+// SetCheckpoints configures an optional [CheckpointStore].
+//
+// When set, a failed story's checkpoint (failed step, start status, pre-story
+// commit and where its partial work went) is saved, and cleared once the
+// story is done. A resumed story reuses the pre-story commit of its
+// checkpoint.
+func (e *Executor) SetCheckpoints(store CheckpointStore) {
+	e.checkpoints = store
+}
+
+// PartialWork describes where the partial work of the story that failed in
+// the last call to [Executor.Execute] went, or is empty if it did not fail.
+func (e *Executor) PartialWork() string {
+	return e.partialWork
+}
+
+// baseCommit returns the commit the story started from: the one recorded in
+// the story's checkpoint, or the commit checked out now. It is only an error
+// not to know it under [FailureReset].
+func (e *Executor) baseCommit(storyKey string) (string, error) {
+	if e.checkpoints != nil {
+		if cp, err := e.checkpoints.Load(); err == nil && cp.StoryKey == storyKey && cp.BaseCommit != "" {
+			return cp.BaseCommit, nil
+		}
+	}
+	if e.worktree == nil {
+		return "", nil
+	}
+
+	head, err := e.worktree.Head()
+	if err != nil && e.failurePolicy == FailureReset {
+		return "", fmt.Errorf("recording pre-story commit: %w", err)
+	}
+	return head, nil
+}
+
+// handleFailure applies the failure policy to a failed story and saves its
+// checkpoint. totalSteps is the number of steps the story started with;
+// review cycles may have added more. Returns an error only if the checkpoint
+// could not be saved.
+func (e *Executor) handleFailure(storyKey string, startStatus status.Status, base string, totalSteps int) error {
+	e.partialWork = e.applyFailurePolicy(storyKey, startStatus, base)
+
+	if e.checkpoints == nil {
+		return nil
+	}
+	stepIndex := max(len(e.results)-1, 0)
+	return e.checkpoints.Save(state.State{
+		StoryKey:    storyKey,
+		StepIndex:   stepIndex,
+		TotalSteps:  max(totalSteps, stepIndex+1),
+		StartStatus: string(startStatus),
+		BaseCommit:  base,
+		PartialWork: e.partialWork,
+	})
+}
+
+// applyFailurePolicy stashes or resets the partial work of a failed story
+// and describes where it went.
+func (e *Executor) applyFailurePolicy(storyKey string, startStatus status.Status, base string) string {
+	if e.worktree == nil {
+		return "kept in the working tree"
+	}
+
+	switch e.failurePolicy {
+	case FailureKeep, "":
+		return "kept in the working tree"
+
+	case FailureStash:
+		message := fmt.Sprintf("bmaduum: %s partial work", storyKey)
+		stashed, err := e.worktree.Stash(message)
+		if err != nil {
+			return fmt.Sprintf("kept in the working tree (stash failed: %v)", err)
+		}
+		if err := e.restoreStatus(storyKey, startStatus); err != nil {
+			return fmt.Sprintf("stashed as %q, but the story status could not be reset: %v", message, err)
+		}
+		if !stashed {
+			return "nothing to stash, the working tree was clean"
+		}
+		return fmt.Sprintf("stashed as %q (restore it with git stash pop)", message)
+
+	case FailureReset:
+		if base == "" {
+			return "kept in the working tree (the pre-story commit is unknown)"
+		}
+		head, _ := e.worktree.Head()
+		if err := e.worktree.Reset(base); err != nil {
+			return fmt.Sprintf("kept in the working tree (reset failed: %v)", err)
+		}
+		desc := fmt.Sprintf("discarded, reset to pre-story commit %s", shortCommit(base))
+		if head != "" && head != base {
+			desc += fmt.Sprintf(" (commits made during the story remain reachable from %s)", shortCommit(head))
+		}
+		if err := e.restoreStatus(storyKey, startStatus); err != nil {
+			desc += fmt.Sprintf(", but the story status could not be reset: %v", err)
+		}
+		return desc
+
+	default:
+		return fmt.Sprintf("kept in the working tree (unknown failure policy %q)", e.failurePolicy)
+	}
+}
+
+// restoreStatus moves a story whose partial work was removed back to the
+// status it started from, so its workflows run again from the start.
+func (e *Executor) restoreStatus(storyKey string, startStatus status.Status) error {
+	current, err := e.statusReader.GetStoryStatus(storyKey)
+	if err != nil {
+		return err
+	}
+	if current == startStatus {
+		return nil
+	}
+	return e.updateStatus(storyKey, startStatus, failurePolicyWorkflow, "")
+}
+
+// clearCheckpoint removes the checkpoint of a story that is done, leaving
+// checkpoints of other stories alone.
+func (e *Executor) clearCheckpoint(storyKey string) error {
+	if e.checkpoints == nil {
+		return nil
+	}
+	cp, err := e.checkpoints.Load()
+	if errors.Is(err, state.ErrNoState) || (err == nil && cp.StoryKey != storyKey) {
+		return nil
+	}
+	if err := e.checkpoints.Clear(); err != nil {
+		return fmt.Errorf("clearing checkpoint: %w", err)
+	}
+	return nil
+}
+
+// shortCommit abbreviates a commit hash for messages.
+func shortCommit(commit string) string {
+	if len(commit) > 12 {
+		return commit[:12]
+	}
+	return commit
+}
@@ -0,0 +1,203 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bmaduum/internal/state"
+	"bmaduum/internal/status"
+)
+
+// MockWorktree implements Worktree for testing.
+type MockWorktree struct {
+	// HeadCommit is returned by Head.
+	HeadCommit string
+	// Stashes records stash messages.
+	Stashes []string
+	// ResetTo records Reset commits.
+	ResetTo []string
+	// Dirty makes Stash report that something was stashed.
+	Dirty bool
+}
+
+func (m *MockWorktree) Head() (string, error) {
+	if m.HeadCommit == "" {
+		return "", errors.New("no commits")
+	}
+	return m.HeadCommit, nil
+}
+
+func (m *MockWorktree) Stash(message string) (bool, error) {
+	m.Stashes = append(m.Stashes, message)
+	return m.Dirty, nil
+}
+
+func (m *MockWorktree) Reset(commit string) error {
+	m.ResetTo = append(m.ResetTo, commit)
+	m.HeadCommit = commit
+	return nil
+}
+
+// MockCheckpointStore implements CheckpointStore in memory.
+type MockCheckpointStore struct {
+	Saved   *state.State
+	Cleared int
+}
+
+func (m *MockCheckpointStore) Load() (state.State, error) {
+	if m.Saved == nil {
+		return state.State{}, state.ErrNoState
+	}
+	return *m.Saved, nil
+}
+
+func (m *MockCheckpointStore) Save(s state.State) error {
+	m.Saved = &s
+	return nil
+}
+
+func (m *MockCheckpointStore) Clear() error {
+	m.Saved = nil
+	m.Cleared++
+	return nil
+}
+
+// failOn returns a runner that fails the given workflow and moves HEAD to
+// "partial" when it runs, as if the agent had committed.
+func failOn(workflow string, ws *MockWorktree) *MockWorkflowRunner {
+	return &MockWorkflowRunner{
+		RunSingleFunc: func(ctx context.Context, workflowName, storyKey string) int {
+			if workflowName == workflow {
+				ws.HeadCommit = "partial"
+				return 1
+			}
+			return 0
+		},
+	}
+}
+
+func TestExecute_FailurePolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      FailurePolicy
+		dirty       bool
+		wantStashes []string
+		wantReset   []string
+		wantStatus  status.Status
+		wantWork    string
+	}{
+		{
+			name:       "keep leaves the working tree alone",
+			policy:     FailureKeep,
+			wantStatus: status.StatusReadyForDev,
+			wantWork:   "kept in the working tree",
+		},
+		{
+			name:        "stash saves uncommitted work under the story's name",
+			policy:      FailureStash,
+			dirty:       true,
+			wantStashes: []string{"bmaduum: 6-1-story partial work"},
+			wantStatus:  status.StatusBacklog,
+			wantWork:    `stashed as "bmaduum: 6-1-story partial work" (restore it with git stash pop)`,
+		},
+		{
+			name:        "stash with a clean tree",
+			policy:      FailureStash,
+			wantStashes: []string{"bmaduum: 6-1-story partial work"},
+			wantStatus:  status.StatusBacklog,
+			wantWork:    "nothing to stash, the working tree was clean",
+		},
+		{
+			name:       "reset returns to the pre-story commit",
+			policy:     FailureReset,
+			wantReset:  []string{"base"},
+			wantStatus: status.StatusBacklog,
+			wantWork:   "discarded, reset to pre-story commit base (commits made during the story remain reachable from partial)",
+		},
+		{
+			name:       "unknown policy keeps the work",
+			policy:     "shred",
+			wantStatus: status.StatusReadyForDev,
+			wantWork:   `kept in the working tree (unknown failure policy "shred")`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			story := &storyState{status: status.StatusBacklog}
+			ws := &MockWorktree{HeadCommit: "base", Dirty: tt.dirty}
+			checkpoints := &MockCheckpointStore{}
+
+			executor := NewExecutor(failOn("dev-story", ws), story, story)
+			executor.SetFailurePolicy(tt.policy, ws)
+			executor.SetCheckpoints(checkpoints)
+			err := executor.Execute(context.Background(), "6-1-story")
+
+			require.Error(t, err)
+			assert.Equal(t, tt.wantStashes, ws.Stashes)
+			assert.Equal(t, tt.wantReset, ws.ResetTo)
+			assert.Equal(t, tt.wantStatus, story.status)
+			assert.Equal(t, tt.wantWork, executor.PartialWork())
+
+			require.NotNil(t, checkpoints.Saved)
+			assert.Equal(t, state.State{
+				StoryKey:    "6-1-story",
+				StepIndex:   1,
+				TotalSteps:  4,
+				StartStatus: "backlog",
+				BaseCommit:  "base",
+				PartialWork: tt.wantWork,
+			}, *checkpoints.Saved)
+		})
+	}
+}
+
+func TestExecute_FailurePolicyResumeKeepsBaseCommit(t *testing.T) {
+	story := &storyState{status: status.StatusInProgress}
+	ws := &MockWorktree{HeadCommit: "second-attempt"}
+	checkpoints := &MockCheckpointStore{Saved: &state.State{StoryKey: "6-1-story", BaseCommit: "first-attempt"}}
+
+	executor := NewExecutor(failOn("dev-story", ws), story, story)
+	executor.SetFailurePolicy(FailureReset, ws)
+	executor.SetCheckpoints(checkpoints)
+	err := executor.Execute(context.Background(), "6-1-story")
+
+	require.Error(t, err)
+	assert.Equal(t, []string{"first-attempt"}, ws.ResetTo)
+	assert.Equal(t, "first-attempt", checkpoints.Saved.BaseCommit)
+}
+
+func TestExecute_FailurePolicyResetNeedsBaseCommit(t *testing.T) {
+	runner := &MockWorkflowRunner{}
+	story := &storyState{status: status.StatusReview}
+
+	executor := NewExecutor(runner, story, story)
+	executor.SetFailurePolicy(FailureReset, &MockWorktree{})
+	err := executor.Execute(context.Background(), "6-1-story")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "recording pre-story commit")
+	assert.Empty(t, runner.Calls)
+}
+
+func TestExecute_CheckpointClearedOnSuccess(t *testing.T) {
+	story := &storyState{status: status.StatusReview}
+	checkpoints := &MockCheckpointStore{Saved: &state.State{StoryKey: "6-1-story"}}
+
+	executor := NewExecutor(&MockWorkflowRunner{}, story, story)
+	executor.SetCheckpoints(checkpoints)
+	require.NoError(t, executor.Execute(context.Background(), "6-1-story"))
+
+	assert.Nil(t, checkpoints.Saved)
+	assert.Empty(t, executor.PartialWork())
+
+	// Another story's checkpoint is left alone
+	checkpoints.Saved = &state.State{StoryKey: "6-2-other"}
+	story.status = status.StatusReview
+	require.NoError(t, executor.Execute(context.Background(), "6-1-story"))
+	assert.Equal(t, "6-2-other", checkpoints.Saved.StoryKey)
+}
@@ -0,0 +1,108 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+)
+
+// HookStage identifies when hooks run relative to a workflow or story.
+type HookStage string
+
+const (
+	// HookPre runs before a workflow or story starts.
+	HookPre HookStage = "pre"
+
+	// HookPost runs after a workflow exits successfully or a story is done.
+	HookPost HookStage = "post"
+
+	// HookOnFailure runs after a workflow step or story fails.
+	HookOnFailure HookStage = "on_failure"
+)
+
+// HookEvent describes the point in the lifecycle hooks run at.
+type HookEvent struct {
+	// Stage is when the hooks run.
+	Stage HookStage
+
+	// StoryKey is the story being processed.
+	StoryKey string
+
+	// Workflow is the workflow the hooks run around, or empty for hooks
+	// around the whole story lifecycle.
+	Workflow string
+
+	// ExitCode is the workflow's exit code. Zero before a workflow has run.
+	ExitCode int
+
+	// Err is the failure for on_failure hooks.
+	Err error
+
+	// TranscriptPath is the transcript of the last Claude session, if known
+	// (see [TranscriptRunner]).
+	TranscriptPath string
+}
+
+// HookRunner runs the hooks configured for a lifecycle event.
+//
+// RunHooks returns an error when a hook failed in a way that should stop the
+// lifecycle. Errors from on_failure hooks are ignored.
+// The [hook.Runner] type implements this interface.
+type HookRunner interface {
+	RunHooks(ctx context.Context, event HookEvent) error
+}
+
+// TranscriptRunner is an optional extension of [WorkflowRunner].
+//
+// Runners that implement it, such as [workflow.Runner], report the transcript
+// file of the last workflow's Claude session so hooks can read it.
+type TranscriptRunner interface {
+	LastTranscriptPath() string
+}
+
+// SetHooks configures an optional [HookRunner].
+//
+// When set, hooks run around the whole story lifecycle (with an empty
+// [HookEvent.Workflow]) and around each workflow step: pre hooks before the
+// workflow, post hooks after it exits successfully and before verification,
+// and on_failure hooks when the step fails.
+func (e *Executor) SetHooks(h HookRunner) {
+	e.hooks = h
+}
+
+// runHooks runs the pre or post hooks for an event, filling in the
+// transcript path. A failing hook is returned as an error.
+func (e *Executor) runHooks(ctx context.Context, event HookEvent) error {
+	if e.hooks == nil {
+		return nil
+	}
+	if r, ok := e.runner.(TranscriptRunner); ok {
+		event.TranscriptPath = r.LastTranscriptPath()
+	}
+
+	err := e.hooks.RunHooks(ctx, event)
+	if err == nil {
+		return nil
+	}
+
+	subject := event.Workflow
+	if subject == "" {
+		subject = "story " + event.StoryKey
+	}
+	if event.Stage == HookPre {
+		return fmt.Errorf("pre hook failed before %s: %w", subject, err)
+	}
+	return fmt.Errorf("post hook failed after %s: %w", subject, err)
+}
+
+// runFailureHooks runs the on_failure hooks after a step (or, with an empty
+// workflow, the story) failed with err. They cannot change the outcome.
+func (e *Executor) runFailureHooks(ctx context.Context, storyKey, workflow string, err error) {
+	if e.hooks == nil {
+		return
+	}
+	event := HookEvent{Stage: HookOnFailure, StoryKey: storyKey, Workflow: workflow, ExitCode: e.lastExitCode, Err: err}
+	if r, ok := e.runner.(TranscriptRunner); ok {
+		event.TranscriptPath = r.LastTranscriptPath()
+	}
+	e.hooks.RunHooks(ctx, event)
+}
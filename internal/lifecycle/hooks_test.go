@@ -0,0 +1,193 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bmaduum/internal/status"
+)
+
+// MockHookRunner implements HookRunner for testing.
+type MockHookRunner struct {
+	// Events records every hook event.
+	Events []HookEvent
+	// Fail maps "stage:workflow" to the error RunHooks returns for it.
+	Fail map[string]error
+}
+
+func (m *MockHookRunner) RunHooks(ctx context.Context, event HookEvent) error {
+	m.Events = append(m.Events, event)
+	return m.Fail[fmt.Sprintf("%s:%s", event.Stage, event.Workflow)]
+}
+
+// labels summarizes recorded events as "stage:workflow".
+func (m *MockHookRunner) labels() []string {
+	var labels []string
+	for _, e := range m.Events {
+		labels = append(labels, fmt.Sprintf("%s:%s", e.Stage, e.Workflow))
+	}
+	return labels
+}
+
+// MockTranscriptRunner implements TranscriptRunner for testing.
+type MockTranscriptRunner struct {
+	MockWorkflowRunner
+}
+
+func (m *MockTranscriptRunner) LastTranscriptPath() string {
+	if len(m.Calls) == 0 {
+		return ""
+	}
+	return "/transcripts/" + m.Calls[len(m.Calls)-1].WorkflowName + ".jsonl"
+}
+
+func TestExecute_Hooks(t *testing.T) {
+	runner := &MockTranscriptRunner{}
+	reader := &MockStatusReader{
+		GetStoryStatusFunc: func(storyKey string) (status.Status, error) {
+			return status.StatusReview, nil
+		},
+	}
+	hooks := &MockHookRunner{}
+
+	executor := NewExecutor(runner, reader, &MockStatusWriter{})
+	executor.SetHooks(hooks)
+	err := executor.Execute(context.Background(), "EPIC-1-story")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"pre:",
+		"pre:code-review", "post:code-review",
+		"pre:git-commit", "post:git-commit",
+		"post:",
+	}, hooks.labels())
+	assert.Equal(t, "/transcripts/code-review.jsonl", hooks.Events[2].TranscriptPath)
+	assert.Equal(t, "EPIC-1-story", hooks.Events[5].StoryKey)
+}
+
+func TestExecute_Hooks_Failure(t *testing.T) {
+	tests := []struct {
+		name       string
+		fail       map[string]error
+		failOn     string
+		wantErr    string
+		wantLabels []string
+		wantRuns   int
+	}{
+		{
+			name:    "pre hook stops the step",
+			fail:    map[string]error{"pre:code-review": errors.New("db: exit code 1")},
+			wantErr: "pre hook failed before code-review: db: exit code 1",
+			wantLabels: []string{
+				"pre:", "pre:code-review", "on_failure:code-review", "on_failure:",
+			},
+			wantRuns: 0,
+		},
+		{
+			name:    "post hook fails the step",
+			fail:    map[string]error{"post:code-review": errors.New("fmt: exit code 1")},
+			wantErr: "post hook failed after code-review: fmt: exit code 1",
+			wantLabels: []string{
+				"pre:", "pre:code-review", "post:code-review", "on_failure:code-review", "on_failure:",
+			},
+			wantRuns: 1,
+		},
+		{
+			name:    "story pre hook stops the story",
+			fail:    map[string]error{"pre:": errors.New("setup: exit code 1")},
+			wantErr: "pre hook failed before story EPIC-1-story: setup: exit code 1",
+			wantLabels: []string{
+				"pre:", "on_failure:",
+			},
+			wantRuns: 0,
+		},
+		{
+			name:    "workflow failure runs on_failure hooks",
+			failOn:  "code-review",
+			wantErr: "workflow failed: code-review returned exit code 2",
+			wantLabels: []string{
+				"pre:", "pre:code-review", "on_failure:code-review", "on_failure:",
+			},
+			wantRuns: 1,
+		},
+		{
+			name: "failing on_failure hooks are ignored",
+			fail: map[string]error{
+				"on_failure:code-review": errors.New("notify: exit code 1"),
+				"on_failure:":            errors.New("notify: exit code 1"),
+			},
+			failOn:  "code-review",
+			wantErr: "workflow failed: code-review returned exit code 2",
+			wantLabels: []string{
+				"pre:", "pre:code-review", "on_failure:code-review", "on_failure:",
+			},
+			wantRuns: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &MockWorkflowRunner{
+				RunSingleFunc: func(ctx context.Context, workflowName, storyKey string) int {
+					if workflowName == tt.failOn {
+						return 2
+					}
+					return 0
+				},
+			}
+			reader := &MockStatusReader{
+				GetStoryStatusFunc: func(storyKey string) (status.Status, error) {
+					return status.StatusReview, nil
+				},
+			}
+			writer := &MockStatusWriter{}
+			hooks := &MockHookRunner{Fail: tt.fail}
+
+			executor := NewExecutor(runner, reader, writer)
+			executor.SetHooks(hooks)
+			err := executor.Execute(context.Background(), "EPIC-1-story")
+
+			require.Error(t, err)
+			assert.Equal(t, tt.wantErr, err.Error())
+			assert.Equal(t, tt.wantLabels, hooks.labels())
+			assert.Len(t, runner.Calls, tt.wantRuns)
+			assert.Empty(t, writer.Calls)
+
+			last := hooks.Events[len(hooks.Events)-1]
+			if last.Stage == HookOnFailure {
+				assert.Equal(t, err, last.Err)
+				if tt.failOn != "" {
+					assert.Equal(t, 2, last.ExitCode)
+				}
+			}
+		})
+	}
+}
+
+func TestExecute_Hooks_StoryPostHookFailure(t *testing.T) {
+	story := &storyState{status: status.StatusReview}
+	hooks := &MockHookRunner{Fail: map[string]error{"post:": errors.New("notify: exit code 1")}}
+	checkpoints := &MockCheckpointStore{}
+
+	executor := NewExecutor(&MockWorkflowRunner{}, story, story)
+	executor.SetHooks(hooks)
+	executor.SetCheckpoints(checkpoints)
+	err := executor.Execute(context.Background(), "6-1-story")
+
+	require.Error(t, err)
+	assert.Equal(t, "post hook failed after story 6-1-story: notify: exit code 1", err.Error())
+	assert.Equal(t, []string{
+		"pre:",
+		"pre:code-review", "post:code-review",
+		"pre:git-commit", "post:git-commit",
+		"post:", "on_failure:",
+	}, hooks.labels())
+	require.NotNil(t, checkpoints.Saved, "the failure is checkpointed like a failed step")
+	assert.Equal(t, "6-1-story", checkpoints.Saved.StoryKey)
+	assert.Equal(t, "kept in the working tree", executor.PartialWork())
+}
@@ -0,0 +1,81 @@
+package lifecycle
+
+import (
+	"regexp"
+	"strings"
+
+	"bmaduum/internal/status"
+)
+
+// DefaultMaxReviewCycles is how many dev-story → code-review cycles a story
+// may go through when [Executor.SetMaxReviewCycles] is not called.
+const DefaultMaxReviewCycles = 3
+
+// ReviewOutcome is the result of a code-review step.
+type ReviewOutcome int
+
+const (
+	// ReviewUnknown means the review reported no outcome and left the status alone.
+	ReviewUnknown ReviewOutcome = iota
+
+	// ReviewApproved means the review came back clean.
+	ReviewApproved
+
+	// ReviewChangesRequested means the review sent the story back with action items.
+	ReviewChangesRequested
+)
+
+// String returns a readable name for the outcome.
+func (o ReviewOutcome) String() string {
+	switch o {
+	case ReviewApproved:
+		return "approved"
+	case ReviewChangesRequested:
+		return "changes requested"
+	default:
+		return "unknown"
+	}
+}
+
+// reviewMarkerPattern matches the structured outcome line a review may end
+// its final message with, e.g. "REVIEW_RESULT: CHANGES_REQUESTED".
+var reviewMarkerPattern = regexp.MustCompile(`(?mi)^[^A-Za-z\n]*REVIEW[_ ]RESULT:\s*(APPROVED|CHANGES[_ ]REQUESTED)\b`)
+
+// ParseReviewMarker returns the outcome reported by the last review marker
+// in text, or [ReviewUnknown] if there is none.
+func ParseReviewMarker(text string) ReviewOutcome {
+	matches := reviewMarkerPattern.FindAllStringSubmatch(text, -1)
+	if len(matches) == 0 {
+		return ReviewUnknown
+	}
+
+	switch strings.ToUpper(matches[len(matches)-1][1]) {
+	case "APPROVED":
+		return ReviewApproved
+	default:
+		return ReviewChangesRequested
+	}
+}
+
+// reviewOutcome determines how a code-review step ended.
+//
+// A marker in the runner's final message takes precedence. Otherwise a review
+// that moved the story from before back to in-progress or ready-for-dev, as
+// BMAD's code-review does when it leaves action items, requested changes.
+func (e *Executor) reviewOutcome(storyKey string, before status.Status) (ReviewOutcome, error) {
+	if r, ok := e.runner.(MessageRunner); ok {
+		if outcome := ParseReviewMarker(r.LastMessage()); outcome != ReviewUnknown {
+			return outcome, nil
+		}
+	}
+
+	after, err := e.statusReader.GetStoryStatus(storyKey)
+	if err != nil {
+		return ReviewUnknown, err
+	}
+	if after != before && (after == status.StatusInProgress || after == status.StatusReadyForDev) {
+		return ReviewChangesRequested, nil
+	}
+
+	return ReviewUnknown, nil
+}
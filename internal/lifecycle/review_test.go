@@ -0,0 +1,203 @@
+package lifecycle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bmaduum/internal/router"
+	"bmaduum/internal/status"
+)
+
+func TestParseReviewMarker(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want ReviewOutcome
+	}{
+		{name: "no marker", text: "Looks good to me.", want: ReviewUnknown},
+		{name: "approved", text: "Done.\nREVIEW_RESULT: APPROVED", want: ReviewApproved},
+		{name: "changes requested", text: "REVIEW_RESULT: CHANGES_REQUESTED\n", want: ReviewChangesRequested},
+		{name: "markdown and spaces", text: "**Review result: changes requested**", want: ReviewChangesRequested},
+		{name: "last marker wins", text: "REVIEW_RESULT: CHANGES_REQUESTED\nFixed.\nREVIEW_RESULT: APPROVED", want: ReviewApproved},
+		{name: "marker must start the line", text: "Reply with REVIEW_RESULT: APPROVED when done", want: ReviewUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseReviewMarker(tt.text))
+		})
+	}
+}
+
+// storyState is a single-story status store shared by a reader and writer.
+type storyState struct {
+	status  status.Status
+	updates []status.Status
+}
+
+func (s *storyState) GetStoryStatus(storyKey string) (status.Status, error) {
+	return s.status, nil
+}
+
+func (s *storyState) UpdateStatus(storyKey string, newStatus status.Status) error {
+	s.status = newStatus
+	s.updates = append(s.updates, newStatus)
+	return nil
+}
+
+// MockMessageRunner implements MessageRunner, returning a final message per
+// code-review run.
+type MockMessageRunner struct {
+	MockWorkflowRunner
+	// ReviewMessages are the final messages of successive code-review runs.
+	ReviewMessages []string
+	last           string
+}
+
+func (m *MockMessageRunner) RunSingle(ctx context.Context, workflowName, storyKey string) int {
+	m.last = ""
+	if workflowName == "code-review" && len(m.ReviewMessages) > 0 {
+		m.last, m.ReviewMessages = m.ReviewMessages[0], m.ReviewMessages[1:]
+	}
+	return m.MockWorkflowRunner.RunSingle(ctx, workflowName, storyKey)
+}
+
+func (m *MockMessageRunner) LastMessage() string {
+	return m.last
+}
+
+func executedWorkflows(runner *MockWorkflowRunner) []string {
+	var workflows []string
+	for _, call := range runner.Calls {
+		workflows = append(workflows, call.WorkflowName)
+	}
+	return workflows
+}
+
+func TestExecute_ReviewLoop_Marker(t *testing.T) {
+	state := &storyState{status: status.StatusReview}
+	runner := &MockMessageRunner{ReviewMessages: []string{
+		"Found 2 issues.\nREVIEW_RESULT: CHANGES_REQUESTED",
+		"REVIEW_RESULT: APPROVED",
+	}}
+
+	executor := NewExecutor(runner, state, state)
+	err := executor.Execute(context.Background(), "EPIC-1-story")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"code-review", "dev-story", "code-review", "git-commit"}, executedWorkflows(&runner.MockWorkflowRunner))
+	assert.Equal(t, []status.Status{
+		status.StatusInProgress, // review sent the story back
+		status.StatusReview,     // dev-story
+		status.StatusDone,       // code-review
+		status.StatusDone,       // git-commit
+	}, state.updates)
+
+	results := executor.Results()
+	require.Len(t, results, 4)
+	assert.Equal(t, StepResult{Workflow: "code-review", Cycle: 1, Duration: results[0].Duration, Success: true}, results[0])
+	assert.Equal(t, 2, results[1].Cycle)
+	assert.Equal(t, 2, results[2].Cycle)
+	assert.Equal(t, 0, results[3].Cycle)
+}
+
+func TestExecute_ReviewLoop_StatusChange(t *testing.T) {
+	state := &storyState{status: status.StatusReadyForDev}
+	runner := &MockWorkflowRunner{}
+	reviews := 0
+	runner.RunSingleFunc = func(ctx context.Context, workflowName, storyKey string) int {
+		// The first review moves the story back itself, as BMAD's code-review does
+		if workflowName == "code-review" {
+			reviews++
+			if reviews == 1 {
+				state.status = status.StatusInProgress
+			}
+		}
+		return 0
+	}
+
+	progress := []int{}
+	executor := NewExecutor(runner, state, state)
+	executor.SetProgressCallback(func(stepIndex, totalSteps int, workflow string) {
+		progress = append(progress, totalSteps)
+	})
+	err := executor.Execute(context.Background(), "EPIC-1-story")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dev-story", "code-review", "dev-story", "code-review", "git-commit"}, executedWorkflows(runner))
+	// The review already moved the story to in-progress, so it is not written again
+	assert.Equal(t, []status.Status{
+		status.StatusReview,
+		status.StatusReview,
+		status.StatusDone,
+		status.StatusDone,
+	}, state.updates)
+	assert.Equal(t, []int{3, 3, 5, 5, 5}, progress)
+}
+
+func TestExecute_ReviewLoop_MaxCycles(t *testing.T) {
+	state := &storyState{status: status.StatusReview}
+	runner := &MockMessageRunner{ReviewMessages: []string{
+		"REVIEW_RESULT: CHANGES_REQUESTED",
+		"REVIEW_RESULT: CHANGES_REQUESTED",
+		"REVIEW_RESULT: APPROVED",
+	}}
+
+	executor := NewExecutor(runner, state, state)
+	executor.SetMaxReviewCycles(2)
+	err := executor.Execute(context.Background(), "EPIC-1-story")
+
+	require.Error(t, err)
+	assert.Equal(t, "code review still requests changes after 2 review cycle(s)", err.Error())
+	assert.Equal(t, []string{"code-review", "dev-story", "code-review"}, executedWorkflows(&runner.MockWorkflowRunner))
+	assert.Equal(t, status.StatusInProgress, state.status)
+
+	results := executor.Results()
+	require.Len(t, results, 3)
+	assert.False(t, results[2].Success)
+}
+
+func TestExecute_ReviewLoop_SingleCycle(t *testing.T) {
+	state := &storyState{status: status.StatusReview}
+	runner := &MockMessageRunner{ReviewMessages: []string{"REVIEW_RESULT: CHANGES_REQUESTED"}}
+
+	executor := NewExecutor(runner, state, state)
+	executor.SetMaxReviewCycles(0)
+	err := executor.Execute(context.Background(), "EPIC-1-story")
+
+	require.Error(t, err)
+	assert.Equal(t, "code review still requests changes after 1 review cycle(s)", err.Error())
+	assert.Equal(t, []status.Status{status.StatusInProgress}, state.updates)
+}
+
+func TestExecute_CustomLifecycle(t *testing.T) {
+	state := &storyState{status: status.StatusReadyForDev}
+	runner := &MockMessageRunner{ReviewMessages: []string{
+		"REVIEW_RESULT: CHANGES_REQUESTED",
+		"REVIEW_RESULT: APPROVED",
+	}}
+
+	executor := NewExecutor(runner, state, state)
+	executor.SetLifecycle([]router.LifecycleStep{
+		{Workflow: "create-story", NextStatus: status.StatusReadyForDev},
+		{Workflow: "dev-story", NextStatus: status.StatusReview},
+		{Workflow: "security-scan"},
+		{Workflow: "code-review", NextStatus: status.StatusDone},
+		{Workflow: "git-commit", NextStatus: status.StatusDone},
+	})
+
+	steps, err := executor.GetSteps("EPIC-1-story")
+	require.NoError(t, err)
+	assert.Equal(t, router.LifecycleStep{Workflow: "security-scan", NextStatus: status.StatusReview}, steps[1])
+
+	require.NoError(t, executor.Execute(context.Background(), "EPIC-1-story"))
+	assert.Equal(t, []string{
+		"dev-story", "security-scan", "code-review",
+		"dev-story", "security-scan", "code-review", // rework reruns the custom step
+		"git-commit",
+	}, executedWorkflows(&runner.MockWorkflowRunner))
+	assert.Equal(t, status.StatusDone, state.status)
+}
@@ -151,7 +151,7 @@ func (p *DefaultPrinter) CycleHeader(storyKey string) {
 	p.cycle.CycleHeader(storyKey)
 }
 
-// CycleSummary prints the summary after a successful cycle.
+// CycleSummary prints the steps a cycle ran, marking a failed step.
 func (p *DefaultPrinter) CycleSummary(storyKey string, steps []core.StepResult, totalDuration time.Duration) {
 	// Convert core.StepResult to render.StepResult
 	renderSteps := make([]render.StepResult, len(steps))
@@ -216,6 +216,23 @@ func TestDefaultPrinter_CycleSummary(t *testing.T) {
 	assert.Contains(t, output, "dev-story")
 }
 
+func TestDefaultPrinter_CycleSummary_Failed(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewPrinterWithWriter(&buf)
+
+	steps := []core.StepResult{
+		{Name: "dev-story", Duration: 30 * time.Second, Success: true},
+		{Name: "code-review", Duration: 10 * time.Second, Success: false},
+	}
+
+	p.CycleSummary("test-story", steps, 40*time.Second)
+
+	output := buf.String()
+	assert.Contains(t, output, "CYCLE FAILED")
+	assert.NotContains(t, output, "CYCLE COMPLETE")
+	assert.Regexp(t, `code-review\s+10s ✗`, output)
+}
+
 func TestDefaultPrinter_CycleFailed(t *testing.T) {
 	var buf bytes.Buffer
 	p := NewPrinterWithWriter(&buf)
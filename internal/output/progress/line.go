@@ -222,6 +222,15 @@ func (l *Line) SetOperation(operation string) {
 	l.render()
 }
 
+// SetProfile sets the active configuration profile shown in the status bar.
+// It takes effect from the next update.
+func (l *Line) SetProfile(profile string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.state.Profile = profile
+}
+
 // RecordFirstResponse records when the first response arrives (for thinking time).
 // Call this when text or tool use is first received. Safe to call multiple times.
 func (l *Line) RecordFirstResponse() {
@@ -404,6 +413,7 @@ func (l *Line) render() {
 		StoryKey:      l.state.StoryKey,
 		Model:         l.state.Model,
 		Operation:     l.state.Operation,
+		Profile:       l.state.Profile,
 		StepStartTime: l.state.StepStartTime,
 		StartTime:     l.state.StartTime,
 		Width:         width,
@@ -3,7 +3,7 @@
 // This package manages a fixed two-line status area at the bottom of the terminal
 // with scrolling output above. The status area consists of:
 //   - Activity line (second-to-last): Spinner + activity verb + timer + token count
-//   - Status bar (last row): Operation + step info + story key + model + total timer
+//   - Status bar (last row): Operation + step info + story key + model + profile + total timer
 package progress
 
 import "time"
@@ -21,6 +21,9 @@ type State struct {
 	// Operation context (e.g., "Epic 6", "Story 2/3")
 	Operation string
 
+	// Profile is the active configuration profile, if any
+	Profile string
+
 	// Current activity
 	CurrentTool string
 
@@ -66,6 +69,7 @@ type StatusState struct {
 	StoryKey      string
 	Model         string
 	Operation     string
+	Profile       string
 	StepStartTime time.Time
 	StartTime     time.Time
 	Width         int
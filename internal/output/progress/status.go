@@ -66,7 +66,7 @@ func (s *StatusBar) Render(width int, state StatusState) error {
 }
 
 // buildLine constructs the status bar content with clear visual hierarchy.
-// Format: ▸ Epic 6 │ Story 3/8 · 6-3-api │ Step 2/5 dev-story │ opus-4-5 │ 12:34
+// Format: ▸ Epic 6 │ Story 3/8 · 6-3-api │ Step 2/5 dev-story │ opus-4-5 │ profile fast │ 12:34
 func (s *StatusBar) buildLine(state StatusState) string {
 	available := state.Width - 2 // Leave some margin
 	sep := " │ "                 // Box drawing separator for cleaner look
@@ -94,8 +94,14 @@ func (s *StatusBar) buildLine(state StatusState) string {
 	// Operation (e.g., "Epic 6", "Story 2/3")
 	operation := state.Operation
 
+	// Profile
+	profile := ""
+	if state.Profile != "" {
+		profile = "profile " + state.Profile
+	}
+
 	// Build parts based on available width
-	// Priority order for narrow terminals: timer > operation > step > story > model
+	// Priority order for narrow terminals: timer > operation > step > story > model > profile
 	var parts []string
 	usedWidth := 0
 
@@ -152,6 +158,15 @@ func (s *StatusBar) buildLine(state StatusState) string {
 		needed := displayWidth(model) + displayWidth(sep)
 		if usedWidth+needed <= available {
 			parts = append(parts, model)
+			usedWidth += needed
+		}
+	}
+
+	// Add profile
+	if profile != "" {
+		needed := displayWidth(profile) + displayWidth(sep)
+		if usedWidth+needed <= available {
+			parts = append(parts, profile)
 		}
 	}
 
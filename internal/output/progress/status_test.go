@@ -1,6 +1,9 @@
 package progress
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestShortenModel(t *testing.T) {
 	tests := []struct {
@@ -59,3 +62,26 @@ func TestShortenModel(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildLine_Profile(t *testing.T) {
+	bar := NewStatusBar(nil)
+	state := StatusState{
+		Total:    4,
+		Step:     2,
+		StepName: "dev-story",
+		StoryKey: "6-3-api",
+		Model:    "claude-opus-4-5",
+		Profile:  "fast",
+		Width:    120,
+	}
+
+	if got, want := bar.buildLine(state), "▸ 6-3-api │ Step 2/4 dev-story │ opus-4-5 │ profile fast"; got != want {
+		t.Errorf("buildLine() = %q, want %q", got, want)
+	}
+
+	// The profile is dropped first on narrow terminals
+	state.Width = 45
+	if got := bar.buildLine(state); strings.Contains(got, "profile") {
+		t.Errorf("buildLine() = %q, want no profile at width 45", got)
+	}
+}
@@ -60,27 +60,39 @@ func (r *CycleRenderer) CycleHeader(storyKey string) {
 	r.writer.Writeln(r.styles.RenderHeader(BoxBottom(width)))
 }
 
-// CycleSummary prints the summary after a successful cycle.
+// CycleSummary prints the steps a cycle ran. When a step failed, the summary
+// is rendered as a failure and the failed step is marked.
 func (r *CycleRenderer) CycleSummary(storyKey string, steps []StepResult, totalDuration time.Duration) {
 	width := r.width.TerminalWidth()
 	if width > 70 {
 		width = 70
 	}
 
+	render, title := r.styles.RenderSuccess, IconSuccess+" CYCLE COMPLETE"
+	for _, step := range steps {
+		if !step.Success {
+			render, title = r.styles.RenderError, IconError+" CYCLE FAILED"
+			break
+		}
+	}
+
 	r.writer.Writeln("")
-	r.writer.Writeln(r.styles.RenderSuccess(BoxTop(width)))
-	r.writer.Writeln(r.styles.RenderSuccess(BoxLine(IconSuccess+" CYCLE COMPLETE", width)))
-	r.writer.Writeln(r.styles.RenderSuccess(BoxLine("Story: "+storyKey, width)))
-	r.writer.Writeln(r.styles.RenderSuccess("├" + strings.Repeat("─", width-2) + "┤"))
+	r.writer.Writeln(render(BoxTop(width)))
+	r.writer.Writeln(render(BoxLine(title, width)))
+	r.writer.Writeln(render(BoxLine("Story: "+storyKey, width)))
+	r.writer.Writeln(render("├" + strings.Repeat("─", width-2) + "┤"))
 
 	for i, step := range steps {
 		line := fmt.Sprintf("[%d] %-15s %s", i+1, step.Name, step.Duration.Round(time.Millisecond))
-		r.writer.Writeln(r.styles.RenderSuccess(BoxLine(line, width)))
+		if !step.Success {
+			line += " " + IconError
+		}
+		r.writer.Writeln(render(BoxLine(line, width)))
 	}
 
-	r.writer.Writeln(r.styles.RenderSuccess("├" + strings.Repeat("─", width-2) + "┤"))
-	r.writer.Writeln(r.styles.RenderSuccess(BoxLine(fmt.Sprintf("Total: %s", totalDuration.Round(time.Millisecond)), width)))
-	r.writer.Writeln(r.styles.RenderSuccess(BoxBottom(width)))
+	r.writer.Writeln(render("├" + strings.Repeat("─", width-2) + "┤"))
+	r.writer.Writeln(render(BoxLine(fmt.Sprintf("Total: %s", totalDuration.Round(time.Millisecond)), width)))
+	r.writer.Writeln(render(BoxBottom(width)))
 }
 
 // CycleFailed prints failure information when a cycle fails.
@@ -0,0 +1,164 @@
+// Package preflight checks that it is safe to let the agent loose on a
+// project before story and epic runs start.
+//
+// The agent runs with --dangerously-skip-permissions directly on the working
+// tree, so [Checker] makes sure its changes can be told apart from the
+// user's and reverted:
+//   - the agent binary (the claude binary by default) can be found
+//   - the project is a git working tree with no rebase, merge, cherry-pick
+//     or revert in progress
+//   - the checked out branch is not protected, unless git.branch_per_story
+//     moves every story onto its own branch
+//   - the working tree is clean, apart from the sprint status file, its
+//     sidecar files and the checkpoint of a failed story
+package preflight
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"bmaduum/internal/agent"
+	"bmaduum/internal/config"
+	"bmaduum/internal/git"
+	"bmaduum/internal/state"
+	"bmaduum/internal/status"
+)
+
+// maxListedFiles caps how many changed files a problem message lists.
+const maxListedFiles = 5
+
+// Checker runs the preflight checks for a project.
+//
+// Use [New] to create an instance.
+type Checker struct {
+	dir               string
+	statusPath        string
+	protectedBranches []string
+	agentBinary       string
+	binarySetting     string
+
+	// lookPath resolves the agent binary.
+	lookPath func(file string) (string, error)
+}
+
+// New creates a [Checker] for the project in the current working directory,
+// using the protected branches and agent binary from cfg. Changes to the
+// sprint status file at statusPath are allowed. Protected branches are not
+// checked when cfg.Git.BranchPerStory is set.
+func New(cfg *config.Config, statusPath string) *Checker {
+	c := &Checker{
+		statusPath:        statusPath,
+		protectedBranches: cfg.Git.ProtectedBranches,
+		lookPath:          exec.LookPath,
+	}
+	c.agentBinary, c.binarySetting = agent.Binary(cfg)
+	if cfg.Git.BranchPerStory {
+		c.protectedBranches = nil
+	}
+	return c
+}
+
+// Check runs every check and returns a description of each problem found,
+// or nil if it is safe to start.
+func (c *Checker) Check() []string {
+	var problems []string
+
+	if c.binarySetting != "" {
+		if _, err := c.lookPath(c.agentBinary); err != nil {
+			problems = append(problems, c.binaryProblem())
+		}
+	}
+
+	top, err := git.TopLevel(c.dir)
+	if err != nil {
+		return append(problems, "not a git repository, so the agent's changes could not be reviewed or reverted")
+	}
+
+	if op, err := git.OperationInProgress(c.dir); err != nil {
+		problems = append(problems, fmt.Sprintf("cannot check for git operations in progress: %v", err))
+	} else if op != "" {
+		problems = append(problems, fmt.Sprintf("a git %s is in progress; finish or abort it first", op))
+	}
+
+	if branch, err := git.CurrentBranch(c.dir); err != nil {
+		problems = append(problems, fmt.Sprintf("cannot read the current branch: %v", err))
+	} else if slices.Contains(c.protectedBranches, branch) {
+		problems = append(problems, fmt.Sprintf("on protected branch %s; switch to a feature branch first", branch))
+	}
+
+	if changed, err := c.unexpectedChanges(top); err != nil {
+		problems = append(problems, fmt.Sprintf("cannot read the working tree status: %v", err))
+	} else if len(changed) > 0 {
+		problems = append(problems, "working tree has uncommitted changes: "+listFiles(changed))
+	}
+
+	return problems
+}
+
+// binaryProblem describes the agent binary that cannot be found.
+func (c *Checker) binaryProblem() string {
+	if c.binarySetting == "claude.binary_path" {
+		return fmt.Sprintf("claude binary %q cannot be found (set claude.binary_path or BMADUUM_CLAUDE_PATH)", c.agentBinary)
+	}
+	return fmt.Sprintf("agent binary %q cannot be found (set %s)", c.agentBinary, c.binarySetting)
+}
+
+// unexpectedChanges returns the changed files in the working tree rooted at
+// top, other than the sprint status file and its sidecars.
+func (c *Checker) unexpectedChanges(top string) ([]string, error) {
+	changed, err := git.ChangedFiles(c.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := c.allowedFiles(top)
+	var unexpected []string
+	for _, file := range changed {
+		if !slices.Contains(allowed, filepath.ToSlash(file)) {
+			unexpected = append(unexpected, file)
+		}
+	}
+
+	return unexpected, nil
+}
+
+// allowedFiles returns the sprint status file, its history log and its
+// lock file, and the checkpoint state file of a failed story, as
+// slash-separated paths relative to top.
+func (c *Checker) allowedFiles(top string) []string {
+	paths := []string{filepath.Join(c.dir, state.StateFileName)}
+	if c.statusPath != "" {
+		paths = append(paths, c.statusPath, status.HistoryPath(c.statusPath), c.statusPath+".lock")
+	}
+
+	// git reports the top level with symlinks resolved
+	if resolved, err := filepath.EvalSymlinks(top); err == nil {
+		top = resolved
+	}
+
+	var allowed []string
+	for _, path := range paths {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			continue
+		}
+		if dir, err := filepath.EvalSymlinks(filepath.Dir(abs)); err == nil {
+			abs = filepath.Join(dir, filepath.Base(abs))
+		}
+		if rel, err := filepath.Rel(top, abs); err == nil {
+			allowed = append(allowed, filepath.ToSlash(rel))
+		}
+	}
+	return allowed
+}
+
+// listFiles formats changed files for a problem message.
+func listFiles(files []string) string {
+	if len(files) <= maxListedFiles {
+		return strings.Join(files, ", ")
+	}
+	return fmt.Sprintf("%s (and %d more)", strings.Join(files[:maxListedFiles], ", "), len(files)-maxListedFiles)
+}
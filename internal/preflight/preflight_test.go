@@ -0,0 +1,172 @@
+package preflight
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bmaduum/internal/config"
+)
+
+const statusFile = "_bmad-output/implementation-artifacts/sprint-status.yaml"
+
+// initRepo creates a git repository on branch "feature" with a committed
+// sprint status file.
+func initRepo(t *testing.T) string {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	gitCmd(t, dir, "init", "-q")
+	gitCmd(t, dir, "checkout", "-q", "-b", "feature")
+	gitCmd(t, dir, "config", "user.email", "test@example.com")
+	gitCmd(t, dir, "config", "user.name", "Test")
+	writeFile(t, filepath.Join(dir, statusFile), "development_status:\n  6-1-first: backlog\n")
+	gitCmd(t, dir, "add", "-A")
+	gitCmd(t, dir, "commit", "-q", "-m", "initial")
+
+	return dir
+}
+
+// gitCmd runs git in dir, failing the test on error.
+func gitCmd(t *testing.T, dir string, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+// newTestChecker creates a Checker for dir whose claude binary resolves.
+func newTestChecker(dir string) *Checker {
+	c := New(config.DefaultConfig(), filepath.Join(dir, statusFile))
+	c.dir = dir
+	c.lookPath = func(file string) (string, error) { return "/usr/bin/" + file, nil }
+	return c
+}
+
+func TestCheck_Clean(t *testing.T) {
+	dir := initRepo(t)
+
+	assert.Empty(t, newTestChecker(dir).Check())
+}
+
+func TestCheck_SprintStatusChangesAllowed(t *testing.T) {
+	dir := initRepo(t)
+	statusPath := filepath.Join(dir, statusFile)
+	writeFile(t, statusPath, "development_status:\n  6-1-first: done\n")
+	writeFile(t, statusPath+".lock", "")
+	writeFile(t, filepath.Join(filepath.Dir(statusPath), "sprint-status.history.jsonl"), "{}\n")
+	writeFile(t, filepath.Join(dir, ".bmad-state.json"), "{}\n")
+
+	assert.Empty(t, newTestChecker(dir).Check())
+}
+
+func TestCheck_DirtyWorkingTree(t *testing.T) {
+	dir := initRepo(t)
+	for _, name := range []string{"a.go", "b.go", "c.go", "d.go", "e.go", "f.go", "g.go"} {
+		writeFile(t, filepath.Join(dir, name), "package main\n")
+	}
+
+	problems := newTestChecker(dir).Check()
+
+	assert.Equal(t, []string{"working tree has uncommitted changes: a.go, b.go, c.go, d.go, e.go (and 2 more)"}, problems)
+}
+
+func TestCheck_ProtectedBranch(t *testing.T) {
+	dir := initRepo(t)
+	gitCmd(t, dir, "checkout", "-q", "-b", "main")
+
+	problems := newTestChecker(dir).Check()
+
+	assert.Equal(t, []string{"on protected branch main; switch to a feature branch first"}, problems)
+}
+
+func TestCheck_ProtectedBranchWithBranchPerStory(t *testing.T) {
+	dir := initRepo(t)
+	gitCmd(t, dir, "checkout", "-q", "-b", "main")
+
+	cfg := config.DefaultConfig()
+	cfg.Git.BranchPerStory = true
+	c := New(cfg, filepath.Join(dir, statusFile))
+	c.dir = dir
+	c.lookPath = func(file string) (string, error) { return "/usr/bin/" + file, nil }
+
+	assert.Empty(t, c.Check())
+}
+
+func TestCheck_MergeInProgress(t *testing.T) {
+	dir := initRepo(t)
+	gitCmd(t, dir, "checkout", "-q", "-b", "other")
+	writeFile(t, filepath.Join(dir, "README.md"), "other\n")
+	gitCmd(t, dir, "add", "-A")
+	gitCmd(t, dir, "commit", "-q", "-m", "other")
+	gitCmd(t, dir, "checkout", "-q", "feature")
+	writeFile(t, filepath.Join(dir, "README.md"), "feature\n")
+	gitCmd(t, dir, "add", "-A")
+	gitCmd(t, dir, "commit", "-q", "-m", "feature")
+	cmd := exec.Command("git", "merge", "-q", "other")
+	cmd.Dir = dir
+	require.Error(t, cmd.Run())
+
+	problems := newTestChecker(dir).Check()
+
+	require.Len(t, problems, 2)
+	assert.Equal(t, "a git merge is in progress; finish or abort it first", problems[0])
+	assert.Equal(t, "working tree has uncommitted changes: README.md", problems[1])
+}
+
+func TestCheck_ClaudeBinaryMissing(t *testing.T) {
+	dir := initRepo(t)
+	c := newTestChecker(dir)
+	c.lookPath = func(file string) (string, error) { return "", errors.New("not found") }
+
+	problems := c.Check()
+
+	assert.Equal(t, []string{`claude binary "claude" cannot be found (set claude.binary_path or BMADUUM_CLAUDE_PATH)`}, problems)
+}
+
+func TestCheck_AgentBinaryMissing(t *testing.T) {
+	dir := initRepo(t)
+	cfg := config.DefaultConfig()
+	cfg.Agent.Backend = config.AgentBackendCommand
+	cfg.Agent.Command.Path = "my-agent"
+	c := New(cfg, filepath.Join(dir, statusFile))
+	c.dir = dir
+	var looked []string
+	c.lookPath = func(file string) (string, error) {
+		looked = append(looked, file)
+		return "", errors.New("not found")
+	}
+
+	problems := c.Check()
+
+	assert.Equal(t, []string{`agent binary "my-agent" cannot be found (set agent.command.path)`}, problems)
+	assert.Equal(t, []string{"my-agent"}, looked)
+}
+
+func TestCheck_NotARepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	problems := newTestChecker(t.TempDir()).Check()
+
+	assert.Equal(t, []string{"not a git repository, so the agent's changes could not be reviewed or reverted"}, problems)
+}
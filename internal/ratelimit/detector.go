@@ -1,12 +1,13 @@
 // Package ratelimit provides rate limit detection for Claude CLI.
 //
 // The detector parses stderr output from Claude CLI to identify rate limit
-// errors and extract the reset time. This enables automatic retry with
-// intelligent wait times.
+// and overload errors and extract the reset time. This enables automatic
+// retry with intelligent wait times, or switching to a fallback model.
 package ratelimit
 
 import (
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -16,6 +17,11 @@ type ErrorInfo struct {
 	// IsRateLimit is true if this is a rate limit error.
 	IsRateLimit bool
 
+	// IsOverloaded is true if the API reported it is overloaded. Unlike a
+	// rate limit, an overload is not tied to the account and has no reset
+	// time.
+	IsOverloaded bool
+
 	// ResetTime is the time when the rate limit will reset.
 	// Only valid if IsRateLimit is true.
 	ResetTime time.Time
@@ -35,6 +41,14 @@ type Detector struct {
 
 	// resetTimePattern extracts the reset time from rate limit messages.
 	resetTimePattern *regexp.Regexp
+
+	// resetEpochPattern extracts a Unix reset time from rate limit messages.
+	// Example: "Claude AI usage limit reached|1760000000"
+	resetEpochPattern *regexp.Regexp
+
+	// overloadPattern matches API overload errors.
+	// Example: `API Error: 529 {"type":"error","error":{"type":"overloaded_error"}}`
+	overloadPattern *regexp.Regexp
 }
 
 // NewDetector creates a new rate limit detector.
@@ -47,15 +61,23 @@ func NewDetector() *Detector {
 		// Extract time from messages like:
 		// "Your limit will reset at 1pm (Etc/GMT+5)" - captures just the time part
 		resetTimePattern: regexp.MustCompile(`reset at ([^([]+)`),
+
+		resetEpochPattern: regexp.MustCompile(`limit reached\|(\d{9,})`),
+
+		overloadPattern: regexp.MustCompile(`(?i)overloaded_error|API Error: 529`),
 	}
 }
 
 // CheckLine checks a single line of stderr output for rate limit errors.
 //
-// Returns an ErrorInfo with IsRateLimit=true if a rate limit error is detected.
+// Returns an ErrorInfo with IsRateLimit=true if a rate limit error is detected,
+// or IsOverloaded=true for an overload error.
 // The ResetTime field will be populated if the time can be parsed from the message.
 func (d *Detector) CheckLine(line string) ErrorInfo {
 	if !d.rateLimitPattern.MatchString(line) {
+		if d.overloadPattern.MatchString(line) {
+			return ErrorInfo{IsOverloaded: true, RawMessage: line}
+		}
 		return ErrorInfo{IsRateLimit: false}
 	}
 
@@ -65,7 +87,11 @@ func (d *Detector) CheckLine(line string) ErrorInfo {
 	}
 
 	// Try to extract reset time
-	if matches := d.resetTimePattern.FindStringSubmatch(line); len(matches) > 1 {
+	if matches := d.resetEpochPattern.FindStringSubmatch(line); len(matches) > 1 {
+		if sec, err := strconv.ParseInt(matches[1], 10, 64); err == nil {
+			info.ResetTime = time.Unix(sec, 0)
+		}
+	} else if matches := d.resetTimePattern.FindStringSubmatch(line); len(matches) > 1 {
 		// Attempt to parse various time formats
 		resetTime := d.parseResetTime(strings.TrimSpace(matches[1]))
 		if !resetTime.IsZero() {
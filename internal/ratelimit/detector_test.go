@@ -69,6 +69,37 @@ func TestDetector_CheckLine(t *testing.T) {
 	}
 }
 
+func TestDetector_CheckLine_UnixResetTime(t *testing.T) {
+	info := NewDetector().CheckLine("Claude AI usage limit reached|1760000000")
+
+	assert.True(t, info.IsRateLimit)
+	assert.False(t, info.IsOverloaded)
+	assert.Equal(t, time.Unix(1760000000, 0), info.ResetTime)
+}
+
+func TestDetector_CheckLine_Overloaded(t *testing.T) {
+	d := NewDetector()
+
+	for _, line := range []string{
+		`API Error: 529 {"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`,
+		`{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`,
+	} {
+		info := d.CheckLine(line)
+		assert.True(t, info.IsOverloaded, line)
+		assert.False(t, info.IsRateLimit, line)
+		assert.Equal(t, line, info.RawMessage)
+	}
+
+	for _, line := range []string{
+		"Error: connection failed",
+		"The API is overloaded, try again later",
+		"HTTP 529 from upstream",
+		"processed 529 files",
+	} {
+		assert.False(t, d.CheckLine(line).IsOverloaded, line)
+	}
+}
+
 func TestDetector_parseResetTime(t *testing.T) {
 	d := NewDetector()
 
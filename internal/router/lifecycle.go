@@ -1,6 +1,8 @@
 package router
 
 import (
+	"fmt"
+
 	"bmaduum/internal/status"
 )
 
@@ -23,6 +25,17 @@ type LifecycleStep struct {
 	Model string
 }
 
+// DefaultLifecycle returns the standard story lifecycle:
+// create-story -> dev-story -> code-review -> git-commit.
+func DefaultLifecycle() []LifecycleStep {
+	return []LifecycleStep{
+		{Workflow: "create-story", NextStatus: status.StatusReadyForDev},
+		{Workflow: "dev-story", NextStatus: status.StatusReview},
+		{Workflow: "code-review", NextStatus: status.StatusDone},
+		{Workflow: "git-commit", NextStatus: status.StatusDone},
+	}
+}
+
 // GetLifecycle returns the complete sequence of lifecycle steps from the given
 // status through to "done".
 //
@@ -41,28 +54,66 @@ type LifecycleStep struct {
 //
 // See [status.Status] for valid status values.
 func GetLifecycle(s status.Status) ([]LifecycleStep, error) {
+	return GetLifecycleFrom(DefaultLifecycle(), s)
+}
+
+// GetLifecycleFrom returns the steps of a configured lifecycle that remain for
+// a story in status s.
+//
+// Each step runs from the status the previous step leaves the story in; the
+// first step runs from backlog. A step without NextStatus leaves the status
+// unchanged. The story starts at the first step that runs from s, where
+// in-progress counts as ready-for-dev. If no step runs from s, it starts at
+// the first step that moves the story past s, so a lifecycle without
+// create-story still picks up ready-for-dev stories.
+//
+// Returns [ErrStoryComplete] for done stories, [ErrUnknownStatus] for
+// unrecognized status values, and an error if no step remains for s.
+func GetLifecycleFrom(lifecycle []LifecycleStep, s status.Status) ([]LifecycleStep, error) {
+	switch {
+	case s == status.StatusDone:
+		return nil, ErrStoryComplete
+	case !s.IsValidFor(status.EntryStory):
+		return nil, ErrUnknownStatus
+	}
+
+	from := status.StatusBacklog
+	steps := make([]LifecycleStep, len(lifecycle))
+	start, past := -1, -1
+	for i, step := range lifecycle {
+		if step.NextStatus == "" {
+			step.NextStatus = from
+		}
+		steps[i] = step
+		if start < 0 && statusRank(from) == statusRank(s) {
+			start = i
+		}
+		if past < 0 && statusRank(step.NextStatus) > statusRank(s) {
+			past = i
+		}
+		from = step.NextStatus
+	}
+	if start < 0 {
+		start = past
+	}
+	if start < 0 {
+		return nil, fmt.Errorf("no lifecycle step runs from status %s", s)
+	}
+	return steps[start:], nil
+}
+
+// statusRank orders story statuses along the lifecycle. In-progress ranks
+// with ready-for-dev, since a story in progress resumes where a ready story
+// starts.
+func statusRank(s status.Status) int {
 	switch s {
 	case status.StatusBacklog:
-		return []LifecycleStep{
-			{Workflow: "create-story", NextStatus: status.StatusReadyForDev},
-			{Workflow: "dev-story", NextStatus: status.StatusReview},
-			{Workflow: "code-review", NextStatus: status.StatusDone},
-			{Workflow: "git-commit", NextStatus: status.StatusDone},
-		}, nil
+		return 0
 	case status.StatusReadyForDev, status.StatusInProgress:
-		return []LifecycleStep{
-			{Workflow: "dev-story", NextStatus: status.StatusReview},
-			{Workflow: "code-review", NextStatus: status.StatusDone},
-			{Workflow: "git-commit", NextStatus: status.StatusDone},
-		}, nil
+		return 1
 	case status.StatusReview:
-		return []LifecycleStep{
-			{Workflow: "code-review", NextStatus: status.StatusDone},
-			{Workflow: "git-commit", NextStatus: status.StatusDone},
-		}, nil
-	case status.StatusDone:
-		return nil, ErrStoryComplete
+		return 2
 	default:
-		return nil, ErrUnknownStatus
+		return 3
 	}
 }
@@ -2,6 +2,7 @@ package router
 
 import (
 	"errors"
+	"reflect"
 	"testing"
 
 	"bmaduum/internal/status"
@@ -111,3 +112,50 @@ func TestGetLifecycle(t *testing.T) {
 		})
 	}
 }
+
+func TestGetLifecycleFrom_CustomSteps(t *testing.T) {
+	lifecycle := []LifecycleStep{
+		{Workflow: "create-story", NextStatus: status.StatusReadyForDev},
+		{Workflow: "dev-story", NextStatus: status.StatusReview},
+		{Workflow: "security-scan"},
+		{Workflow: "code-review", NextStatus: status.StatusDone},
+	}
+	scan := LifecycleStep{Workflow: "security-scan", NextStatus: status.StatusReview}
+	review := LifecycleStep{Workflow: "code-review", NextStatus: status.StatusDone}
+
+	tests := []struct {
+		status status.Status
+		want   []LifecycleStep
+	}{
+		{status.StatusBacklog, []LifecycleStep{lifecycle[0], lifecycle[1], scan, review}},
+		{status.StatusInProgress, []LifecycleStep{lifecycle[1], scan, review}},
+		{status.StatusReview, []LifecycleStep{scan, review}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			got, err := GetLifecycleFrom(lifecycle, tt.status)
+			if err != nil {
+				t.Fatalf("GetLifecycleFrom(%q) err = %v", tt.status, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetLifecycleFrom(%q) = %+v, want %+v", tt.status, got, tt.want)
+			}
+		})
+	}
+
+	if lifecycle[2].NextStatus != "" {
+		t.Error("GetLifecycleFrom modified the configured lifecycle")
+	}
+
+	// Without create-story, ready stories start at the first step moving them on
+	got, err := GetLifecycleFrom(lifecycle[1:], status.StatusReadyForDev)
+	if err != nil || len(got) != 3 || got[0].Workflow != "dev-story" {
+		t.Errorf("lifecycle without create-story from ready-for-dev = %+v, %v", got, err)
+	}
+
+	_, err = GetLifecycleFrom([]LifecycleStep{{Workflow: "create-story", NextStatus: status.StatusReadyForDev}}, status.StatusReview)
+	if err == nil || err.Error() != "no lifecycle step runs from status review" {
+		t.Errorf("lifecycle without steps for review: err = %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+// Package shell runs user-configured command lines with the system shell.
+//
+// It is shared by quality gates and workflow hooks: commands run with sh -c
+// (cmd /C on Windows), their combined output is captured, and a timeout
+// stops commands that hang.
+package shell
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+)
+
+// Command describes a command line to run.
+type Command struct {
+	// Line is the command line passed to the shell.
+	Line string
+
+	// Dir is the working directory. Empty means the current directory.
+	Dir string
+
+	// Env lists extra KEY=value variables added to the inherited environment.
+	Env []string
+
+	// Timeout limits how long the command may run. Zero means no limit.
+	Timeout time.Duration
+}
+
+// Result is the outcome of running a [Command].
+type Result struct {
+	// ExitCode is the command's exit code, or -1 if it did not finish.
+	ExitCode int
+
+	// Output is the combined stdout and stderr of the command.
+	Output string
+
+	// Duration is how long the command ran.
+	Duration time.Duration
+
+	// Err is set when the command could not be started or timed out.
+	Err error
+}
+
+// Run executes c and waits for it to finish or time out.
+func Run(ctx context.Context, c Command) Result {
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	cmd := shellCommand(ctx, c.Line)
+	cmd.Dir = c.Dir
+	if len(c.Env) > 0 {
+		cmd.Env = append(os.Environ(), c.Env...)
+	}
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	// Don't wait forever on pipes held open by orphaned grandchildren
+	cmd.WaitDelay = time.Second
+
+	start := time.Now()
+	err := cmd.Run()
+	result := Result{Output: out.String(), Duration: time.Since(start)}
+
+	var exitErr *exec.ExitError
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		result.ExitCode = -1
+		result.Err = fmt.Errorf("timed out after %s", c.Timeout)
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	case err != nil:
+		result.ExitCode = -1
+		result.Err = err
+	}
+
+	return result
+}
+
+// shellCommand builds a command that runs line with the system shell.
+func shellCommand(ctx context.Context, line string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", line)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", line)
+}
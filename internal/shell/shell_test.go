@@ -0,0 +1,51 @@
+package shell
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	result := Run(context.Background(), Command{Line: "echo out && exit 4"})
+
+	assert.Equal(t, 4, result.ExitCode)
+	assert.Equal(t, "out", strings.TrimSpace(result.Output))
+	assert.NoError(t, result.Err)
+}
+
+func TestRun_DirAndEnv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh syntax")
+	}
+	dir := t.TempDir()
+
+	result := Run(context.Background(), Command{
+		Line: `echo "$BMADUUM_TEST_VAR" && pwd`,
+		Dir:  dir,
+		Env:  []string{"BMADUUM_TEST_VAR=hello"},
+	})
+
+	require.Equal(t, 0, result.ExitCode)
+	lines := strings.Split(strings.TrimSpace(result.Output), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, "hello", lines[0])
+	assert.Contains(t, lines[1], dir)
+}
+
+func TestRun_Timeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sleep")
+	}
+
+	result := Run(context.Background(), Command{Line: "sleep 5", Timeout: 100 * time.Millisecond})
+
+	assert.Equal(t, -1, result.ExitCode)
+	require.Error(t, result.Err)
+	assert.Equal(t, "timed out after 100ms", result.Err.Error())
+}
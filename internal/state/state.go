@@ -50,6 +50,15 @@ type State struct {
 	// StartStatus is the story's status when execution began.
 	// Stored for debugging and context when viewing saved state.
 	StartStatus string `json:"start_status"`
+
+	// BaseCommit is the commit checked out before the story first started.
+	// A resumed story keeps its original base, so resetting under the
+	// "reset" failure policy discards the work of every attempt.
+	BaseCommit string `json:"base_commit,omitempty"`
+
+	// PartialWork describes where the failed story's partial work went under
+	// the failure policy, e.g. the stash it was saved in.
+	PartialWork string `json:"partial_work,omitempty"`
 }
 
 // Manager handles state persistence operations.
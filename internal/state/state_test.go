@@ -5,6 +5,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -206,3 +207,38 @@ func TestExistsReturnsFalseWhenFileMissing(t *testing.T) {
 		t.Error("Exists should return false when file missing")
 	}
 }
+
+// TestStateFailurePolicyFields verifies the failure policy fields round-trip
+// and are omitted when empty
+func TestStateFailurePolicyFields(t *testing.T) {
+	tmpDir := t.TempDir()
+	mgr := NewManager(tmpDir)
+
+	state := State{
+		StoryKey:    "6-1-first",
+		StepIndex:   1,
+		TotalSteps:  3,
+		StartStatus: "ready-for-dev",
+		BaseCommit:  "0123456789abcdef",
+		PartialWork: `stashed as "bmaduum: 6-1-first partial work"`,
+	}
+	if err := mgr.Save(state); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := mgr.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if loaded != state {
+		t.Errorf("loaded state mismatch: got %+v, want %+v", loaded, state)
+	}
+
+	data, err := json.Marshal(State{StoryKey: "6-1-first"})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if strings.Contains(string(data), "base_commit") || strings.Contains(string(data), "partial_work") {
+		t.Errorf("empty failure policy fields should be omitted: %s", data)
+	}
+}
@@ -0,0 +1,222 @@
+package status
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// dependencyLinePattern matches dependency declarations in story markdown files.
+//
+// Both "Depends on: 6-1, 6-2" and "Dependencies: 6-1" are recognized, with
+// optional list markers and bold/italic emphasis around the label.
+var dependencyLinePattern = regexp.MustCompile(`(?im)^[ \t>*_-]*(?:depends[ \t]+on|dependencies)[*_]*[ \t]*:[*_]*[ \t]*(.+)$`)
+
+// dottedStoryPattern matches story numbers written with a dot, like "6.2".
+var dottedStoryPattern = regexp.MustCompile(`^(\d+)\.(\d+)$`)
+
+// DependencyCycleError is returned when story dependencies form a cycle.
+//
+// Cycle lists the story keys along the cycle, with the first key repeated at
+// the end (e.g., 6-1 -> 6-2 -> 6-1) so the loop is visible in error output.
+type DependencyCycleError struct {
+	// Cycle is the ordered list of story keys forming the cycle.
+	Cycle []string
+}
+
+// Error implements the error interface.
+func (e *DependencyCycleError) Error() string {
+	return fmt.Sprintf("dependency cycle detected: %s", strings.Join(e.Cycle, " -> "))
+}
+
+// GetStoryDependencies returns the resolved story keys the given story depends on.
+//
+// Dependencies are collected from the dependencies map in sprint-status.yaml
+// and from a "Depends on:" line in the story's markdown file, if one exists.
+// Short references such as "6-2" are resolved to the full story key
+// ("6-2-add-api"). References that cannot be resolved are returned unchanged
+// so callers can report them.
+//
+// Returns an error if the status file cannot be read.
+func (r *Reader) GetStoryDependencies(storyKey string) ([]string, error) {
+	sprintStatus, err := r.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.dependencies(sprintStatus, storyKey), nil
+}
+
+// dependencies collects and resolves the dependencies of a single story.
+func (r *Reader) dependencies(sprintStatus *SprintStatus, storyKey string) []string {
+	var refs []string
+	for key, deps := range sprintStatus.Dependencies {
+		if resolveStoryRef(sprintStatus, key) == storyKey {
+			refs = append(refs, deps...)
+		}
+	}
+	refs = append(refs, r.storyFileDependencies(sprintStatus, storyKey)...)
+
+	seen := make(map[string]bool)
+	var result []string
+	for _, ref := range refs {
+		dep := resolveStoryRef(sprintStatus, strings.TrimSpace(ref))
+		if dep == "" || dep == storyKey || seen[dep] {
+			continue
+		}
+		seen[dep] = true
+		result = append(result, dep)
+	}
+
+	return result
+}
+
+// storyFileDependencies parses dependency declarations from a story's markdown file.
+//
+// Only words that look like story references are taken: story numbers such
+// as "6-2" or "6.2" and story keys such as "6-2-add-api". Other words, like
+// free text in "Depends on: the auth story being merged", are ignored.
+//
+// See [Reader.StoryFilePath] for where story files live. A missing file is not
+// an error; it simply declares no dependencies.
+func (r *Reader) storyFileDependencies(sprintStatus *SprintStatus, storyKey string) []string {
+	data, err := os.ReadFile(filepath.Join(storyDir(r.basePath, r.Path(), sprintStatus), storyKey+".md"))
+	if err != nil {
+		return nil
+	}
+
+	var refs []string
+	for _, match := range dependencyLinePattern.FindAllStringSubmatch(string(data), -1) {
+		for _, ref := range strings.FieldsFunc(match[1], func(r rune) bool {
+			return r == ',' || r == ';' || r == ' ' || r == '\t'
+		}) {
+			ref = strings.Trim(ref, "`*_[]().:")
+			if m := dottedStoryPattern.FindStringSubmatch(ref); m != nil {
+				ref = m[1] + "-" + m[2]
+			}
+			if kind, epicID, _ := ParseKey(ref); kind != EntryStory || epicID == "" {
+				continue
+			}
+			refs = append(refs, ref)
+		}
+	}
+
+	return refs
+}
+
+// resolveStoryRef resolves a dependency reference to a full story key.
+//
+// An exact key match wins. Otherwise a short reference like "6-2" matches the
+// single story key starting with "6-2-". Ambiguous or unknown references are
+// returned unchanged.
+func resolveStoryRef(sprintStatus *SprintStatus, ref string) string {
+	if _, ok := sprintStatus.DevelopmentStatus[ref]; ok {
+		return ref
+	}
+
+	match := ""
+	prefix := ref + "-"
+	for key := range sprintStatus.DevelopmentStatus {
+		if strings.HasPrefix(key, prefix) {
+			if match != "" {
+				return ref
+			}
+			match = key
+		}
+	}
+	if match == "" {
+		return ref
+	}
+
+	return match
+}
+
+// OrderStories sorts story keys so that every story comes after its dependencies.
+//
+// The input order is preserved wherever dependencies allow it, so callers
+// should pass keys already sorted by story number. Dependencies on stories not
+// present in keys (e.g., stories from another epic) do not affect ordering.
+//
+// Returns a [*DependencyCycleError] if the dependencies form a cycle.
+func OrderStories(keys []string, deps map[string][]string) ([]string, error) {
+	index := make(map[string]int, len(keys))
+	for i, key := range keys {
+		index[key] = i
+	}
+
+	// Count in-set dependencies for each story (Kahn's algorithm)
+	pending := make(map[string]int, len(keys))
+	dependents := make(map[string][]string)
+	for _, key := range keys {
+		for _, dep := range deps[key] {
+			if _, ok := index[dep]; !ok {
+				continue
+			}
+			pending[key]++
+			dependents[dep] = append(dependents[dep], key)
+		}
+	}
+
+	result := make([]string, 0, len(keys))
+	placed := make(map[string]bool, len(keys))
+	for len(result) < len(keys) {
+		// Pick the earliest story in input order whose dependencies are placed
+		next := ""
+		for _, key := range keys {
+			if !placed[key] && pending[key] == 0 {
+				next = key
+				break
+			}
+		}
+		if next == "" {
+			return nil, &DependencyCycleError{Cycle: findCycle(keys, deps, placed)}
+		}
+
+		placed[next] = true
+		result = append(result, next)
+		for _, dependent := range dependents[next] {
+			pending[dependent]--
+		}
+	}
+
+	return result, nil
+}
+
+// findCycle walks dependencies among unplaced stories until a key repeats.
+//
+// Every unplaced story has at least one unplaced dependency, so the walk is
+// guaranteed to revisit a key.
+func findCycle(keys []string, deps map[string][]string, placed map[string]bool) []string {
+	inSet := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		inSet[key] = true
+	}
+
+	var start string
+	for _, key := range keys {
+		if !placed[key] {
+			start = key
+			break
+		}
+	}
+
+	var path []string
+	visited := make(map[string]int)
+	current := start
+	for {
+		if at, ok := visited[current]; ok {
+			return append(path[at:], current)
+		}
+		visited[current] = len(path)
+		path = append(path, current)
+
+		for _, dep := range deps[current] {
+			if inSet[dep] && !placed[dep] {
+				current = dep
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package status
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeStatusFixture writes sprint-status.yaml and optional story files into tmpDir.
+func writeStatusFixture(t *testing.T, tmpDir, statusContent string, storyFiles map[string]string) {
+	t.Helper()
+
+	statusDir := filepath.Join(tmpDir, "_bmad-output", "implementation-artifacts")
+	require.NoError(t, os.MkdirAll(statusDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(statusDir, "sprint-status.yaml"), []byte(statusContent), 0644))
+
+	for key, content := range storyFiles {
+		require.NoError(t, os.WriteFile(filepath.Join(statusDir, key+".md"), []byte(content), 0644))
+	}
+}
+
+func TestOrderStories(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []string
+		deps map[string][]string
+		want []string
+	}{
+		{
+			name: "no dependencies keeps input order",
+			keys: []string{"6-1-a", "6-2-b", "6-3-c"},
+			want: []string{"6-1-a", "6-2-b", "6-3-c"},
+		},
+		{
+			name: "dependency moves story after its prerequisite",
+			keys: []string{"6-1-a", "6-2-b", "6-3-c"},
+			deps: map[string][]string{"6-1-a": {"6-3-c"}},
+			want: []string{"6-2-b", "6-3-c", "6-1-a"},
+		},
+		{
+			name: "chain of dependencies",
+			keys: []string{"6-1-a", "6-2-b", "6-3-c"},
+			deps: map[string][]string{"6-1-a": {"6-2-b"}, "6-2-b": {"6-3-c"}},
+			want: []string{"6-3-c", "6-2-b", "6-1-a"},
+		},
+		{
+			name: "dependencies outside the set are ignored",
+			keys: []string{"6-1-a", "6-2-b"},
+			deps: map[string][]string{"6-1-a": {"5-3-x"}},
+			want: []string{"6-1-a", "6-2-b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := OrderStories(tt.keys, tt.deps)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestOrderStories_Cycle(t *testing.T) {
+	keys := []string{"6-1-a", "6-2-b", "6-3-c"}
+	deps := map[string][]string{
+		"6-1-a": {"6-2-b"},
+		"6-2-b": {"6-3-c"},
+		"6-3-c": {"6-1-a"},
+	}
+
+	got, err := OrderStories(keys, deps)
+
+	assert.Nil(t, got)
+	var cycleErr *DependencyCycleError
+	require.True(t, errors.As(err, &cycleErr))
+	assert.Equal(t, []string{"6-1-a", "6-2-b", "6-3-c", "6-1-a"}, cycleErr.Cycle)
+	assert.Contains(t, err.Error(), "6-1-a -> 6-2-b -> 6-3-c -> 6-1-a")
+}
+
+func TestReader_GetStoryDependencies(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeStatusFixture(t, tmpDir, `development_status:
+  5-3-shared-lib: done
+  6-1-schema: backlog
+  6-2-api: backlog
+  6-4-ui: backlog
+dependencies:
+  6-4: [6-2]
+`, map[string]string{
+		"6-4-ui": "# Story 6.4: UI\n\n**Depends on:** 6-1, `5-3`\n",
+	})
+
+	reader := NewReader(tmpDir)
+	deps, err := reader.GetStoryDependencies("6-4-ui")
+
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"6-2-api", "6-1-schema", "5-3-shared-lib"}, deps)
+}
+
+func TestReader_GetStoryDependencies_UnknownReferenceKept(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeStatusFixture(t, tmpDir, `development_status:
+  6-1-schema: backlog
+dependencies:
+  6-1-schema: [9-9]
+`, nil)
+
+	reader := NewReader(tmpDir)
+	deps, err := reader.GetStoryDependencies("6-1-schema")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"9-9"}, deps)
+}
+
+func TestReader_GetStoryDependencies_FreeText(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeStatusFixture(t, tmpDir, `development_status:
+  6-1-schema: done
+  6-2-api: backlog
+  6-3-ui: backlog
+`, map[string]string{
+		"6-3-ui": "# Story\n\nDepends on: the auth story being merged\nDependencies: Story 6.2 (API), then epic-5.\n",
+	})
+
+	reader := NewReader(tmpDir)
+	deps, err := reader.GetStoryDependencies("6-3-ui")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"6-2-api"}, deps)
+}
+
+func TestReader_GetStoryDependencies_NoneDeclared(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeStatusFixture(t, tmpDir, `development_status:
+  6-1-schema: backlog
+`, map[string]string{
+		"6-1-schema": "# Story\n\nDependencies: none\n",
+	})
+
+	reader := NewReader(tmpDir)
+	deps, err := reader.GetStoryDependencies("6-1-schema")
+
+	require.NoError(t, err)
+	assert.Empty(t, deps)
+}
+
+func TestReader_GetEpicStories_DependencyOrder(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeStatusFixture(t, tmpDir, `development_status:
+  6-1-schema: backlog
+  6-2-api: backlog
+  6-3-ui: backlog
+dependencies:
+  6-1: [6-3]
+`, nil)
+
+	reader := NewReader(tmpDir)
+	stories, err := reader.GetEpicStories("6")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"6-2-api", "6-3-ui", "6-1-schema"}, stories)
+}
+
+func TestReader_GetEpicStories_DependencyCycle(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeStatusFixture(t, tmpDir, `development_status:
+  6-1-schema: backlog
+  6-2-api: backlog
+dependencies:
+  6-1: [6-2]
+  6-2: [6-1]
+`, nil)
+
+	reader := NewReader(tmpDir)
+	stories, err := reader.GetEpicStories("6")
+
+	assert.Nil(t, stories)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "dependency cycle detected")
+}
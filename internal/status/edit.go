@@ -0,0 +1,177 @@
+package status
+
+import (
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// valueEdit is a pending replacement of one scalar value in the status file.
+type valueEdit struct {
+	// key is the development_status key whose value changes.
+	key string
+
+	// node is the parsed value node whose source bytes are replaced.
+	node *yaml.Node
+
+	// value is the new scalar value.
+	value string
+}
+
+// applyEdits replaces the source bytes of each edited value node in data.
+//
+// Only the bytes of the scalar itself are rewritten; anchors, tags, comments,
+// indentation and line endings around it are left untouched. Quoted scalars
+// keep their quote style, and an alias (*name) is replaced by the plain value.
+//
+// Returns an error if a value cannot be located or is a block scalar, which
+// cannot be edited in place.
+func applyEdits(data []byte, edits []valueEdit) ([]byte, error) {
+	type span struct {
+		start, end int
+		text       string
+	}
+
+	lines := lineOffsets(data)
+	spans := make([]span, 0, len(edits))
+	for _, edit := range edits {
+		start, end, err := scalarSpan(data, lines, edit.node)
+		if err != nil {
+			return nil, err
+		}
+		spans = append(spans, span{start: start, end: end, text: formatScalar(edit.node, edit.value)})
+	}
+
+	// Apply from the end of the file so earlier offsets stay valid
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start > spans[j].start })
+
+	result := append([]byte(nil), data...)
+	for _, s := range spans {
+		result = append(result[:s.start], append([]byte(s.text), result[s.end:]...)...)
+	}
+
+	return result, nil
+}
+
+// lineOffsets returns the byte offset at which each line of data starts.
+//
+// Line breaks follow the YAML parser: "\r\n", "\n" and a lone "\r" each end
+// a line, so node line numbers map onto the same offsets.
+func lineOffsets(data []byte) []int {
+	offsets := []int{0}
+	for i := 0; i < len(data); i++ {
+		switch data[i] {
+		case '\r':
+			if i+1 < len(data) && data[i+1] == '\n' {
+				i++
+			}
+			offsets = append(offsets, i+1)
+		case '\n':
+			offsets = append(offsets, i+1)
+		}
+	}
+	return offsets
+}
+
+// scalarSpan returns the byte range of a value node's scalar text in data.
+//
+// The node's line and column (1-based, counted in characters) point at the
+// start of its properties, so any anchor or tag is skipped first.
+func scalarSpan(data []byte, lines []int, node *yaml.Node) (int, int, error) {
+	if node.Line < 1 || node.Line > len(lines) {
+		return 0, 0, fmt.Errorf("cannot locate value %q in sprint status", node.Value)
+	}
+
+	// Convert the character column to a byte offset
+	pos := lines[node.Line-1]
+	for col := 1; col < node.Column && pos < len(data); col++ {
+		_, size := utf8.DecodeRune(data[pos:])
+		pos += size
+	}
+
+	// Skip anchor (&name) and tag (!tag) properties
+	for pos < len(data) && (data[pos] == '&' || data[pos] == '!') {
+		for pos < len(data) && !isYAMLSpace(data[pos]) {
+			pos++
+		}
+		for pos < len(data) && (data[pos] == ' ' || data[pos] == '\t') {
+			pos++
+		}
+	}
+
+	end := pos
+	switch {
+	case node.Kind == yaml.AliasNode:
+		if pos >= len(data) || data[pos] != '*' {
+			return 0, 0, fmt.Errorf("cannot locate alias *%s in sprint status", node.Value)
+		}
+		for end < len(data) && !isYAMLSpace(data[end]) && data[end] != ',' && data[end] != '}' && data[end] != ']' {
+			end++
+		}
+
+	case node.Style&yaml.SingleQuotedStyle != 0:
+		// '' is an escaped quote inside single-quoted scalars
+		for end++; end < len(data); end++ {
+			if data[end] == '\'' {
+				if end+1 < len(data) && data[end+1] == '\'' {
+					end++
+					continue
+				}
+				break
+			}
+		}
+		end++
+
+	case node.Style&yaml.DoubleQuotedStyle != 0:
+		for end++; end < len(data) && data[end] != '"'; end++ {
+			if data[end] == '\\' {
+				end++
+			}
+		}
+		end++
+
+	case node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0:
+		return 0, 0, fmt.Errorf("cannot update block scalar value %q in place", node.Value)
+
+	default:
+		end = pos + len(node.Value)
+		if end > len(data) || string(data[pos:end]) != node.Value {
+			return 0, 0, fmt.Errorf("cannot locate value %q in sprint status", node.Value)
+		}
+	}
+
+	if end > len(data) {
+		return 0, 0, fmt.Errorf("cannot locate value %q in sprint status", node.Value)
+	}
+
+	return pos, end, nil
+}
+
+// formatScalar renders value in the quote style of the node it replaces.
+func formatScalar(node *yaml.Node, value string) string {
+	switch {
+	case node.Kind == yaml.AliasNode:
+		return value
+	case node.Style&yaml.SingleQuotedStyle != 0:
+		return "'" + value + "'"
+	case node.Style&yaml.DoubleQuotedStyle != 0:
+		return `"` + value + `"`
+	default:
+		return value
+	}
+}
+
+// isYAMLSpace reports whether b separates YAML tokens.
+func isYAMLSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\r' || b == '\n'
+}
+
+// scalarValue returns the value of a scalar node, following aliases.
+func scalarValue(node *yaml.Node) string {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		return node.Alias.Value
+	}
+	return node.Value
+}
@@ -0,0 +1,56 @@
+package status
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestApplyEdits(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "plain value",
+			input: "k: backlog # note\n",
+			want:  "k: done # note\n",
+		},
+		{
+			name:  "multi-byte characters before value",
+			input: "\"ключ\": backlog\n",
+			want:  "\"ключ\": done\n",
+		},
+		{
+			name:  "double-quoted value",
+			input: "k: \"backlog\"\n",
+			want:  "k: \"done\"\n",
+		},
+		{
+			name:  "flow mapping",
+			input: "{k: backlog, other: review}\n",
+			want:  "{k: done, other: review}\n",
+		},
+		{
+			name:  "lone carriage return line breaks",
+			input: "# c\rk: backlog\r",
+			want:  "# c\rk: done\r",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var doc yaml.Node
+			require.NoError(t, yaml.Unmarshal([]byte(tt.input), &doc))
+			value := doc.Content[0].Content[1]
+
+			got, err := applyEdits([]byte(tt.input), []valueEdit{{node: value, value: "done"}})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package status
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"bmaduum/internal/git"
+)
+
+// Transition is one recorded status change from the history log.
+//
+// Every [Writer.UpdateStatus] call appends a transition, including the epic
+// entry changes made by epic status sync.
+type Transition struct {
+	// Key is the development_status key that changed (e.g., "6-2-add-api").
+	Key string `json:"key"`
+
+	// From is the status before the update.
+	From Status `json:"from"`
+
+	// To is the status after the update.
+	To Status `json:"to"`
+
+	// Time is when the update was written.
+	Time time.Time `json:"time"`
+
+	// Workflow is the workflow whose completion caused the update, if known.
+	Workflow string `json:"workflow,omitempty"`
+
+	// Model is the model the workflow ran on, if known. With a model
+	// fallback chain this is the model that finished the workflow.
+	Model string `json:"model,omitempty"`
+
+	// RunID identifies the bmaduum invocation that made the update.
+	RunID string `json:"run_id,omitempty"`
+
+	// GitHead is the commit checked out when the update was written, if the
+	// project is a git repository.
+	GitHead string `json:"git_head,omitempty"`
+}
+
+// HistoryPath returns the path of the transition history log for a status file.
+//
+// The log is a JSON Lines sidecar next to the status file, e.g.
+// sprint-status.yaml is paired with sprint-status.history.jsonl.
+func HistoryPath(statusFile string) string {
+	return strings.TrimSuffix(statusFile, filepath.Ext(statusFile)) + ".history.jsonl"
+}
+
+// NewRunID returns an identifier for one bmaduum invocation.
+//
+// The ID starts with a UTC timestamp so IDs sort chronologically, followed by
+// a random suffix to keep concurrent runs apart (e.g., "20260118T140211-9f3a1c").
+func NewRunID() string {
+	suffix := make([]byte, 3)
+	rand.Read(suffix) //nolint:errcheck // crypto/rand.Read never fails
+	return time.Now().UTC().Format("20060102T150405") + "-" + hex.EncodeToString(suffix)
+}
+
+// GetHistory returns the recorded transitions of a story, oldest first.
+//
+// Short references such as "6-2" are resolved to the full key as for
+// dependencies. A missing history log is not an error; it simply has no
+// transitions.
+func (r *Reader) GetHistory(storyKey string) ([]Transition, error) {
+	if sprintStatus, err := r.Read(); err == nil {
+		storyKey = resolveStoryRef(sprintStatus, storyKey)
+	}
+
+	transitions, err := readHistory(HistoryPath(r.Path()))
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Transition
+	for _, t := range transitions {
+		if t.Key == storyKey {
+			result = append(result, t)
+		}
+	}
+
+	return result, nil
+}
+
+// readHistory parses every transition in a history log.
+func readHistory(path string) ([]Transition, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read status history: %w", err)
+	}
+
+	var transitions []Transition
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var t Transition
+		if err := json.Unmarshal(scanner.Bytes(), &t); err != nil {
+			return nil, fmt.Errorf("failed to parse status history line %d: %w", line, err)
+		}
+		transitions = append(transitions, t)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read status history: %w", err)
+	}
+
+	return transitions, nil
+}
+
+// appendHistory appends transitions to the history log, one JSON object per line.
+//
+// Callers must hold the status file lock so records from concurrent writers
+// do not interleave.
+func appendHistory(path string, transitions []Transition) error {
+	var buf bytes.Buffer
+	for _, t := range transitions {
+		line, err := json.Marshal(t)
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// currentGitHead returns the commit hash checked out in dir, or an empty
+// string if dir is not inside a git repository.
+func currentGitHead(dir string) string {
+	head, err := git.Head(dir)
+	if err != nil {
+		return ""
+	}
+	return head
+}
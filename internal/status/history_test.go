@@ -0,0 +1,168 @@
+package status
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistoryPath(t *testing.T) {
+	assert.Equal(t,
+		filepath.Join("out", "sprint-status.history.jsonl"),
+		HistoryPath(filepath.Join("out", "sprint-status.yaml")))
+}
+
+func TestNewRunID(t *testing.T) {
+	id := NewRunID()
+
+	assert.Regexp(t, regexp.MustCompile(`^\d{8}T\d{6}-[0-9a-f]{6}$`), id)
+	assert.NotEqual(t, id, NewRunID())
+}
+
+func TestWriter_UpdateStatus_RecordsHistory(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeStatusFixture(t, tmpDir, `development_status:
+  epic-7: backlog
+  7-1-define-schema: backlog
+  7-2-create-api: backlog
+`, nil)
+
+	writer := NewWriter(tmpDir)
+	writer.SetRunID("run-1")
+	writer.gitHead = func(dir string) string { return "abc123" }
+
+	require.NoError(t, writer.UpdateStatusForWorkflow("7-1-define-schema", StatusReadyForDev, "create-story", "sonnet"))
+	require.NoError(t, writer.UpdateStatus("7-2-create-api", StatusReview))
+
+	transitions, err := readHistory(HistoryPath(filepath.Join(tmpDir, DefaultStatusPath)))
+	require.NoError(t, err)
+	require.Len(t, transitions, 3)
+
+	// Story update and the epic sync it triggered
+	assert.Equal(t, "7-1-define-schema", transitions[0].Key)
+	assert.Equal(t, StatusBacklog, transitions[0].From)
+	assert.Equal(t, StatusReadyForDev, transitions[0].To)
+	assert.Equal(t, "create-story", transitions[0].Workflow)
+	assert.Equal(t, "sonnet", transitions[0].Model)
+	assert.Equal(t, "run-1", transitions[0].RunID)
+	assert.Equal(t, "abc123", transitions[0].GitHead)
+	assert.False(t, transitions[0].Time.IsZero())
+
+	assert.Equal(t, "epic-7", transitions[1].Key)
+	assert.Equal(t, StatusBacklog, transitions[1].From)
+	assert.Equal(t, StatusInProgress, transitions[1].To)
+
+	assert.Equal(t, "7-2-create-api", transitions[2].Key)
+	assert.Empty(t, transitions[2].Workflow)
+	assert.Empty(t, transitions[2].Model)
+}
+
+func TestWriter_UpdateStatus_Unchanged(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeStatusFixture(t, tmpDir, `development_status:
+  epic-7: in-progress
+  7-1-define-schema: done
+  7-2-create-api: review
+`, nil)
+	statusPath := filepath.Join(tmpDir, DefaultStatusPath)
+	before, err := os.ReadFile(statusPath)
+	require.NoError(t, err)
+
+	writer := NewWriter(tmpDir)
+	require.NoError(t, writer.UpdateStatusForWorkflow("7-1-define-schema", StatusDone, "git-commit", ""))
+
+	after, err := os.ReadFile(statusPath)
+	require.NoError(t, err)
+	assert.Equal(t, string(before), string(after))
+	_, err = os.Stat(HistoryPath(statusPath))
+	assert.True(t, os.IsNotExist(err), "no transition is recorded")
+
+	// A later change is recorded once, without the unchanged entry
+	require.NoError(t, writer.UpdateStatus("7-2-create-api", StatusDone))
+	require.NoError(t, writer.UpdateStatus("7-2-create-api", StatusDone))
+	transitions, err := readHistory(HistoryPath(statusPath))
+	require.NoError(t, err)
+	require.Len(t, transitions, 2)
+	assert.Equal(t, "7-2-create-api", transitions[0].Key)
+	assert.Equal(t, "epic-7", transitions[1].Key)
+	assert.Equal(t, StatusDone, transitions[1].To)
+}
+
+func TestWriter_RecordTransition(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeStatusFixture(t, tmpDir, `development_status:
+  7-1-define-schema: done
+`, nil)
+	statusPath := filepath.Join(tmpDir, DefaultStatusPath)
+	before, err := os.ReadFile(statusPath)
+	require.NoError(t, err)
+
+	writer := NewWriter(tmpDir)
+	writer.SetRunID("run-1")
+	writer.gitHead = func(dir string) string { return "abc123" }
+
+	require.NoError(t, writer.RecordTransition("epic-7-retrospective", StatusDone, "retrospective"))
+	assert.Error(t, writer.RecordTransition("epic-7-retrospective", StatusInProgress, "retrospective"))
+
+	after, err := os.ReadFile(statusPath)
+	require.NoError(t, err)
+	assert.Equal(t, string(before), string(after), "the status file is not edited")
+
+	transitions, err := NewReader(tmpDir).GetHistory("epic-7-retrospective")
+	require.NoError(t, err)
+	require.Len(t, transitions, 1)
+	assert.Empty(t, transitions[0].From)
+	assert.Equal(t, StatusDone, transitions[0].To)
+	assert.Equal(t, "retrospective", transitions[0].Workflow)
+	assert.Equal(t, "run-1", transitions[0].RunID)
+	assert.Equal(t, "abc123", transitions[0].GitHead)
+}
+
+func TestReader_GetHistory(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeStatusFixture(t, tmpDir, `development_status:
+  7-1-define-schema: backlog
+  7-2-create-api: backlog
+`, nil)
+
+	writer := NewWriter(tmpDir)
+	require.NoError(t, writer.UpdateStatus("7-1-define-schema", StatusReadyForDev))
+	require.NoError(t, writer.UpdateStatus("7-2-create-api", StatusReadyForDev))
+	require.NoError(t, writer.UpdateStatus("7-1-define-schema", StatusInProgress))
+
+	reader := NewReader(tmpDir)
+	history, err := reader.GetHistory("7-1")
+
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, StatusReadyForDev, history[0].To)
+	assert.Equal(t, StatusReadyForDev, history[1].From)
+	assert.Equal(t, StatusInProgress, history[1].To)
+	assert.False(t, history[1].Time.Before(history[0].Time))
+}
+
+func TestReader_GetHistory_NoLog(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeStatusFixture(t, tmpDir, "development_status:\n  7-1-define-schema: backlog\n", nil)
+
+	history, err := NewReader(tmpDir).GetHistory("7-1-define-schema")
+
+	require.NoError(t, err)
+	assert.Empty(t, history)
+}
+
+func TestReader_GetHistory_InvalidLine(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeStatusFixture(t, tmpDir, "development_status:\n  7-1-define-schema: backlog\n", nil)
+	historyPath := HistoryPath(filepath.Join(tmpDir, DefaultStatusPath))
+	require.NoError(t, os.WriteFile(historyPath, []byte("{\"key\":\"7-1-define-schema\"}\nnot json\n"), 0644))
+
+	_, err := NewReader(tmpDir).GetHistory("7-1-define-schema")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 2")
+}
@@ -0,0 +1,70 @@
+package status
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// lockPollInterval is how often a contended lock is retried.
+const lockPollInterval = 50 * time.Millisecond
+
+// ErrLockTimeout is returned when the sprint status lock cannot be acquired
+// within the writer's lock timeout, typically because another bmaduum process
+// is holding it.
+var ErrLockTimeout = errors.New("timed out waiting for sprint status lock")
+
+// LockPath returns the path of the lock file that serializes writes to a
+// status file, e.g. sprint-status.yaml.lock. It must never be removed (see
+// [fileLock]).
+func LockPath(statusFile string) string {
+	return statusFile + ".lock"
+}
+
+// fileLock is an advisory, cross-process lock on a sidecar lock file.
+//
+// The lock is held via flock (Unix) or LockFileEx (Windows) on an open file
+// handle, so it is released automatically if the process dies. The lock file
+// itself is left in place; removing it while another process waits on it
+// would let two processes hold "the" lock at once.
+type fileLock struct {
+	f *os.File
+}
+
+// acquireLock takes an exclusive lock on path, creating the file if needed.
+//
+// It polls until the lock is acquired or timeout elapses, returning
+// [ErrLockTimeout] in the latter case.
+func acquireLock(path string, timeout time.Duration) (*fileLock, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		ok, err := tryLockFile(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		if ok {
+			return &fileLock{f: f}, nil
+		}
+		if time.Now().After(deadline) {
+			f.Close()
+			return nil, fmt.Errorf("%w: %s", ErrLockTimeout, path)
+		}
+		time.Sleep(lockPollInterval)
+	}
+}
+
+// release unlocks and closes the lock file.
+func (l *fileLock) release() error {
+	err := unlockFile(l.f)
+	if closeErr := l.f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
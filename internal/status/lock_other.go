@@ -0,0 +1,15 @@
+//go:build !unix && !windows
+
+package status
+
+import "os"
+
+// tryLockFile always succeeds on platforms without file locking support.
+func tryLockFile(f *os.File) (bool, error) {
+	return true, nil
+}
+
+// unlockFile is a no-op on platforms without file locking support.
+func unlockFile(f *os.File) error {
+	return nil
+}
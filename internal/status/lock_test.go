@@ -0,0 +1,54 @@
+package status
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcquireLock_Release(t *testing.T) {
+	lockPath := filepath.Join(t.TempDir(), "sprint-status.yaml.lock")
+
+	lock, err := acquireLock(lockPath, time.Second)
+	require.NoError(t, err)
+	require.NoError(t, lock.release())
+
+	// Lock can be taken again once released
+	lock, err = acquireLock(lockPath, time.Second)
+	require.NoError(t, err)
+	require.NoError(t, lock.release())
+}
+
+func TestAcquireLock_TimeoutWhenHeld(t *testing.T) {
+	lockPath := filepath.Join(t.TempDir(), "sprint-status.yaml.lock")
+
+	held, err := acquireLock(lockPath, time.Second)
+	require.NoError(t, err)
+	defer held.release()
+
+	lock, err := acquireLock(lockPath, 100*time.Millisecond)
+
+	assert.Nil(t, lock)
+	assert.True(t, errors.Is(err, ErrLockTimeout))
+}
+
+func TestWriter_UpdateStatus_LockHeld(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeStatusFixture(t, tmpDir, "development_status:\n  7-1-define-schema: backlog\n", nil)
+
+	held, err := acquireLock(filepath.Join(tmpDir, DefaultStatusPath)+".lock", time.Second)
+	require.NoError(t, err)
+	defer held.release()
+
+	writer := NewWriter(tmpDir)
+	writer.SetLockTimeout(100 * time.Millisecond)
+	err = writer.UpdateStatus("7-1-define-schema", StatusInProgress)
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrLockTimeout))
+	assert.Contains(t, err.Error(), "failed to lock sprint status")
+}
@@ -0,0 +1,107 @@
+package status
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BMADConfigPath is the location of BMAD's module configuration relative to
+// the project root. Its output folder settings determine where
+// sprint-status.yaml lives when no explicit path is configured.
+const BMADConfigPath = "_bmad/bmm/config.yaml"
+
+// ErrProjectNotFound is returned by [FindProjectRoot] when no directory between
+// the start directory and the filesystem root looks like a BMAD project.
+var ErrProjectNotFound = errors.New("no BMAD project found")
+
+// bmadConfig holds the output folder settings from BMAD's config.yaml.
+type bmadConfig struct {
+	OutputFolder            string `yaml:"output_folder"`
+	ImplementationArtifacts string `yaml:"implementation_artifacts"`
+}
+
+// FindProjectRoot walks up from start looking for a BMAD project root.
+//
+// A directory is a project root if it contains the _bmad directory or a
+// sprint-status.yaml at [DefaultStatusPath]. The returned path is absolute.
+// Returns [ErrProjectNotFound] if the filesystem root is reached first.
+func FindProjectRoot(start string) (string, error) {
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		if isDir(filepath.Join(dir, "_bmad")) || isFile(filepath.Join(dir, DefaultStatusPath)) {
+			return dir, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ErrProjectNotFound
+		}
+		dir = parent
+	}
+}
+
+// ResolveStatusPath determines the sprint-status.yaml path for a project.
+//
+// The path is resolved in priority order:
+//  1. configured, the status.path config value (relative to projectRoot)
+//  2. implementation_artifacts or output_folder from BMAD's config.yaml
+//  3. [DefaultStatusPath]
+//
+// BMAD's "{project-root}" placeholder is expanded to projectRoot. The result
+// is relative to projectRoot unless an absolute path was configured.
+func ResolveStatusPath(projectRoot, configured string) string {
+	if configured != "" {
+		return expandProjectRoot(configured, projectRoot)
+	}
+
+	data, err := os.ReadFile(filepath.Join(projectRoot, BMADConfigPath))
+	if err != nil {
+		return DefaultStatusPath
+	}
+
+	var cfg bmadConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return DefaultStatusPath
+	}
+
+	switch {
+	case cfg.ImplementationArtifacts != "":
+		artifacts := strings.ReplaceAll(cfg.ImplementationArtifacts, "{output_folder}", cfg.OutputFolder)
+		return filepath.Join(expandProjectRoot(artifacts, projectRoot), "sprint-status.yaml")
+	case cfg.OutputFolder != "":
+		return filepath.Join(expandProjectRoot(cfg.OutputFolder, projectRoot), "implementation-artifacts", "sprint-status.yaml")
+	default:
+		return DefaultStatusPath
+	}
+}
+
+// expandProjectRoot replaces BMAD's "{project-root}" placeholder.
+//
+// Paths under the placeholder are returned relative to projectRoot so they
+// can be joined with a [Reader] or [Writer] base path.
+func expandProjectRoot(path, projectRoot string) string {
+	if rest, ok := strings.CutPrefix(path, "{project-root}"); ok {
+		return filepath.Clean(strings.TrimLeft(rest, `/\`))
+	}
+	return path
+}
+
+// isDir reports whether path exists and is a directory.
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// isFile reports whether path exists and is a regular file.
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
@@ -0,0 +1,98 @@
+package status
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindProjectRoot(t *testing.T) {
+	t.Run("finds directory containing _bmad", func(t *testing.T) {
+		root := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(root, "_bmad"), 0755))
+		nested := filepath.Join(root, "src", "pkg")
+		require.NoError(t, os.MkdirAll(nested, 0755))
+
+		got, err := FindProjectRoot(nested)
+
+		require.NoError(t, err)
+		assert.Equal(t, root, got)
+	})
+
+	t.Run("finds directory containing sprint status", func(t *testing.T) {
+		root := t.TempDir()
+		writeStatusFixture(t, root, "development_status: {}\n", nil)
+
+		got, err := FindProjectRoot(filepath.Join(root, "_bmad-output"))
+
+		require.NoError(t, err)
+		assert.Equal(t, root, got)
+	})
+
+	t.Run("returns ErrProjectNotFound without markers", func(t *testing.T) {
+		_, err := FindProjectRoot(t.TempDir())
+
+		assert.True(t, errors.Is(err, ErrProjectNotFound))
+	})
+}
+
+func TestResolveStatusPath(t *testing.T) {
+	writeBMADConfig := func(t *testing.T, root, content string) {
+		t.Helper()
+		require.NoError(t, os.MkdirAll(filepath.Join(root, "_bmad", "bmm"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, BMADConfigPath), []byte(content), 0644))
+	}
+
+	t.Run("defaults without configuration", func(t *testing.T) {
+		assert.Equal(t, DefaultStatusPath, ResolveStatusPath(t.TempDir(), ""))
+	})
+
+	t.Run("configured path wins over BMAD config", func(t *testing.T) {
+		root := t.TempDir()
+		writeBMADConfig(t, root, "output_folder: \"{project-root}/out\"\n")
+
+		assert.Equal(t, "docs/sprint-status.yaml", ResolveStatusPath(root, "docs/sprint-status.yaml"))
+	})
+
+	t.Run("honours BMAD implementation_artifacts", func(t *testing.T) {
+		root := t.TempDir()
+		writeBMADConfig(t, root, `output_folder: "{project-root}/out"
+implementation_artifacts: "{output_folder}/impl"
+`)
+
+		assert.Equal(t, filepath.Join("out", "impl", "sprint-status.yaml"), ResolveStatusPath(root, ""))
+	})
+
+	t.Run("honours BMAD output_folder", func(t *testing.T) {
+		root := t.TempDir()
+		writeBMADConfig(t, root, "output_folder: \"{project-root}/custom-output\"\n")
+
+		assert.Equal(t, filepath.Join("custom-output", "implementation-artifacts", "sprint-status.yaml"), ResolveStatusPath(root, ""))
+	})
+}
+
+func TestNewReaderWithPath(t *testing.T) {
+	root := t.TempDir()
+	statusPath := filepath.Join("docs", "sprint-status.yaml")
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "docs"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, statusPath), []byte("development_status:\n  1-1-a: backlog\n"), 0644))
+
+	reader := NewReaderWithPath(root, statusPath)
+	assert.Equal(t, filepath.Join(root, statusPath), reader.Path())
+
+	require.NoError(t, NewWriterWithPath(root, statusPath).UpdateStatus("1-1-a", StatusReview))
+
+	s, err := reader.GetStoryStatus("1-1-a")
+	require.NoError(t, err)
+	assert.Equal(t, StatusReview, s)
+
+	// Absolute paths ignore the base path
+	absReader := NewReaderWithPath("/elsewhere", filepath.Join(root, statusPath))
+	s, err = absReader.GetStoryStatus("1-1-a")
+	require.NoError(t, err)
+	assert.Equal(t, StatusReview, s)
+}
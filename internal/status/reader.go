@@ -4,9 +4,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"sort"
-	"strconv"
-	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -19,9 +16,11 @@ const DefaultStatusPath = "_bmad-output/implementation-artifacts/sprint-status.y
 //
 // The basePath field specifies the project root directory. When empty,
 // the current working directory is used. The full path to the status file
-// is constructed as: basePath + DefaultStatusPath.
+// is constructed as: basePath + statusPath, where statusPath defaults to
+// [DefaultStatusPath] (see [NewReaderWithPath]).
 type Reader struct {
-	basePath string
+	basePath   string
+	statusPath string
 }
 
 // NewReader creates a new [Reader] with the specified base path.
@@ -29,19 +28,32 @@ type Reader struct {
 // The basePath is the project root directory. Pass an empty string to use
 // the current working directory.
 func NewReader(basePath string) *Reader {
+	return NewReaderWithPath(basePath, DefaultStatusPath)
+}
+
+// NewReaderWithPath creates a new [Reader] for a status file at a custom location.
+//
+// The statusPath is relative to basePath unless it is absolute. Use
+// [ResolveStatusPath] to honour the status.path config key and BMAD's
+// output folder settings.
+func NewReaderWithPath(basePath, statusPath string) *Reader {
 	return &Reader{
-		basePath: basePath,
+		basePath:   basePath,
+		statusPath: statusPath,
 	}
 }
 
+// Path returns the full path to the sprint status file.
+func (r *Reader) Path() string {
+	return joinStatusPath(r.basePath, r.statusPath)
+}
+
 // Read reads and parses the complete sprint status file.
 //
 // It returns the full [SprintStatus] structure containing all story statuses.
 // Returns an error if the file cannot be read or parsed.
 func (r *Reader) Read() (*SprintStatus, error) {
-	fullPath := filepath.Join(r.basePath, DefaultStatusPath)
-
-	data, err := os.ReadFile(fullPath)
+	data, err := os.ReadFile(r.Path())
 	if err != nil {
 		return nil, fmt.Errorf("failed to read sprint status: %w", err)
 	}
@@ -72,120 +84,102 @@ func (r *Reader) GetStoryStatus(storyKey string) (Status, error) {
 	return status, nil
 }
 
-// GetEpicStories returns all story keys belonging to an epic, sorted by story number.
+// GetEpicStories returns all story keys belonging to an epic in execution order.
 //
-// Story keys are matched using the pattern {epicID}-{N}-*, where N is a numeric
-// story number. Results are sorted numerically by story number (1, 2, 10 not 1, 10, 2).
+// Only story entries are considered: epic ("epic-6") and retrospective
+// ("epic-6-retrospective") entries are ignored. Story keys are matched using the
+// pattern {epicID}-{N}-*, where N is a numeric story number. Results are sorted
+// numerically by story number (1, 2, 10 not 1, 10, 2), then reordered so that
+// each story follows its declared dependencies (see [Reader.GetStoryDependencies]
+// and [OrderStories]).
 //
-// Returns an error if the file cannot be read or if no stories are found for the epic.
+// Returns an error if the file cannot be read, if no stories are found for the epic,
+// or if the epic's dependencies form a cycle.
 func (r *Reader) GetEpicStories(epicID string) ([]string, error) {
 	sprintStatus, err := r.Read()
 	if err != nil {
 		return nil, err
 	}
 
-	// Collect all keys matching the epic ID pattern
-	type storyWithNum struct {
-		key string
-		num int
+	epic := sprintStatus.Epic(epicID)
+	if epic == nil || len(epic.Stories) == 0 {
+		return nil, fmt.Errorf("no stories found for epic: %s", epicID)
 	}
-	var stories []storyWithNum
 
-	prefix := epicID + "-"
-	for key := range sprintStatus.DevelopmentStatus {
-		if !strings.HasPrefix(key, prefix) {
-			continue
-		}
+	// Stories are already sorted by story number
+	keys := make([]string, len(epic.Stories))
+	deps := make(map[string][]string, len(epic.Stories))
+	for i, s := range epic.Stories {
+		keys[i] = s.Key
+		deps[s.Key] = r.dependencies(sprintStatus, s.Key)
+	}
 
-		// Extract the story number (second segment)
-		// Format: {epicID}-{storyNum}-{rest}
-		remainder := strings.TrimPrefix(key, prefix)
-		parts := strings.SplitN(remainder, "-", 2)
-		if len(parts) < 1 {
-			continue
-		}
+	// Reorder so dependencies run first
+	result, err := OrderStories(keys, deps)
+	if err != nil {
+		return nil, fmt.Errorf("failed to order stories for epic %s: %w", epicID, err)
+	}
 
-		num, err := strconv.Atoi(parts[0])
-		if err != nil {
-			// Not a numeric story number, skip
-			continue
-		}
+	return result, nil
+}
 
-		stories = append(stories, storyWithNum{key: key, num: num})
+// StoryFilePath returns the path of a story's markdown file.
+//
+// Story files are named {storyKey}.md and live in the story_location
+// directory, or next to sprint-status.yaml when story_location is not set.
+// The file may not exist yet. Returns an error if the status file cannot be read.
+func (r *Reader) StoryFilePath(storyKey string) (string, error) {
+	sprintStatus, err := r.Read()
+	if err != nil {
+		return "", err
 	}
 
-	if len(stories) == 0 {
-		return nil, fmt.Errorf("no stories found for epic: %s", epicID)
-	}
+	return filepath.Join(storyDir(r.basePath, r.Path(), sprintStatus), storyKey+".md"), nil
+}
 
-	// Sort by story number
-	sort.Slice(stories, func(i, j int) bool {
-		return stories[i].num < stories[j].num
-	})
+// GetEpic returns the typed [Epic] with its stories and retrospective.
+//
+// Returns an error if the file cannot be read or if no entry references the epic.
+func (r *Reader) GetEpic(epicID string) (*Epic, error) {
+	sprintStatus, err := r.Read()
+	if err != nil {
+		return nil, err
+	}
 
-	// Extract just the keys
-	result := make([]string, len(stories))
-	for i, s := range stories {
-		result[i] = s.key
+	epic := sprintStatus.Epic(epicID)
+	if epic == nil {
+		return nil, fmt.Errorf("epic not found: %s", epicID)
 	}
 
-	return result, nil
+	return epic, nil
 }
 
 // GetAllEpics returns all epic IDs with active status, sorted numerically.
 //
-// An epic is considered "active" if its status is not "done", "deferred", or "optional".
-// Epic IDs are extracted from story keys (format: {epicID}-{storyNum}-{description}).
-// Results are sorted numerically by epic ID.
+// An epic is active if it has at least one story and its epic-N entry, when
+// present, is not done. Epic IDs come from both epic entries and story keys;
+// metadata and retrospective entries never produce epic IDs.
 func (r *Reader) GetAllEpics() ([]string, error) {
 	sprintStatus, err := r.Read()
 	if err != nil {
 		return nil, err
 	}
 
-	// Collect unique epic IDs with their numeric value for sorting
-	type epicInfo struct {
-		id  string
-		num int
-	}
-
-	epicMap := make(map[string]int)
-
-	for key := range sprintStatus.DevelopmentStatus {
-		// Extract epic ID from story key (format: {epicID}-{storyNum}-{description})
-		parts := strings.SplitN(key, "-", 2)
-		if len(parts) < 1 {
+	result := []string{}
+	for _, epic := range sprintStatus.Epics() {
+		if len(epic.Stories) == 0 || epic.Status == StatusDone {
 			continue
 		}
-
-		epicID := parts[0]
-
-		// Check if this epic is active
-		// For now, we return all epics - the caller can filter by status if needed
-		epicMap[epicID] = 0
+		result = append(result, epic.ID)
 	}
 
-	if len(epicMap) == 0 {
-		return []string{}, nil
-	}
-
-	// Convert to slice and parse numeric values for sorting
-	epics := make([]epicInfo, 0, len(epicMap))
-	for id := range epicMap {
-		num, _ := strconv.Atoi(id)
-		epics = append(epics, epicInfo{id: id, num: num})
-	}
-
-	// Sort by numeric value
-	sort.Slice(epics, func(i, j int) bool {
-		return epics[i].num < epics[j].num
-	})
+	return result, nil
+}
 
-	// Extract just the IDs
-	result := make([]string, len(epics))
-	for i, e := range epics {
-		result[i] = e.id
+// joinStatusPath joins a status file path to a base path unless it is absolute.
+func joinStatusPath(basePath, statusPath string) string {
+	if filepath.IsAbs(statusPath) {
+		return statusPath
 	}
-
-	return result, nil
+	return filepath.Join(basePath, statusPath)
 }
@@ -0,0 +1,253 @@
+package status
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EntryKind classifies a key in the development_status map.
+//
+// BMAD sprint-status files mix three kinds of entries in one map: epics
+// ("epic-6"), stories ("6-2-add-api") and retrospectives ("epic-6-retrospective").
+// Use [ParseKey] to classify a key.
+type EntryKind int
+
+const (
+	// EntryStory is a story entry such as "6-2-add-api".
+	// Keys that match no other pattern are also treated as stories.
+	EntryStory EntryKind = iota
+
+	// EntryEpic is an epic entry such as "epic-6".
+	EntryEpic
+
+	// EntryRetrospective is an epic retrospective entry such as "epic-6-retrospective".
+	EntryRetrospective
+)
+
+// String returns the lowercase name of the entry kind.
+func (k EntryKind) String() string {
+	switch k {
+	case EntryEpic:
+		return "epic"
+	case EntryRetrospective:
+		return "retrospective"
+	default:
+		return "story"
+	}
+}
+
+var (
+	// epicKeyPattern matches epic entries: epic-{epicID}
+	epicKeyPattern = regexp.MustCompile(`^epic-([^-]+)$`)
+
+	// retrospectiveKeyPattern matches retrospective entries: epic-{epicID}-retrospective
+	retrospectiveKeyPattern = regexp.MustCompile(`^epic-([^-]+)-retrospective$`)
+
+	// storyKeyPattern matches story entries: {epicID}-{storyNum}[-{description}]
+	storyKeyPattern = regexp.MustCompile(`^([^-]+)-(\d+)(?:-|$)`)
+)
+
+// ParseKey classifies a development_status key.
+//
+// It returns the entry kind, the epic ID the entry belongs to, and for stories
+// the numeric story number. Story keys that do not follow the
+// {epicID}-{storyNum}-* pattern return an empty epic ID and story number 0.
+func ParseKey(key string) (kind EntryKind, epicID string, storyNum int) {
+	if m := retrospectiveKeyPattern.FindStringSubmatch(key); m != nil {
+		return EntryRetrospective, m[1], 0
+	}
+	if m := epicKeyPattern.FindStringSubmatch(key); m != nil {
+		return EntryEpic, m[1], 0
+	}
+	// Keys starting with epic- are never stories, e.g. a malformed "epic-6-x"
+	if m := storyKeyPattern.FindStringSubmatch(key); m != nil && m[1] != "epic" {
+		num, _ := strconv.Atoi(m[2])
+		return EntryStory, m[1], num
+	}
+	return EntryStory, "", 0
+}
+
+// EpicKey returns the development_status key for an epic entry (e.g., "epic-6").
+func EpicKey(epicID string) string {
+	return "epic-" + epicID
+}
+
+// RetrospectiveKey returns the development_status key for an epic's
+// retrospective entry (e.g., "epic-6-retrospective").
+func RetrospectiveKey(epicID string) string {
+	return "epic-" + epicID + "-retrospective"
+}
+
+// Story is a typed story entry from sprint-status.yaml.
+type Story struct {
+	// Key is the full story key (e.g., "6-2-add-api").
+	Key string
+
+	// EpicID is the epic the story belongs to (e.g., "6").
+	EpicID string
+
+	// Number is the numeric story number within the epic.
+	Number int
+
+	// Status is the story's current development status.
+	Status Status
+}
+
+// Retrospective is a typed epic retrospective entry from sprint-status.yaml.
+type Retrospective struct {
+	// Key is the retrospective key (e.g., "epic-6-retrospective").
+	Key string
+
+	// EpicID is the epic the retrospective belongs to.
+	EpicID string
+
+	// Status is the retrospective status: optional or done.
+	Status Status
+}
+
+// Epic is a typed epic with its stories and optional retrospective.
+//
+// Epics are assembled from explicit epic-N entries and from the epic IDs of
+// story keys, so an epic without its own entry still appears with an empty
+// Status.
+type Epic struct {
+	// ID is the epic identifier (e.g., "6").
+	ID string
+
+	// Status is the value of the epic-N entry, or empty if the file has none.
+	Status Status
+
+	// Stories lists the epic's stories sorted by story number.
+	Stories []Story
+
+	// Retrospective is the epic's retrospective entry, or nil if the file has none.
+	Retrospective *Retrospective
+}
+
+// IsComplete reports whether the epic has stories and all of them are done.
+func (e Epic) IsComplete() bool {
+	if len(e.Stories) == 0 {
+		return false
+	}
+	for _, s := range e.Stories {
+		if s.Status != StatusDone {
+			return false
+		}
+	}
+	return true
+}
+
+// Epics returns all epics in the sprint status, sorted numerically by ID.
+func (s *SprintStatus) Epics() []Epic {
+	byID := make(map[string]*Epic)
+	get := func(id string) *Epic {
+		if e, ok := byID[id]; ok {
+			return e
+		}
+		e := &Epic{ID: id}
+		byID[id] = e
+		return e
+	}
+
+	for key, value := range s.DevelopmentStatus {
+		kind, epicID, num := ParseKey(key)
+		if epicID == "" {
+			continue
+		}
+		switch kind {
+		case EntryEpic:
+			get(epicID).Status = value
+		case EntryRetrospective:
+			get(epicID).Retrospective = &Retrospective{Key: key, EpicID: epicID, Status: value}
+		default:
+			e := get(epicID)
+			e.Stories = append(e.Stories, Story{Key: key, EpicID: epicID, Number: num, Status: value})
+		}
+	}
+
+	epics := make([]Epic, 0, len(byID))
+	for _, e := range byID {
+		sort.Slice(e.Stories, func(i, j int) bool {
+			return e.Stories[i].Number < e.Stories[j].Number
+		})
+		epics = append(epics, *e)
+	}
+	sort.Slice(epics, func(i, j int) bool {
+		ni, erri := strconv.Atoi(epics[i].ID)
+		nj, errj := strconv.Atoi(epics[j].ID)
+		if erri == nil && errj == nil {
+			return ni < nj
+		}
+		return epics[i].ID < epics[j].ID
+	})
+
+	return epics
+}
+
+// Epic returns the epic with the given ID, or nil if no entry references it.
+func (s *SprintStatus) Epic(epicID string) *Epic {
+	for _, e := range s.Epics() {
+		if e.ID == epicID {
+			return &e
+		}
+	}
+	return nil
+}
+
+// DeriveEpicStatus computes an epic's status from its stories' statuses.
+//
+// Returns done when every story is done, in-progress when any story has
+// started, and backlog otherwise (including when there are no stories).
+func DeriveEpicStatus(storyStatuses []Status) Status {
+	if len(storyStatuses) == 0 {
+		return StatusBacklog
+	}
+
+	allDone := true
+	started := false
+	for _, s := range storyStatuses {
+		if s != StatusDone {
+			allDone = false
+		}
+		if s != StatusBacklog {
+			started = true
+		}
+	}
+
+	switch {
+	case allDone:
+		return StatusDone
+	case started:
+		return StatusInProgress
+	default:
+		return StatusBacklog
+	}
+}
+
+// storyDir returns the directory containing story markdown files.
+//
+// It honours the story_location metadata key, expanding BMAD's
+// "{project-root}" placeholder to basePath. Relative locations are resolved
+// against basePath. Without story_location, stories live next to the status
+// file at statusFile.
+func storyDir(basePath, statusFile string, sprintStatus *SprintStatus) string {
+	location := sprintStatus.StoryLocation
+	if location == "" {
+		return filepath.Dir(statusFile)
+	}
+
+	root := basePath
+	if root == "" {
+		root = "."
+	}
+	if strings.Contains(location, "{project-root}") {
+		return filepath.Clean(strings.ReplaceAll(location, "{project-root}", root))
+	}
+	if filepath.IsAbs(location) {
+		return location
+	}
+	return filepath.Join(basePath, location)
+}
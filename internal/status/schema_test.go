@@ -0,0 +1,150 @@
+package status
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bmadSprintStatus is a sprint-status.yaml in the full BMAD schema.
+const bmadSprintStatus = `# generated: 2026-01-15
+generated: 2026-01-15
+project: demo
+project_key: DEMO
+tracking_system: file-system
+story_location: "{project-root}/_bmad-output/implementation-artifacts"
+
+development_status:
+  epic-6: in-progress
+  6-1-define-schema: done
+  6-2-create-api: review
+  6-10-build-ui: backlog
+  epic-6-retrospective: optional
+
+  epic-7: done
+  7-1-setup: done
+  epic-7-retrospective: done
+
+  epic-8: backlog
+`
+
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		key      string
+		kind     EntryKind
+		epicID   string
+		storyNum int
+	}{
+		{"epic-6", EntryEpic, "6", 0},
+		{"epic-6-retrospective", EntryRetrospective, "6", 0},
+		{"6-2-create-api", EntryStory, "6", 2},
+		{"6-10", EntryStory, "6", 10},
+		{"test-story", EntryStory, "", 0},
+		{"epic-6-x", EntryStory, "", 0},
+		{"epic-6-2", EntryStory, "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			kind, epicID, storyNum := ParseKey(tt.key)
+			assert.Equal(t, tt.kind, kind)
+			assert.Equal(t, tt.epicID, epicID)
+			assert.Equal(t, tt.storyNum, storyNum)
+		})
+	}
+}
+
+func TestStatus_IsValidFor(t *testing.T) {
+	assert.True(t, StatusReview.IsValidFor(EntryStory))
+	assert.False(t, StatusOptional.IsValidFor(EntryStory))
+
+	assert.True(t, StatusInProgress.IsValidFor(EntryEpic))
+	assert.False(t, StatusReview.IsValidFor(EntryEpic))
+
+	assert.True(t, StatusOptional.IsValidFor(EntryRetrospective))
+	assert.True(t, StatusDone.IsValidFor(EntryRetrospective))
+	assert.False(t, StatusBacklog.IsValidFor(EntryRetrospective))
+}
+
+func TestDeriveEpicStatus(t *testing.T) {
+	assert.Equal(t, StatusBacklog, DeriveEpicStatus(nil))
+	assert.Equal(t, StatusBacklog, DeriveEpicStatus([]Status{StatusBacklog, StatusBacklog}))
+	assert.Equal(t, StatusInProgress, DeriveEpicStatus([]Status{StatusDone, StatusBacklog}))
+	assert.Equal(t, StatusInProgress, DeriveEpicStatus([]Status{StatusReadyForDev}))
+	assert.Equal(t, StatusDone, DeriveEpicStatus([]Status{StatusDone, StatusDone}))
+}
+
+func TestSprintStatus_Epics(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeStatusFixture(t, tmpDir, bmadSprintStatus, nil)
+
+	sprint, err := NewReader(tmpDir).Read()
+	require.NoError(t, err)
+
+	assert.Equal(t, "2026-01-15", sprint.Generated)
+	assert.Equal(t, "demo", sprint.Project)
+	assert.Equal(t, "DEMO", sprint.ProjectKey)
+	assert.Equal(t, "file-system", sprint.TrackingSystem)
+
+	epics := sprint.Epics()
+	require.Len(t, epics, 3)
+
+	assert.Equal(t, "6", epics[0].ID)
+	assert.Equal(t, StatusInProgress, epics[0].Status)
+	require.Len(t, epics[0].Stories, 3)
+	assert.Equal(t, "6-1-define-schema", epics[0].Stories[0].Key)
+	assert.Equal(t, "6-10-build-ui", epics[0].Stories[2].Key)
+	require.NotNil(t, epics[0].Retrospective)
+	assert.Equal(t, StatusOptional, epics[0].Retrospective.Status)
+	assert.False(t, epics[0].IsComplete())
+
+	assert.Equal(t, "7", epics[1].ID)
+	assert.True(t, epics[1].IsComplete())
+
+	assert.Equal(t, "8", epics[2].ID)
+	assert.Empty(t, epics[2].Stories)
+	assert.Nil(t, epics[2].Retrospective)
+}
+
+func TestReader_FullSchema(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeStatusFixture(t, tmpDir, bmadSprintStatus, nil)
+	reader := NewReader(tmpDir)
+
+	stories, err := reader.GetEpicStories("6")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"6-1-define-schema", "6-2-create-api", "6-10-build-ui"}, stories)
+
+	// Epic 7 is done and epic 8 has no stories
+	epics, err := reader.GetAllEpics()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"6"}, epics)
+
+	epic, err := reader.GetEpic("7")
+	require.NoError(t, err)
+	assert.Equal(t, StatusDone, epic.Status)
+
+	_, err = reader.GetEpic("9")
+	assert.Error(t, err)
+}
+
+func TestReader_StoryLocation(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeStatusFixture(t, tmpDir, `story_location: "{project-root}/docs/stories"
+development_status:
+  6-1-schema: done
+  6-2-api: backlog
+`, nil)
+
+	storiesDir := filepath.Join(tmpDir, "docs", "stories")
+	require.NoError(t, os.MkdirAll(storiesDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(storiesDir, "6-2-api.md"), []byte("Depends on: 6-1\n"), 0644))
+
+	deps, err := NewReader(tmpDir).GetStoryDependencies("6-2-api")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"6-1-schema"}, deps)
+}
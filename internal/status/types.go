@@ -7,11 +7,13 @@
 // Key types:
 //   - [Status] - Story development status enum with validation
 //   - [SprintStatus] - Parsed representation of sprint-status.yaml
+//   - [Epic], [Story], [Retrospective] - Typed views of development_status entries
 //   - [Reader] - Reads and queries sprint status from YAML files
 //   - [Writer] - Updates status values while preserving YAML formatting
 //
-// The package uses yaml.v3's Node API for writes to preserve comments, ordering,
-// and formatting in the status file.
+// Writes locate values with yaml.v3's Node API and then change only the bytes
+// of those values, so comments, ordering, and formatting in the status file
+// are preserved exactly.
 package status
 
 // Status represents a story's development status in the workflow lifecycle.
@@ -44,6 +46,10 @@ const (
 	// StatusDone indicates a story has completed all workflow steps.
 	// Stories marked done are skipped in queue and epic operations.
 	StatusDone Status = "done"
+
+	// StatusOptional indicates an epic retrospective that has not been run.
+	// It is only valid for retrospective entries (epic-N-retrospective).
+	StatusOptional Status = "optional"
 )
 
 // IsValid reports whether the status is one of the known valid status values.
@@ -57,12 +63,55 @@ func (s Status) IsValid() bool {
 	}
 }
 
+// IsValidFor reports whether the status is valid for the given entry kind.
+//
+// Stories accept the five story statuses (see [Status.IsValid]). Epics accept
+// backlog, in-progress and done. Retrospectives accept optional and done.
+func (s Status) IsValidFor(kind EntryKind) bool {
+	switch kind {
+	case EntryEpic:
+		return s == StatusBacklog || s == StatusInProgress || s == StatusDone
+	case EntryRetrospective:
+		return s == StatusOptional || s == StatusDone
+	default:
+		return s.IsValid()
+	}
+}
+
 // SprintStatus represents the parsed contents of a sprint-status.yaml file.
 //
-// The file structure contains a development_status map where keys are story
-// identifiers (e.g., "7-1-define-schema") and values are their current [Status].
+// The file structure contains a development_status map where keys are epic,
+// story and retrospective identifiers (e.g., "epic-7", "7-1-define-schema",
+// "epic-7-retrospective") and values are their current [Status], plus an
+// optional dependencies map declaring story ordering constraints. Use
+// [SprintStatus.Epics] for a typed view of the entries.
+//
+// BMAD also writes metadata keys at the top level. They are exposed read-only
+// here; [Writer] leaves them untouched.
 type SprintStatus struct {
+	// Generated is the timestamp at which BMAD generated the file.
+	Generated string `yaml:"generated"`
+
+	// Project is the project name.
+	Project string `yaml:"project"`
+
+	// ProjectKey is the project key used by the tracking system.
+	ProjectKey string `yaml:"project_key"`
+
+	// TrackingSystem names the tracking backend (e.g., "file-system").
+	TrackingSystem string `yaml:"tracking_system"`
+
+	// StoryLocation is the directory holding story markdown files. It may
+	// contain BMAD's "{project-root}" placeholder.
+	StoryLocation string `yaml:"story_location"`
+
 	// DevelopmentStatus maps story keys to their current development status.
 	// Story keys follow the pattern: {epicID}-{storyNum}-{description}.
 	DevelopmentStatus map[string]Status `yaml:"development_status"`
+
+	// Dependencies maps story keys to the stories they depend on.
+	// Both keys and values may be full story keys or short references
+	// such as "6-2". Dependencies may also be declared in story files;
+	// see [Reader.GetStoryDependencies].
+	Dependencies map[string][]string `yaml:"dependencies"`
 }
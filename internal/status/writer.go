@@ -1,103 +1,336 @@
 package status
 
 import (
+	"crypto/sha256"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
 // Writer writes sprint status updates to YAML files at [DefaultStatusPath].
 //
-// It uses yaml.v3's Node API to preserve comments, ordering, and formatting
-// when updating status values. Writes are performed atomically using a
-// temporary file and rename pattern to prevent corruption.
+// It uses yaml.v3's Node API only to locate values, then rewrites just the
+// bytes of the changed values, so comments, indentation, quoting, anchors and
+// line endings elsewhere in the file are left byte-for-byte intact. Writes are
+// performed atomically using a uniquely named temporary file and rename
+// pattern to prevent corruption.
+//
+// Concurrent writers are serialized with an advisory lock on a sidecar
+// "sprint-status.yaml.lock" file. Edits made by processes that do not take
+// the lock (such as the agent editing the file directly) are detected by
+// comparing the file's hash before the rename; the update is then reapplied
+// to the new contents, and [ErrConcurrentModification] is returned if the
+// file keeps changing.
 type Writer struct {
-	basePath string
+	basePath    string
+	statusPath  string
+	lockTimeout time.Duration
+	runID       string
+
+	// gitHead returns the checked-out commit for history records.
+	gitHead func(dir string) string
+
+	// beforeRename is a test hook invoked after the temp file is written.
+	beforeRename func()
 }
 
+// DefaultLockTimeout is how long [Writer.UpdateStatus] waits for another
+// process to release the sprint status lock.
+const DefaultLockTimeout = 30 * time.Second
+
+// maxWriteAttempts is how many times an update is reapplied after detecting
+// a concurrent external modification.
+const maxWriteAttempts = 3
+
+// ErrConcurrentModification is returned when sprint-status.yaml keeps changing
+// on disk between read and write, so the update could not be applied safely.
+var ErrConcurrentModification = errors.New("sprint status was modified concurrently")
+
 // NewWriter creates a new [Writer] with the specified base path.
 //
 // The basePath is the project root directory. Pass an empty string to use
 // the current working directory.
 func NewWriter(basePath string) *Writer {
+	return NewWriterWithPath(basePath, DefaultStatusPath)
+}
+
+// NewWriterWithPath creates a new [Writer] for a status file at a custom location.
+//
+// The statusPath is relative to basePath unless it is absolute. See
+// [NewReaderWithPath].
+func NewWriterWithPath(basePath, statusPath string) *Writer {
 	return &Writer{
-		basePath: basePath,
+		basePath:    basePath,
+		statusPath:  statusPath,
+		lockTimeout: DefaultLockTimeout,
+		gitHead:     currentGitHead,
 	}
 }
 
-// UpdateStatus atomically updates the [Status] for a specific story key.
+// SetLockTimeout sets how long to wait for the sprint status lock.
+func (w *Writer) SetLockTimeout(timeout time.Duration) {
+	w.lockTimeout = timeout
+}
+
+// SetRunID sets the run identifier recorded with each status transition.
+// See [NewRunID].
+func (w *Writer) SetRunID(runID string) {
+	w.runID = runID
+}
+
+// UpdateStatus atomically updates the [Status] for a specific development_status key.
+//
+// The key may be a story, epic or retrospective entry (see [ParseKey]); the
+// status is validated against the entry kind with [Status.IsValidFor].
 //
 // The update process:
-//  1. Validates that newStatus is a known valid status
-//  2. Reads the existing file into a yaml.Node tree (preserves formatting)
-//  3. Locates and updates the story's status value
-//  4. Writes to a temporary file, then renames for atomic update
+//  1. Validates that newStatus is valid for the key's entry kind
+//  2. Acquires the cross-process lock on the sidecar lock file
+//  3. Reads the existing file into a yaml.Node tree to locate values
+//  4. Replaces only the bytes of the entry's status value
+//  5. For stories, syncs the owning epic-N entry (see [DeriveEpicStatus])
+//  6. Writes to a unique temporary file, verifies the original is unchanged,
+//     then renames for atomic update (retrying on concurrent modification)
+//
+// Metadata keys such as project and story_location are left untouched.
+// Each changed entry is appended to the history log (see [HistoryPath]) as a
+// [Transition]. Entries that already have the new status are not rewritten
+// or recorded, so setting a story to its current status changes nothing.
 //
 // Returns an error if the status is invalid, the file cannot be read/written,
-// or the story key is not found.
+// the key is not found, the lock cannot be acquired ([ErrLockTimeout]), or the
+// file keeps changing underneath the writer ([ErrConcurrentModification]).
 func (w *Writer) UpdateStatus(storyKey string, newStatus Status) error {
+	return w.UpdateStatusForWorkflow(storyKey, newStatus, "", "")
+}
+
+// UpdateStatusForWorkflow is [Writer.UpdateStatus] for an update caused by a
+// workflow; the workflow name and the model it ran on, if known, are
+// recorded in the status history.
+func (w *Writer) UpdateStatusForWorkflow(storyKey string, newStatus Status, workflow, model string) error {
 	// Validate the new status
-	if !newStatus.IsValid() {
+	kind, epicID, _ := ParseKey(storyKey)
+	if !newStatus.IsValidFor(kind) {
+		return fmt.Errorf("invalid status: %s", newStatus)
+	}
+
+	fullPath := joinStatusPath(w.basePath, w.statusPath)
+	if _, err := os.Stat(fullPath); err != nil {
+		return fmt.Errorf("failed to read sprint status: %w", err)
+	}
+
+	// Serialize with other bmaduum processes
+	lock, err := acquireLock(LockPath(fullPath), w.lockTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to lock sprint status: %w", err)
+	}
+	defer lock.release() //nolint:errcheck // Lock is released on close regardless
+
+	var edits []valueEdit
+	for attempt := 1; ; attempt++ {
+		edits, err = w.updateOnce(fullPath, storyKey, newStatus, kind, epicID)
+		if !errors.Is(err, ErrConcurrentModification) || attempt >= maxWriteAttempts {
+			break
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	// Record the transitions while still holding the lock
+	now := time.Now().UTC()
+	head := w.gitHead(w.basePath)
+	transitions := make([]Transition, 0, len(edits))
+	for _, edit := range edits {
+		transitions = append(transitions, Transition{
+			Key:      edit.key,
+			From:     Status(scalarValue(edit.node)),
+			To:       Status(edit.value),
+			Time:     now,
+			Workflow: workflow,
+			Model:    model,
+			RunID:    w.runID,
+			GitHead:  head,
+		})
+	}
+	if len(transitions) == 0 {
+		return nil
+	}
+	if err := appendHistory(HistoryPath(fullPath), transitions); err != nil {
+		return fmt.Errorf("failed to record status history: %w", err)
+	}
+
+	return nil
+}
+
+// RecordTransition appends a transition of key to newStatus, caused by
+// workflow, to the history log without editing the status file. It is for
+// entries the status file does not list, such as the retrospective of an
+// epic without an epic-N-retrospective entry; the transition has no From
+// status.
+//
+// Returns an error if the status is invalid for the key, the status file
+// does not exist, or the lock cannot be acquired ([ErrLockTimeout]).
+func (w *Writer) RecordTransition(key string, newStatus Status, workflow string) error {
+	kind, _, _ := ParseKey(key)
+	if !newStatus.IsValidFor(kind) {
 		return fmt.Errorf("invalid status: %s", newStatus)
 	}
 
-	fullPath := filepath.Join(w.basePath, DefaultStatusPath)
+	fullPath := joinStatusPath(w.basePath, w.statusPath)
+	if _, err := os.Stat(fullPath); err != nil {
+		return fmt.Errorf("failed to read sprint status: %w", err)
+	}
+
+	lock, err := acquireLock(LockPath(fullPath), w.lockTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to lock sprint status: %w", err)
+	}
+	defer lock.release() //nolint:errcheck // Lock is released on close regardless
+
+	transition := Transition{
+		Key:      key,
+		To:       newStatus,
+		Time:     time.Now().UTC(),
+		Workflow: workflow,
+		RunID:    w.runID,
+		GitHead:  w.gitHead(w.basePath),
+	}
+	if err := appendHistory(HistoryPath(fullPath), []Transition{transition}); err != nil {
+		return fmt.Errorf("failed to record status history: %w", err)
+	}
+	return nil
+}
 
+// updateOnce performs a single read-modify-write of the status file.
+//
+// It returns the applied edits, whose nodes still hold the previous values.
+// Entries that already have their new value are not edited; when nothing
+// changes, the file is not written and no edits are returned.
+// Returns [ErrConcurrentModification] if the file changed after it was read.
+func (w *Writer) updateOnce(fullPath, storyKey string, newStatus Status, kind EntryKind, epicID string) ([]valueEdit, error) {
 	// Read existing file
 	data, err := os.ReadFile(fullPath)
 	if err != nil {
-		return fmt.Errorf("failed to read sprint status: %w", err)
+		return nil, fmt.Errorf("failed to read sprint status: %w", err)
 	}
+	originalHash := sha256.Sum256(data)
 
-	// Parse YAML into a Node tree to preserve formatting
+	// Parse YAML into a Node tree to locate the values to change
 	var doc yaml.Node
 	if err := yaml.Unmarshal(data, &doc); err != nil {
-		return fmt.Errorf("failed to parse sprint status: %w", err)
+		return nil, fmt.Errorf("failed to parse sprint status: %w", err)
 	}
 
-	// Find and update the story status in the node tree
-	if err := updateStoryStatusInNode(&doc, storyKey, newStatus); err != nil {
-		return err
+	// Find the entry's value node
+	devStatusNode, edit, err := storyStatusEdit(&doc, storyKey, newStatus)
+	if err != nil {
+		return nil, err
+	}
+	var edits []valueEdit
+	if scalarValue(edit.node) != edit.value {
+		edits = append(edits, edit)
+	}
+
+	// Keep the epic entry in step with its stories
+	if kind == EntryStory && epicID != "" {
+		if epicEdit, ok := epicStatusEdit(devStatusNode, epicID, storyKey, newStatus); ok {
+			edits = append(edits, epicEdit)
+		}
+	}
+
+	// Nothing changes, so there is nothing to write or record
+	if len(edits) == 0 {
+		return nil, nil
+	}
+
+	// Rewrite only the bytes of the changed values
+	updatedData, err := applyEdits(data, edits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update sprint status: %w", err)
 	}
 
-	// Marshal the node tree back to YAML (preserves formatting)
-	updatedData, err := yaml.Marshal(&doc)
+	// Write to a uniquely named temp file in the same directory
+	tmpPath, err := writeTempFile(fullPath, updatedData)
 	if err != nil {
-		return fmt.Errorf("failed to marshal sprint status: %w", err)
+		return nil, fmt.Errorf("failed to write sprint status: %w", err)
+	}
+
+	if w.beforeRename != nil {
+		w.beforeRename()
 	}
 
-	// Write back to file atomically (write to temp, then rename)
-	tmpPath := fullPath + ".tmp"
-	if err := os.WriteFile(tmpPath, updatedData, 0644); err != nil {
-		return fmt.Errorf("failed to write sprint status: %w", err)
+	// Detect edits made since the read by processes that ignore the lock
+	current, err := os.ReadFile(fullPath)
+	if err != nil || sha256.Sum256(current) != originalHash {
+		os.Remove(tmpPath)
+		return nil, fmt.Errorf("%w: %s", ErrConcurrentModification, fullPath)
 	}
 
 	if err := os.Rename(tmpPath, fullPath); err != nil {
 		// Clean up temp file on rename failure
 		os.Remove(tmpPath)
-		return fmt.Errorf("failed to write sprint status: %w", err)
+		return nil, fmt.Errorf("failed to write sprint status: %w", err)
 	}
 
-	return nil
+	return edits, nil
+}
+
+// writeTempFile writes data to a unique temporary file next to path.
+//
+// The temp file takes the original file's permissions so the rename does not
+// change them. Returns the temp file path.
+func writeTempFile(path string, data []byte) (string, error) {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return "", err
+	}
+	if err := os.Chmod(tmpPath, mode); err != nil {
+		os.Remove(tmpPath)
+		return "", err
+	}
+
+	return tmpPath, nil
 }
 
-// updateStoryStatusInNode finds and updates a story's status within a yaml.Node tree.
-func updateStoryStatusInNode(doc *yaml.Node, storyKey string, newStatus Status) error {
+// storyStatusEdit finds the value node of a development_status entry.
+//
+// It returns the development_status mapping node so callers can look up
+// related entries, and the edit that sets the entry to newStatus.
+func storyStatusEdit(doc *yaml.Node, storyKey string, newStatus Status) (*yaml.Node, valueEdit, error) {
 	// Document node contains the root content node
 	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
-		return fmt.Errorf("invalid YAML document structure")
+		return nil, valueEdit{}, fmt.Errorf("invalid YAML document structure")
 	}
 
 	root := doc.Content[0]
 	if root.Kind != yaml.MappingNode {
-		return fmt.Errorf("expected mapping at root level")
+		return nil, valueEdit{}, fmt.Errorf("expected mapping at root level")
 	}
 
 	// Find development_status key in root mapping
 	var devStatusNode *yaml.Node
-	for i := 0; i < len(root.Content); i += 2 {
+	for i := 0; i+1 < len(root.Content); i += 2 {
 		keyNode := root.Content[i]
 		if keyNode.Value == "development_status" {
 			devStatusNode = root.Content[i+1]
@@ -106,23 +339,58 @@ func updateStoryStatusInNode(doc *yaml.Node, storyKey string, newStatus Status)
 	}
 
 	if devStatusNode == nil {
-		return fmt.Errorf("development_status not found in file")
+		return nil, valueEdit{}, fmt.Errorf("development_status not found in file")
 	}
 
 	if devStatusNode.Kind != yaml.MappingNode {
-		return fmt.Errorf("development_status is not a mapping")
+		return nil, valueEdit{}, fmt.Errorf("development_status is not a mapping")
 	}
 
 	// Find the story key within development_status
-	for i := 0; i < len(devStatusNode.Content); i += 2 {
+	for i := 0; i+1 < len(devStatusNode.Content); i += 2 {
 		keyNode := devStatusNode.Content[i]
 		if keyNode.Value == storyKey {
-			// Update the value node
-			valueNode := devStatusNode.Content[i+1]
-			valueNode.Value = string(newStatus)
-			return nil
+			return devStatusNode, valueEdit{key: storyKey, node: devStatusNode.Content[i+1], value: string(newStatus)}, nil
 		}
 	}
 
-	return fmt.Errorf("story not found: %s", storyKey)
+	return nil, valueEdit{}, fmt.Errorf("story not found: %s", storyKey)
+}
+
+// epicStatusEdit returns the edit that syncs an existing epic-N entry with its
+// stories' statuses, given that storyKey is about to become newStatus.
+//
+// The epic moves to in-progress once any story has started and to done once
+// every story is done. An epic whose stories are all in backlog is left as is,
+// and files without an epic-N entry are not modified. Returns false when no
+// edit is needed.
+func epicStatusEdit(devStatusNode *yaml.Node, epicID, storyKey string, newStatus Status) (valueEdit, bool) {
+	var epicValue *yaml.Node
+	var storyStatuses []Status
+
+	for i := 0; i+1 < len(devStatusNode.Content); i += 2 {
+		key := devStatusNode.Content[i].Value
+		if key == EpicKey(epicID) {
+			epicValue = devStatusNode.Content[i+1]
+			continue
+		}
+		if kind, id, _ := ParseKey(key); kind == EntryStory && id == epicID {
+			if key == storyKey {
+				storyStatuses = append(storyStatuses, newStatus)
+			} else {
+				storyStatuses = append(storyStatuses, Status(scalarValue(devStatusNode.Content[i+1])))
+			}
+		}
+	}
+
+	if epicValue == nil {
+		return valueEdit{}, false
+	}
+
+	derived := DeriveEpicStatus(storyStatuses)
+	if derived == StatusBacklog || Status(scalarValue(epicValue)) == derived {
+		return valueEdit{}, false
+	}
+
+	return valueEdit{key: EpicKey(epicID), node: epicValue, value: string(derived)}, true
 }
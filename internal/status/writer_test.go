@@ -1,8 +1,12 @@
 package status
 
 import (
+	"errors"
+	"flag"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -213,3 +217,273 @@ func TestWriter_UpdateStatus_AllStatusTransitions(t *testing.T) {
 		})
 	}
 }
+
+func TestWriter_UpdateStatus_SyncsEpicStatus(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		storyKey  string
+		newStatus Status
+		wantEpic  string
+	}{
+		{
+			name: "first story started moves epic to in-progress",
+			content: `development_status:
+  epic-6: backlog
+  6-1-a: backlog
+  6-2-b: backlog
+  epic-7: backlog
+  7-1-c: backlog
+`,
+			storyKey:  "6-1-a",
+			newStatus: StatusReadyForDev,
+			wantEpic:  "epic-6: in-progress",
+		},
+		{
+			name: "last story done moves epic to done",
+			content: `development_status:
+  epic-6: in-progress
+  6-1-a: done
+  6-2-b: review
+  epic-7: backlog
+  7-1-c: backlog
+`,
+			storyKey:  "6-2-b",
+			newStatus: StatusDone,
+			wantEpic:  "epic-6: done",
+		},
+		{
+			name: "reopened story moves done epic back to in-progress",
+			content: `development_status:
+  epic-6: done
+  6-1-a: done
+  6-2-b: done
+  epic-7: backlog
+  7-1-c: backlog
+`,
+			storyKey:  "6-2-b",
+			newStatus: StatusInProgress,
+			wantEpic:  "epic-6: in-progress",
+		},
+		{
+			name: "all stories in backlog leave epic untouched",
+			content: `development_status:
+  epic-6: in-progress
+  6-1-a: ready-for-dev
+  6-2-b: backlog
+  epic-7: backlog
+  7-1-c: backlog
+`,
+			storyKey:  "6-1-a",
+			newStatus: StatusBacklog,
+			wantEpic:  "epic-6: in-progress",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			writeStatusFixture(t, tmpDir, tt.content, nil)
+
+			require.NoError(t, NewWriter(tmpDir).UpdateStatus(tt.storyKey, tt.newStatus))
+
+			content, err := os.ReadFile(filepath.Join(tmpDir, DefaultStatusPath))
+			require.NoError(t, err)
+			assert.Contains(t, string(content), tt.wantEpic)
+			assert.Contains(t, string(content), "epic-7: backlog", "other epics are not touched")
+		})
+	}
+}
+
+func TestWriter_UpdateStatus_Retrospective(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeStatusFixture(t, tmpDir, `development_status:
+  epic-6: done
+  6-1-a: done
+  epic-6-retrospective: optional
+`, nil)
+	writer := NewWriter(tmpDir)
+
+	assert.Error(t, writer.UpdateStatus("epic-6-retrospective", StatusReview), "story statuses are invalid for retrospectives")
+	require.NoError(t, writer.UpdateStatus("epic-6-retrospective", StatusDone))
+
+	epic, err := NewReader(tmpDir).GetEpic("6")
+	require.NoError(t, err)
+	require.NotNil(t, epic.Retrospective)
+	assert.Equal(t, StatusDone, epic.Retrospective.Status)
+}
+
+func TestWriter_UpdateStatus_PreservesMetadata(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeStatusFixture(t, tmpDir, bmadSprintStatus, nil)
+
+	require.NoError(t, NewWriter(tmpDir).UpdateStatus("6-2-create-api", StatusDone))
+
+	sprint, err := NewReader(tmpDir).Read()
+	require.NoError(t, err)
+	assert.Equal(t, "demo", sprint.Project)
+	assert.Equal(t, "DEMO", sprint.ProjectKey)
+	assert.Equal(t, "2026-01-15", sprint.Generated)
+	assert.Equal(t, "{project-root}/_bmad-output/implementation-artifacts", sprint.StoryLocation)
+	assert.Equal(t, StatusDone, sprint.DevelopmentStatus["6-2-create-api"])
+	assert.Equal(t, StatusInProgress, sprint.DevelopmentStatus["epic-6"])
+}
+
+func TestWriter_UpdateStatus_ConcurrentUpdates(t *testing.T) {
+	tmpDir := t.TempDir()
+	keys := []string{"7-1-a", "7-2-b", "7-3-c", "7-4-d", "7-5-e", "7-6-f"}
+
+	content := "development_status:\n"
+	for _, key := range keys {
+		content += "  " + key + ": backlog\n"
+	}
+	writeStatusFixture(t, tmpDir, content, nil)
+
+	// Each goroutine uses its own writer, as separate processes would
+	var wg sync.WaitGroup
+	errs := make([]error, len(keys))
+	for i, key := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = NewWriter(tmpDir).UpdateStatus(key, StatusReadyForDev)
+		}()
+	}
+	wg.Wait()
+
+	reader := NewReader(tmpDir)
+	for i, key := range keys {
+		require.NoError(t, errs[i])
+		got, err := reader.GetStoryStatus(key)
+		require.NoError(t, err)
+		assert.Equal(t, StatusReadyForDev, got, "update to %s was lost", key)
+	}
+}
+
+func TestWriter_UpdateStatus_RetriesAfterExternalModification(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeStatusFixture(t, tmpDir, "development_status:\n  7-1-define-schema: backlog\n  7-2-create-api: backlog\n", nil)
+	statusPath := filepath.Join(tmpDir, DefaultStatusPath)
+
+	writer := NewWriter(tmpDir)
+	calls := 0
+	writer.beforeRename = func() {
+		calls++
+		if calls == 1 {
+			// Simulate the agent editing the file mid-update
+			require.NoError(t, os.WriteFile(statusPath, []byte("development_status:\n  7-1-define-schema: backlog\n  7-2-create-api: review\n"), 0644))
+		}
+	}
+
+	err := writer.UpdateStatus("7-1-define-schema", StatusInProgress)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+
+	// Both the external edit and the update survive
+	reader := NewReader(tmpDir)
+	got, err := reader.GetStoryStatus("7-1-define-schema")
+	require.NoError(t, err)
+	assert.Equal(t, StatusInProgress, got)
+	got, err = reader.GetStoryStatus("7-2-create-api")
+	require.NoError(t, err)
+	assert.Equal(t, StatusReview, got)
+}
+
+func TestWriter_UpdateStatus_ConcurrentModificationError(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeStatusFixture(t, tmpDir, "development_status:\n  7-1-define-schema: backlog\n", nil)
+	statusPath := filepath.Join(tmpDir, DefaultStatusPath)
+
+	writer := NewWriter(tmpDir)
+	calls := 0
+	writer.beforeRename = func() {
+		calls++
+		// Keep changing the file on every attempt
+		content := fmt.Sprintf("# edit %d\ndevelopment_status:\n  7-1-define-schema: backlog\n", calls)
+		require.NoError(t, os.WriteFile(statusPath, []byte(content), 0644))
+	}
+
+	err := writer.UpdateStatus("7-1-define-schema", StatusInProgress)
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrConcurrentModification))
+	assert.Equal(t, maxWriteAttempts, calls)
+
+	// The external edit is kept rather than overwritten
+	data, err := os.ReadFile(statusPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "7-1-define-schema: backlog")
+}
+
+func TestWriter_UpdateStatus_NoTempFilesLeft(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeStatusFixture(t, tmpDir, "development_status:\n  7-1-define-schema: backlog\n", nil)
+
+	writer := NewWriter(tmpDir)
+	require.NoError(t, writer.UpdateStatus("7-1-define-schema", StatusInProgress))
+
+	matches, err := filepath.Glob(filepath.Join(tmpDir, "_bmad-output", "implementation-artifacts", "*.tmp"))
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+}
+
+var updateGolden = flag.Bool("update", false, "update writer golden files")
+
+func TestWriter_UpdateStatus_Golden(t *testing.T) {
+	tests := []struct {
+		name      string
+		storyKey  string
+		newStatus Status
+	}{
+		// BMAD legend comments, custom indentation and epic sync
+		{name: "comments", storyKey: "7-1-define-schema", newStatus: StatusInProgress},
+		// Alias replaced by a plain value; anchors and tags elsewhere kept
+		{name: "anchors", storyKey: "7-1-define-schema", newStatus: StatusReadyForDev},
+		// Quoted keys and quote style of the value kept
+		{name: "quoted_keys", storyKey: "7-1-define-schema", newStatus: StatusInProgress},
+		// CRLF line endings kept, epic synced to done
+		{name: "crlf", storyKey: "7-1-define-schema", newStatus: StatusDone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input, err := os.ReadFile(filepath.Join("testdata", "writer", tt.name+".yaml"))
+			require.NoError(t, err)
+
+			tmpDir := t.TempDir()
+			writeStatusFixture(t, tmpDir, string(input), nil)
+
+			writer := NewWriter(tmpDir)
+			require.NoError(t, writer.UpdateStatus(tt.storyKey, tt.newStatus))
+
+			got, err := os.ReadFile(filepath.Join(tmpDir, DefaultStatusPath))
+			require.NoError(t, err)
+
+			goldenPath := filepath.Join("testdata", "writer", tt.name+".golden.yaml")
+			if *updateGolden {
+				require.NoError(t, os.WriteFile(goldenPath, got, 0644))
+			}
+			want, err := os.ReadFile(goldenPath)
+			require.NoError(t, err)
+			assert.Equal(t, string(want), string(got))
+		})
+	}
+}
+
+func TestWriter_UpdateStatus_BlockScalarValue(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := "development_status:\n  7-1-define-schema: |\n    backlog\n"
+	writeStatusFixture(t, tmpDir, content, nil)
+
+	writer := NewWriter(tmpDir)
+	err := writer.UpdateStatus("7-1-define-schema", StatusInProgress)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot update block scalar")
+
+	// File is left unchanged
+	data, err := os.ReadFile(filepath.Join(tmpDir, DefaultStatusPath))
+	require.NoError(t, err)
+	assert.Equal(t, content, string(data))
+}
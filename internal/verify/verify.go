@@ -0,0 +1,187 @@
+// Package verify checks that BMAD workflows actually advanced a story.
+//
+// A workflow exiting with code 0 only means Claude finished without error,
+// not that it did the work. [Verifier] inspects the project after each
+// workflow and reports what is missing:
+//   - create-story: the story markdown file exists under the story location
+//   - dev-story: the agent moved the story to review, or every task checkbox
+//     in the story file is ticked
+//   - git-commit: git HEAD moved to a new commit whose message references
+//     the story key
+//
+// Other workflows are not verified. [NewCommitVerifier] creates a Verifier
+// that only checks git-commit. [Verifier] implements
+// [lifecycle.StepVerifier].
+package verify
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+
+	"bmaduum/internal/git"
+	"bmaduum/internal/lifecycle"
+	"bmaduum/internal/status"
+)
+
+// StatusReader is the interface for looking up story status and files.
+//
+// The [status.Reader] type implements this interface.
+type StatusReader interface {
+	GetStoryStatus(storyKey string) (status.Status, error)
+	StoryFilePath(storyKey string) (string, error)
+}
+
+// Verifier checks the outcome of the standard BMAD workflows.
+//
+// Use [New] to create an instance.
+type Verifier struct {
+	reader StatusReader
+	dir    string
+
+	// commitOnly limits verification to git-commit.
+	commitOnly bool
+
+	// head returns the current git HEAD of dir.
+	head func(dir string) (string, error)
+
+	// messages returns the messages of commits made since a commit.
+	messages func(dir, since string) (string, error)
+}
+
+// New creates a [Verifier] that reads story status through reader and
+// inspects the git repository in dir. An empty dir means the current
+// working directory.
+func New(reader StatusReader, dir string) *Verifier {
+	return &Verifier{
+		reader:   reader,
+		dir:      dir,
+		head:     git.Head,
+		messages: git.CommitMessages,
+	}
+}
+
+// NewCommitVerifier creates a [Verifier] that only checks that git-commit
+// created a commit referencing the story key, in the git repository in dir.
+// The CLI uses it when full step verification is off.
+func NewCommitVerifier(dir string) *Verifier {
+	v := New(nil, dir)
+	v.commitOnly = true
+	return v
+}
+
+// Prepare returns the check for a workflow, capturing any state it compares
+// against. Returns nil for workflows that are not verified.
+func (v *Verifier) Prepare(storyKey, workflow string) lifecycle.StepCheck {
+	if v.commitOnly && workflow != "git-commit" {
+		return nil
+	}
+	switch workflow {
+	case "create-story":
+		return func() error { return v.checkStoryFile(storyKey) }
+	case "dev-story":
+		return func() error { return v.checkDevComplete(storyKey) }
+	case "git-commit":
+		before, _ := v.head(v.dir)
+		return func() error { return v.checkCommitted(storyKey, before) }
+	default:
+		return nil
+	}
+}
+
+// checkStoryFile verifies that the story markdown file was created.
+func (v *Verifier) checkStoryFile(storyKey string) error {
+	path, err := v.reader.StoryFilePath(storyKey)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("story file was not created: %s", path)
+	} else if err != nil {
+		return fmt.Errorf("cannot check story file: %w", err)
+	}
+
+	return nil
+}
+
+var (
+	// checkedTaskPattern matches ticked markdown task checkboxes: - [x]
+	checkedTaskPattern = regexp.MustCompile(`(?m)^\s*[-*+]\s+\[[xX]\]`)
+
+	// uncheckedTaskPattern matches unticked markdown task checkboxes: - [ ]
+	uncheckedTaskPattern = regexp.MustCompile(`(?m)^\s*[-*+]\s+\[ \]`)
+)
+
+// checkDevComplete verifies that dev-story finished the story.
+//
+// BMAD's dev-story moves the story to review in sprint-status.yaml when it
+// is done; failing that, a story file whose tasks are all ticked counts as
+// complete.
+func (v *Verifier) checkDevComplete(storyKey string) error {
+	current, err := v.reader.GetStoryStatus(storyKey)
+	if err != nil {
+		return err
+	}
+	if current == status.StatusReview || current == status.StatusDone {
+		return nil
+	}
+
+	path, err := v.reader.StoryFilePath(storyKey)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("story is still %s and its story file cannot be read: %w", current, err)
+	}
+
+	checked := len(checkedTaskPattern.FindAll(data, -1))
+	unchecked := len(uncheckedTaskPattern.FindAll(data, -1))
+	switch {
+	case checked+unchecked == 0:
+		return fmt.Errorf("story is still %s and %s has no task checkboxes", current, path)
+	case unchecked > 0:
+		return fmt.Errorf("story is still %s and %d of %d tasks are unchecked in %s", current, unchecked, checked+unchecked, path)
+	}
+
+	return nil
+}
+
+// checkCommitted verifies that git HEAD moved from before to a new commit
+// whose message references the story key.
+func (v *Verifier) checkCommitted(storyKey, before string) error {
+	after, err := v.head(v.dir)
+	if err != nil {
+		return fmt.Errorf("cannot read git HEAD: %w", err)
+	}
+	if after == before {
+		return fmt.Errorf("no commit was created (HEAD is still %s)", shortHash(after))
+	}
+
+	messages, err := v.messages(v.dir, before)
+	if err != nil {
+		return fmt.Errorf("cannot read new commits: %w", err)
+	}
+	if !referencesKey(messages, storyKey) {
+		return fmt.Errorf("no new commit references story %s", storyKey)
+	}
+	return nil
+}
+
+// referencesKey reports whether text mentions storyKey, ignoring case. The
+// key must not touch another digit, so "16-1" or "6-10" do not count as a
+// reference to "6-1".
+func referencesKey(text, storyKey string) bool {
+	pattern := `(?i)(^|[^0-9])` + regexp.QuoteMeta(storyKey) + `($|[^0-9])`
+	return regexp.MustCompile(pattern).MatchString(text)
+}
+
+// shortHash abbreviates a commit hash for messages.
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
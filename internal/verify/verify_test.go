@@ -0,0 +1,167 @@
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bmaduum/internal/status"
+)
+
+// setupProject writes sprint-status.yaml and optional story files into a temp
+// project and returns a Verifier for it.
+func setupProject(t *testing.T, statusContent string, storyFiles map[string]string) (*Verifier, string) {
+	t.Helper()
+
+	tmpDir := t.TempDir()
+	statusDir := filepath.Join(tmpDir, "_bmad-output", "implementation-artifacts")
+	require.NoError(t, os.MkdirAll(statusDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(statusDir, "sprint-status.yaml"), []byte(statusContent), 0644))
+	for key, content := range storyFiles {
+		require.NoError(t, os.WriteFile(filepath.Join(statusDir, key+".md"), []byte(content), 0644))
+	}
+
+	return New(status.NewReader(tmpDir), tmpDir), statusDir
+}
+
+func TestVerifier_Prepare_UnverifiedWorkflow(t *testing.T) {
+	v, _ := setupProject(t, "development_status:\n  6-1-first: review\n", nil)
+
+	assert.Nil(t, v.Prepare("6-1-first", "code-review"))
+	assert.Nil(t, v.Prepare("6-1-first", "custom"))
+}
+
+func TestVerifier_CreateStory(t *testing.T) {
+	v, statusDir := setupProject(t, "development_status:\n  6-1-first: backlog\n", nil)
+
+	check := v.Prepare("6-1-first", "create-story")
+	require.NotNil(t, check)
+
+	err := check()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "story file was not created")
+
+	require.NoError(t, os.WriteFile(filepath.Join(statusDir, "6-1-first.md"), []byte("# Story\n"), 0644))
+	assert.NoError(t, check())
+}
+
+func TestVerifier_DevStory(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  string
+		story   string
+		wantErr string
+	}{
+		{
+			name:   "agent moved story to review",
+			status: "review",
+			story:  "- [ ] Task 1\n",
+		},
+		{
+			name:   "all tasks checked",
+			status: "in-progress",
+			story:  "## Tasks\n\n- [x] Task 1\n  - [X] Subtask\n* [x] Task 2\n",
+		},
+		{
+			name:    "unchecked tasks remain",
+			status:  "in-progress",
+			story:   "- [x] Task 1\n- [ ] Task 2\n  - [ ] Subtask\n",
+			wantErr: "story is still in-progress and 2 of 3 tasks are unchecked",
+		},
+		{
+			name:    "no task checkboxes",
+			status:  "ready-for-dev",
+			story:   "# Story\n",
+			wantErr: "has no task checkboxes",
+		},
+		{
+			name:    "missing story file",
+			status:  "ready-for-dev",
+			wantErr: "story file cannot be read",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := map[string]string{}
+			if tt.story != "" {
+				files["6-1-first"] = tt.story
+			}
+			v, _ := setupProject(t, "development_status:\n  6-1-first: "+tt.status+"\n", files)
+
+			err := v.Prepare("6-1-first", "dev-story")()
+
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestVerifier_GitCommit(t *testing.T) {
+	v, _ := setupProject(t, "development_status:\n  6-1-first: review\n", nil)
+
+	head := "1111111aaaaaaa"
+	v.head = func(dir string) (string, error) { return head, nil }
+	messages := "feat(6-1-first): add login"
+	var since string
+	v.messages = func(dir, from string) (string, error) {
+		since = from
+		return messages, nil
+	}
+
+	check := v.Prepare("6-1-first", "git-commit")
+
+	err := check()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no commit was created (HEAD is still 1111111)")
+
+	head = "2222222bbbbbbb"
+	assert.NoError(t, check())
+	assert.Equal(t, "1111111aaaaaaa", since)
+
+	messages = "chore: update dependencies"
+	err = check()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no new commit references story 6-1-first")
+}
+
+func TestNewCommitVerifier(t *testing.T) {
+	v := NewCommitVerifier(t.TempDir())
+	v.head = func(dir string) (string, error) { return "1111111aaaaaaa", nil }
+
+	assert.Nil(t, v.Prepare("6-1-first", "create-story"))
+	assert.Nil(t, v.Prepare("6-1-first", "dev-story"))
+
+	check := v.Prepare("6-1-first", "git-commit")
+	require.NotNil(t, check)
+	assert.ErrorContains(t, check(), "no commit was created")
+}
+
+func TestReferencesKey(t *testing.T) {
+	tests := []struct {
+		text string
+		key  string
+		want bool
+	}{
+		{"feat(6-1): add login", "6-1", true},
+		{"Story: 6-1", "6-1", true},
+		{"6-1 add login", "6-1", true},
+		{"feat(6-1-First): add login", "6-1-first", true},
+		{"feat(6-10): add login", "6-1", false},
+		{"feat(16-1): add login", "6-1", false},
+		{"feat(16-1-first): add login", "6-1-first", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			assert.Equal(t, tt.want, referencesKey(tt.text, tt.key))
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"bmaduum/internal/config"
+	"bmaduum/internal/git"
+)
+
+// maxCommitDiff caps how much of the staged diff the commit-message prompt
+// includes, in bytes.
+const maxCommitDiff = 50_000
+
+// RunNativeCommit commits a story's changes without a full Claude session.
+//
+// All changes are staged, Claude is asked for a conventional-commit message
+// through the [config.CommitMessageWorkflow] prompt, and the commit is made
+// directly. The message always references storyKey. The branch is then
+// pushed unless git.push is false (--no-push).
+//
+// Returns 0 on success, the Claude exit code if the message could not be
+// written, or 1 if there is nothing to commit or a git command fails.
+func (r *Runner) RunNativeCommit(ctx context.Context, storyKey string) int {
+	if err := git.StageAll(""); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+
+	diff, err := git.StagedDiff("")
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+	if strings.TrimSpace(diff) == "" {
+		fmt.Printf("Error: nothing to commit for story %s\n", storyKey)
+		return 1
+	}
+	diff = truncateDiff(diff, maxCommitDiff)
+
+	prompt, err := r.config.GetPromptWithData(config.CommitMessageWorkflow, r.promptData(config.CommitMessageWorkflow, config.PromptData{
+		StoryKey: storyKey,
+		Diff:     diff,
+	}))
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+	label := fmt.Sprintf("%s: %s", config.CommitMessageWorkflow, storyKey)
+	exitCode := r.runClaude(ctx, prompt, label, config.CommitMessageWorkflow)
+	r.lastStoryKey = storyKey
+	if exitCode != 0 {
+		return exitCode
+	}
+
+	message := commitMessage(r.lastMessage, storyKey)
+	if err := git.Commit("", message); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+	subject, _, _ := strings.Cut(message, "\n")
+	r.printer.Text(fmt.Sprintf("Committed: %s", subject))
+
+	if !r.config.Git.Push {
+		return 0
+	}
+	if err := git.Push(""); err != nil {
+		fmt.Printf("Error: push failed: %v\n", err)
+		return 1
+	}
+	r.printer.Text("Pushed to the current branch")
+	return 0
+}
+
+// truncateDiff cuts diff to at most limit bytes, backing off to the start of
+// a UTF-8 character so none is split, and marks it as truncated.
+func truncateDiff(diff string, limit int) string {
+	if len(diff) <= limit {
+		return diff
+	}
+	for limit > 0 && !utf8.RuneStart(diff[limit]) {
+		limit--
+	}
+	return diff[:limit] + "\n[diff truncated]"
+}
+
+// commitMessage cleans up the message Claude wrote for storyKey.
+//
+// Surrounding code fences are removed, a fallback is used if the reply is
+// empty, and a "Story:" trailer is added if the message does not mention
+// the story key, so the commit can always be traced back to the story.
+func commitMessage(reply, storyKey string) string {
+	message := strings.TrimSpace(reply)
+	if strings.HasPrefix(message, "```") {
+		lines := strings.Split(message, "\n")
+		lines = lines[1:]
+		if n := len(lines); n > 0 && strings.HasPrefix(strings.TrimSpace(lines[n-1]), "```") {
+			lines = lines[:n-1]
+		}
+		message = strings.TrimSpace(strings.Join(lines, "\n"))
+	}
+
+	if message == "" {
+		message = fmt.Sprintf("chore: complete story %s", storyKey)
+	}
+	if !strings.Contains(strings.ToLower(message), strings.ToLower(storyKey)) {
+		message += "\n\nStory: " + storyKey
+	}
+	return message
+}
@@ -0,0 +1,157 @@
+package workflow
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bmaduum/internal/claude"
+	"bmaduum/internal/config"
+)
+
+// initCommitRepo creates a git repository with one commit and changes into it.
+func initCommitRepo(t *testing.T) string {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "Test"},
+		{"commit", "-q", "--allow-empty", "-m", "initial"},
+	} {
+		gitOutput(t, dir, args...)
+	}
+	t.Chdir(dir)
+	return dir
+}
+
+// gitOutput runs git in dir and returns its trimmed output.
+func gitOutput(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	return strings.TrimSpace(string(out))
+}
+
+func TestRunner_RunNativeCommit(t *testing.T) {
+	dir := initCommitRepo(t)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "feature.go"), []byte("package feature\n"), 0644))
+
+	runner, mockExecutor, buf := setupTestRunner()
+	runner.config.Git.CommitMode = config.CommitModeNative
+	runner.config.Git.Push = false
+	mockExecutor.Events = []claude.Event{
+		{Type: claude.EventTypeAssistant, Text: "```\nfeat(6-1-first): add feature package\n\nAdds the feature package.\n```"},
+	}
+
+	exitCode := runner.RunSingle(context.Background(), config.GitCommitWorkflow, "6-1-first")
+
+	assert.Equal(t, 0, exitCode)
+	require.Len(t, mockExecutor.RecordedPrompts, 1)
+	assert.Contains(t, mockExecutor.RecordedPrompts[0], "+package feature")
+	assert.Equal(t, "feat(6-1-first): add feature package\n\nAdds the feature package.", gitOutput(t, dir, "log", "-1", "--format=%B"))
+	assert.Empty(t, gitOutput(t, dir, "status", "--porcelain"))
+	assert.Contains(t, buf.String(), "Committed: feat(6-1-first): add feature package")
+}
+
+func TestRunner_RunNativeCommit_Push(t *testing.T) {
+	dir := initCommitRepo(t)
+	remote := t.TempDir()
+	gitOutput(t, remote, "init", "-q", "--bare")
+	gitOutput(t, dir, "remote", "add", "origin", remote)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "feature.go"), []byte("package feature\n"), 0644))
+
+	runner, mockExecutor, _ := setupTestRunner()
+	runner.config.Git.CommitMode = config.CommitModeNative
+	mockExecutor.Events = []claude.Event{{Type: claude.EventTypeAssistant, Text: "feat: add feature package"}}
+
+	exitCode := runner.RunSingle(context.Background(), config.GitCommitWorkflow, "6-1-first")
+
+	assert.Equal(t, 0, exitCode)
+	assert.Equal(t, "feat: add feature package\n\nStory: 6-1-first", gitOutput(t, dir, "log", "-1", "--format=%B"))
+	assert.Equal(t, gitOutput(t, dir, "rev-parse", "HEAD"), gitOutput(t, remote, "rev-parse", "HEAD"))
+}
+
+func TestRunner_RunNativeCommit_NothingToCommit(t *testing.T) {
+	initCommitRepo(t)
+
+	runner, mockExecutor, _ := setupTestRunner()
+	runner.config.Git.CommitMode = config.CommitModeNative
+
+	exitCode := runner.RunSingle(context.Background(), config.GitCommitWorkflow, "6-1-first")
+
+	assert.Equal(t, 1, exitCode)
+	assert.Empty(t, mockExecutor.RecordedPrompts, "no message is needed without changes")
+}
+
+func TestRunner_RunSingle_GitCommitPush(t *testing.T) {
+	runner, mockExecutor, _ := setupTestRunner()
+
+	runner.RunSingle(context.Background(), config.GitCommitWorkflow, "6-1-first")
+	runner.config.Git.Push = false
+	runner.RunSingle(context.Background(), config.GitCommitWorkflow, "6-1-first")
+
+	require.Len(t, mockExecutor.RecordedPrompts, 2)
+	assert.Contains(t, mockExecutor.RecordedPrompts[0], "Then push to the current branch.")
+	assert.NotContains(t, mockExecutor.RecordedPrompts[1], "push")
+}
+
+func TestTruncateDiff(t *testing.T) {
+	assert.Equal(t, "+short", truncateDiff("+short", 10))
+	assert.Equal(t, "+abc\n[diff truncated]", truncateDiff("+abcdef", 4))
+
+	// "é" is two bytes, so a cut after its first byte backs off before it
+	truncated := truncateDiff("+café", 5)
+	assert.Equal(t, "+caf\n[diff truncated]", truncated)
+	assert.True(t, utf8.ValidString(truncated))
+}
+
+func TestCommitMessage(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply string
+		want  string
+	}{
+		{
+			name:  "message referencing the story is used as is",
+			reply: "feat(6-1-first): add login\n\nAdds the login form.",
+			want:  "feat(6-1-first): add login\n\nAdds the login form.",
+		},
+		{
+			name:  "code fences are removed",
+			reply: "```text\nfix(6-1-first): handle empty input\n```\n",
+			want:  "fix(6-1-first): handle empty input",
+		},
+		{
+			name:  "story trailer is added when the key is missing",
+			reply: "feat: add login",
+			want:  "feat: add login\n\nStory: 6-1-first",
+		},
+		{
+			name:  "empty reply falls back to a default",
+			reply: "  \n",
+			want:  "chore: complete story 6-1-first",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, commitMessage(tt.reply, "6-1-first"))
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"bmaduum/internal/config"
+	"bmaduum/internal/status"
+)
+
+// promptData fills in the template fields of data that the caller left
+// unset.
+//
+// ProjectRoot is the working directory. For a story, the epic ID, story
+// number and slug come from the story key, the status and story file path
+// from the project's sprint-status.yaml, and PreviousMessage from the last
+// workflow run for the same story. Attempt counts the runs of workflowName
+// for the story (or epic) in this invocation, including this one. Status
+// file errors are ignored; the fields they provide stay empty.
+func (r *Runner) promptData(workflowName string, data config.PromptData) config.PromptData {
+	if data.ProjectRoot == "" {
+		data.ProjectRoot, _ = os.Getwd()
+	}
+
+	if data.Attempt == 0 {
+		key := fmt.Sprintf("%s\x00%s\x00%s", workflowName, data.StoryKey, data.EpicID)
+		r.attempts[key]++
+		data.Attempt = r.attempts[key]
+	}
+
+	if data.StoryKey == "" {
+		return data
+	}
+
+	if data.PreviousMessage == "" && data.StoryKey == r.lastStoryKey {
+		data.PreviousMessage = r.lastMessage
+	}
+
+	if kind, epicID, num := status.ParseKey(data.StoryKey); kind == status.EntryStory && epicID != "" {
+		if data.EpicID == "" {
+			data.EpicID = epicID
+		}
+		data.StoryNum = num
+		data.StorySlug = strings.TrimPrefix(data.StoryKey, fmt.Sprintf("%s-%d", epicID, num))
+		data.StorySlug = strings.TrimPrefix(data.StorySlug, "-")
+	}
+
+	reader := status.NewReaderWithPath("", status.ResolveStatusPath("", r.config.Status.Path))
+	if data.Status == "" {
+		if s, err := reader.GetStoryStatus(data.StoryKey); err == nil {
+			data.Status = string(s)
+		}
+	}
+	if data.StoryFile == "" {
+		if path, err := reader.StoryFilePath(data.StoryKey); err == nil {
+			data.StoryFile = path
+		}
+	}
+
+	return data
+}
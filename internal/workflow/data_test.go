@@ -0,0 +1,61 @@
+package workflow
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bmaduum/internal/claude"
+	"bmaduum/internal/config"
+	"bmaduum/internal/status"
+)
+
+func TestRunner_RunSingle_StoryData(t *testing.T) {
+	dir := t.TempDir()
+	statusFile := filepath.Join(dir, status.DefaultStatusPath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(statusFile), 0755))
+	require.NoError(t, os.WriteFile(statusFile, []byte("development_status:\n  6-2-add-api: in-progress\n"), 0644))
+	t.Chdir(dir)
+	root, err := os.Getwd()
+	require.NoError(t, err)
+
+	runner, mockExecutor, _ := setupTestRunner()
+	runner.config.Vars = map[string]string{"team": "core"}
+	runner.config.Workflows["dev-story"] = config.WorkflowConfig{
+		PromptTemplate: "{{.EpicID}}|{{.StoryNum}}|{{.StorySlug}}|{{.Status}}|{{.StoryFile}}|{{.ProjectRoot}}|{{.Attempt}}|{{.PreviousMessage}}|{{.Vars.team}}",
+	}
+
+	require.Equal(t, 0, runner.RunSingle(context.Background(), "dev-story", "6-2-add-api"))
+
+	storyFile := filepath.Join(filepath.Dir(status.DefaultStatusPath), "6-2-add-api.md")
+	require.Len(t, mockExecutor.RecordedPrompts, 1)
+	assert.Equal(t, "6|2|add-api|in-progress|"+storyFile+"|"+root+"|1||core", mockExecutor.RecordedPrompts[0])
+}
+
+func TestRunner_RunSingle_AttemptAndPreviousMessage(t *testing.T) {
+	runner, mockExecutor, _ := setupTestRunner()
+	runner.config.Workflows["dev-story"] = config.WorkflowConfig{
+		PromptTemplate: "dev {{.Attempt}}: {{.PreviousMessage}}",
+	}
+	mockExecutor.Events = []claude.Event{
+		{Type: claude.EventTypeAssistant, Text: "REVIEW_RESULT: CHANGES_REQUESTED"},
+		{Type: claude.EventTypeResult, SessionComplete: true},
+	}
+	ctx := context.Background()
+
+	runner.RunSingle(ctx, "dev-story", "6-1-a")
+	runner.RunSingle(ctx, "code-review", "6-1-a")
+	runner.RunSingle(ctx, "dev-story", "6-1-a")
+	runner.RunSingle(ctx, "dev-story", "6-2-b")
+
+	require.Len(t, mockExecutor.RecordedPrompts, 4)
+	assert.Equal(t, "dev 1: ", mockExecutor.RecordedPrompts[0])
+	assert.Equal(t, "dev 2: REVIEW_RESULT: CHANGES_REQUESTED", mockExecutor.RecordedPrompts[2],
+		"the rerun sees its attempt number and the review's final message")
+	assert.Equal(t, "dev 1: ", mockExecutor.RecordedPrompts[3],
+		"another story starts at attempt 1 without the previous story's message")
+}
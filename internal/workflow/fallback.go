@@ -0,0 +1,123 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"bmaduum/internal/claude"
+	"bmaduum/internal/config"
+	"bmaduum/internal/ratelimit"
+)
+
+// execute runs the prompt with the given options on the workflow's models in
+// turn (see [config.Config.GetModels]).
+//
+// When a session hits a limit the workflow's model_fallback policy covers,
+// reported in its error result or on the CLI's stderr (see
+// [Runner.HandleStderr]), it is stopped and the prompt is run again on the
+// next model. The last model runs like a workflow without fallback. setModel is called with each
+// model before it runs so the status bar shows the model actually running.
+//
+// Returns the exit code of the last session.
+func (r *Runner) execute(ctx context.Context, workflowName, prompt string, opts claude.Options, handler claude.EventHandler, setModel func(model string)) int {
+	models := r.config.GetModels(workflowName)
+	if len(models) == 0 {
+		models = []string{opts.Model}
+	}
+	policy := r.config.GetModelFallback(workflowName)
+
+	for i, model := range models {
+		opts.Model = model
+		r.lastModel = model
+		setModel(model)
+
+		canFallBack := i < len(models)-1
+		runCtx, cancel := context.WithCancel(ctx)
+		var (
+			mu    sync.Mutex
+			limit ratelimit.ErrorInfo
+		)
+		// stop records a limit the policy covers and stops the session.
+		stop := func(info ratelimit.ErrorInfo) bool {
+			if !canFallBack || !fallsBack(policy, info) {
+				return false
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if limit.RawMessage == "" {
+				limit = info
+				cancel()
+			}
+			return true
+		}
+		r.setStderrHandler(func(line string) {
+			stop(r.detector.CheckLine(line))
+		})
+		exitCode, err := r.executor.ExecuteWithResult(runCtx, prompt, func(event claude.Event) {
+			mu.Lock()
+			stopped := limit.RawMessage != ""
+			mu.Unlock()
+			if stopped {
+				return // Session is being stopped
+			}
+			if event.IsError && stop(r.detector.CheckLine(event.Result)) {
+				return
+			}
+			handler(event)
+		}, opts)
+		r.setStderrHandler(nil)
+		cancel()
+		mu.Lock()
+		hit := limit
+		mu.Unlock()
+
+		if hit.RawMessage != "" && ctx.Err() == nil {
+			reason := "hit a usage limit"
+			if !hit.IsRateLimit {
+				reason = "is overloaded"
+			}
+			fmt.Printf("\n⚠️  Model %s %s, falling back to %s\n", model, reason, models[i+1])
+			continue
+		}
+		if err != nil {
+			fmt.Printf("Error executing claude: %v\n", err)
+			return 1
+		}
+		return exitCode
+	}
+	return 1
+}
+
+// HandleStderr passes a line the CLI wrote to stderr to the running session,
+// so a usage limit or overload reported there triggers the workflow's
+// model_fallback policy. Lines written while no session runs are ignored.
+//
+// It is safe to call from the executor's stderr handler.
+func (r *Runner) HandleStderr(line string) {
+	r.stderrMu.Lock()
+	handle := r.onStderr
+	r.stderrMu.Unlock()
+	if handle != nil {
+		handle(line)
+	}
+}
+
+// setStderrHandler sets the handler [Runner.HandleStderr] passes lines to.
+func (r *Runner) setStderrHandler(handle func(line string)) {
+	r.stderrMu.Lock()
+	r.onStderr = handle
+	r.stderrMu.Unlock()
+}
+
+// fallsBack reports whether a model_fallback policy moves on to the next
+// model after the given error.
+func fallsBack(policy string, info ratelimit.ErrorInfo) bool {
+	switch policy {
+	case config.ModelFallbackRateLimit:
+		return info.IsRateLimit
+	case config.ModelFallbackOverload:
+		return info.IsRateLimit || info.IsOverloaded
+	}
+	return false
+}
@@ -0,0 +1,177 @@
+package workflow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bmaduum/internal/claude"
+	"bmaduum/internal/config"
+)
+
+// modelExecutor replays scripted sessions by model and records the models
+// it was asked to run. A model's stderr line is passed to onStderr before
+// its events.
+type modelExecutor struct {
+	sessions map[string][]claude.Event
+	exitCode map[string]int
+	stderr   map[string]string
+	onStderr func(line string)
+	models   []string
+}
+
+func (m *modelExecutor) Execute(ctx context.Context, prompt string) (<-chan claude.Event, error) {
+	panic("not used")
+}
+
+func (m *modelExecutor) ExecuteWithResult(ctx context.Context, prompt string, handler claude.EventHandler, opts claude.Options) (int, error) {
+	m.models = append(m.models, opts.Model)
+	if line := m.stderr[opts.Model]; line != "" && m.onStderr != nil {
+		m.onStderr(line)
+	}
+	for _, event := range m.sessions[opts.Model] {
+		if ctx.Err() != nil {
+			return -1, nil
+		}
+		handler(event)
+	}
+	return m.exitCode[opts.Model], nil
+}
+
+var (
+	usageLimitResult = claude.Event{Type: claude.EventTypeResult, SessionComplete: true, IsError: true, Result: "Claude AI usage limit reached|1760000000"}
+	overloadedResult = claude.Event{Type: claude.EventTypeResult, SessionComplete: true, IsError: true, Result: `API Error: 529 {"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`}
+	successResult    = claude.Event{Type: claude.EventTypeResult, SessionComplete: true}
+)
+
+func setupFallbackRunner(policy string, executor *modelExecutor) *Runner {
+	runner, _, _ := setupTestRunner()
+	runner.executor = executor
+	wf := runner.config.Workflows["dev-story"]
+	wf.Models = []string{"opus", "sonnet", "haiku"}
+	wf.ModelFallback = policy
+	runner.config.Workflows["dev-story"] = wf
+	return runner
+}
+
+func TestRunner_ModelFallback_RateLimit(t *testing.T) {
+	executor := &modelExecutor{
+		sessions: map[string][]claude.Event{
+			"opus":   {{Type: claude.EventTypeAssistant, Text: "Starting..."}, usageLimitResult},
+			"sonnet": {{Type: claude.EventTypeAssistant, Text: "Done."}, successResult},
+		},
+		exitCode: map[string]int{"opus": 1},
+	}
+	runner := setupFallbackRunner(config.ModelFallbackRateLimit, executor)
+
+	exitCode := runner.RunSingle(context.Background(), "dev-story", "test-123")
+
+	assert.Equal(t, 0, exitCode)
+	assert.Equal(t, []string{"opus", "sonnet"}, executor.models)
+	assert.Equal(t, "sonnet", runner.LastModel())
+	assert.Equal(t, "Done.", runner.LastMessage())
+}
+
+func TestRunner_ModelFallback_Overload(t *testing.T) {
+	sessions := map[string][]claude.Event{
+		"opus":   {overloadedResult},
+		"sonnet": {overloadedResult},
+		"haiku":  {successResult},
+	}
+
+	tests := []struct {
+		policy     string
+		wantModels []string
+		wantExit   int
+	}{
+		{config.ModelFallbackOverload, []string{"opus", "sonnet", "haiku"}, 0},
+		{config.ModelFallbackRateLimit, []string{"opus"}, 1},
+		{config.ModelFallbackNever, []string{"opus"}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			executor := &modelExecutor{sessions: sessions, exitCode: map[string]int{"opus": 1, "sonnet": 1}}
+			runner := setupFallbackRunner(tt.policy, executor)
+
+			exitCode := runner.RunSingle(context.Background(), "dev-story", "test-123")
+
+			assert.Equal(t, tt.wantExit, exitCode)
+			assert.Equal(t, tt.wantModels, executor.models)
+			assert.Equal(t, tt.wantModels[len(tt.wantModels)-1], runner.LastModel())
+		})
+	}
+}
+
+func TestRunner_ModelFallback_LastModelFails(t *testing.T) {
+	executor := &modelExecutor{
+		sessions: map[string][]claude.Event{
+			"opus":   {usageLimitResult},
+			"sonnet": {usageLimitResult},
+			"haiku":  {usageLimitResult},
+		},
+		exitCode: map[string]int{"opus": 1, "sonnet": 1, "haiku": 1},
+	}
+	runner := setupFallbackRunner(config.ModelFallbackRateLimit, executor)
+
+	exitCode := runner.RunSingle(context.Background(), "dev-story", "test-123")
+
+	assert.Equal(t, 1, exitCode)
+	assert.Equal(t, []string{"opus", "sonnet", "haiku"}, executor.models)
+}
+
+func TestRunner_ModelFallback_StopsSession(t *testing.T) {
+	executor := &modelExecutor{
+		sessions: map[string][]claude.Event{
+			"opus":   {{Type: claude.EventTypeAssistant, Text: "never seen"}},
+			"sonnet": {successResult},
+		},
+		stderr: map[string]string{"opus": "Claude usage limit reached. Your limit will reset at 1pm (Etc/GMT+5)"},
+	}
+	runner := setupFallbackRunner(config.ModelFallbackRateLimit, executor)
+	executor.onStderr = runner.HandleStderr
+
+	exitCode := runner.RunSingle(context.Background(), "dev-story", "test-123")
+
+	assert.Equal(t, 0, exitCode)
+	assert.Equal(t, []string{"opus", "sonnet"}, executor.models)
+	assert.Empty(t, runner.LastMessage())
+}
+
+func TestRunner_ModelFallback_IgnoresToolStderr(t *testing.T) {
+	executor := &modelExecutor{
+		sessions: map[string][]claude.Event{
+			"opus": {
+				{Type: claude.EventTypeUser, HasToolResult: true, ToolStderr: overloadedResult.Result},
+				successResult,
+			},
+		},
+	}
+	runner := setupFallbackRunner(config.ModelFallbackOverload, executor)
+
+	exitCode := runner.RunSingle(context.Background(), "dev-story", "test-123")
+
+	assert.Equal(t, 0, exitCode)
+	assert.Equal(t, []string{"opus"}, executor.models)
+}
+
+func TestRunner_HandleStderr_NoSession(t *testing.T) {
+	runner, _, _ := setupTestRunner()
+
+	assert.NotPanics(t, func() { runner.HandleStderr("Claude usage limit reached") })
+}
+
+func TestRunner_SingleModel(t *testing.T) {
+	runner, mockExecutor, _ := setupTestRunner()
+	wf := runner.config.Workflows["dev-story"]
+	wf.Model = "opus"
+	runner.config.Workflows["dev-story"] = wf
+
+	require.Equal(t, 0, runner.RunSingle(context.Background(), "dev-story", "test-123"))
+
+	require.Len(t, mockExecutor.RecordedOptions, 1)
+	assert.Equal(t, "opus", mockExecutor.RecordedOptions[0].Model)
+	assert.Equal(t, "opus", runner.LastModel())
+}
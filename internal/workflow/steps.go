@@ -14,7 +14,11 @@
 // expansion with story keys.
 package workflow
 
-import "time"
+import (
+	"time"
+
+	"bmaduum/internal/claude"
+)
 
 // Step represents a single step in a workflow execution.
 //
@@ -26,8 +30,8 @@ type Step struct {
 	Name string
 	// Prompt is the expanded prompt text to send to Claude CLI.
 	Prompt string
-	// Model is the Claude model to use for this step (optional).
-	Model string
+	// Options are the Claude CLI settings for this step, including the model.
+	Options claude.Options
 }
 
 // StepResult captures the outcome of executing a single workflow step.
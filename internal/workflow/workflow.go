@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sync"
 	"time"
 
 	"bmaduum/internal/claude"
@@ -27,6 +28,28 @@ type Runner struct {
 	config     *config.Config
 	detector   *ratelimit.Detector
 	correlator *ToolCorrelator // Correlates tool uses with their results
+
+	// lastMessage is the final text message of the last Claude execution.
+	lastMessage string
+
+	// lastSessionID is the Claude session ID of the last Claude execution.
+	lastSessionID string
+
+	// lastModel is the model the last Claude execution ran on, after any
+	// fallback.
+	lastModel string
+
+	// lastStoryKey is the story the last workflow ran for, so its final
+	// message is only passed on to workflows of the same story.
+	lastStoryKey string
+
+	// attempts counts workflow runs per story (see [config.PromptData.Attempt]).
+	attempts map[string]int
+
+	// stderrMu guards onStderr, which receives the CLI's stderr lines while
+	// a session runs (see [Runner.HandleStderr]).
+	stderrMu sync.Mutex
+	onStderr func(line string)
 }
 
 // NewRunner creates a new workflow runner with the specified dependencies.
@@ -36,17 +59,23 @@ type Runner struct {
 //   - printer: The [core.Printer] for formatted terminal output
 //   - cfg: The configuration containing workflow prompt templates
 //
+// The active configuration profile (see [config.Config.Profile]) is shown in
+// the status bar.
+//
 // The executor typically uses [claude.NewExecutor] in production or
 // [claude.MockExecutor] for testing.
 func NewRunner(executor claude.Executor, printer core.Printer, cfg *config.Config) *Runner {
-	return &Runner{
+	r := &Runner{
 		executor:   executor,
 		printer:    printer,
 		progress:   progress.NewLine(os.Stdout),
 		config:     cfg,
 		detector:   ratelimit.NewDetector(),
 		correlator: NewToolCorrelator(),
+		attempts:   make(map[string]int),
 	}
+	r.progress.SetProfile(cfg.Profile)
+	return r
 }
 
 // SetOperation sets the operation context for display in the status bar.
@@ -62,17 +91,69 @@ func (r *Runner) SetOperation(operation string) {
 // "analyze", "implement", "test"). The storyKey is substituted into the
 // workflow's prompt template.
 //
+// In native commit mode (see [config.GitConfig.CommitMode]) the git-commit
+// workflow is run by [Runner.RunNativeCommit] instead.
+//
 // Returns the exit code from Claude CLI (0 for success, non-zero for failure).
 func (r *Runner) RunSingle(ctx context.Context, workflowName, storyKey string) int {
-	prompt, err := r.config.GetPrompt(workflowName, storyKey)
+	if workflowName == config.GitCommitWorkflow && r.config.Git.CommitMode == config.CommitModeNative {
+		return r.RunNativeCommit(ctx, storyKey)
+	}
+	return r.RunWorkflow(ctx, workflowName, config.PromptData{StoryKey: storyKey, Push: r.config.Git.Push})
+}
+
+// LastMessage returns the text of the final message Claude sent during the
+// most recent workflow, or an empty string if it sent none.
+//
+// The lifecycle executor reads outcomes reported there, such as a code
+// review's REVIEW_RESULT marker.
+func (r *Runner) LastMessage() string {
+	return r.lastMessage
+}
+
+// LastModel returns the model the most recent workflow ran on, which is a
+// fallback model if the configured one hit a limit, or an empty string for
+// the Claude CLI default.
+func (r *Runner) LastModel() string {
+	return r.lastModel
+}
+
+// LastTranscriptPath returns the transcript file of the most recent
+// workflow's Claude session (see [claude.TranscriptPath]), or an empty string
+// if the session ID is unknown.
+func (r *Runner) LastTranscriptPath() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	return claude.TranscriptPath(dir, r.lastSessionID)
+}
+
+// RunWorkflow executes a named workflow with arbitrary template data.
+//
+// This is the general form of [Runner.RunSingle] for workflows that are not
+// tied to a single story, such as the epic retrospective. The status bar label
+// uses the story key when set, otherwise the epic ID.
+//
+// Fields of data that are not set are filled in from the story and project
+// (see [Runner.promptData]).
+//
+// Returns the exit code from Claude CLI (0 for success, non-zero for failure).
+func (r *Runner) RunWorkflow(ctx context.Context, workflowName string, data config.PromptData) int {
+	data = r.promptData(workflowName, data)
+	prompt, err := r.config.GetPromptWithData(workflowName, data)
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 		return 1
 	}
+	defer func() { r.lastStoryKey = data.StoryKey }()
 
-	label := fmt.Sprintf("%s: %s", workflowName, storyKey)
-	model := r.config.GetModel(workflowName)
-	return r.runClaude(ctx, prompt, label, model)
+	subject := data.StoryKey
+	if subject == "" && data.EpicID != "" {
+		subject = "epic " + data.EpicID
+	}
+	label := fmt.Sprintf("%s: %s", workflowName, subject)
+	return r.runClaude(ctx, prompt, label, workflowName)
 }
 
 // RunRaw executes an arbitrary prompt without template expansion.
@@ -111,8 +192,7 @@ func (r *Runner) RunFullCycle(ctx context.Context, storyKey string) int {
 			fmt.Printf("Error building step %s: %v\n", name, err)
 			return 1
 		}
-		model := r.config.GetModel(name)
-		steps = append(steps, Step{Name: name, Prompt: prompt, Model: model})
+		steps = append(steps, Step{Name: name, Prompt: prompt, Options: r.claudeOptions(name)})
 	}
 
 	// Initialize progress line FIRST (sets up scroll region at bottom)
@@ -160,7 +240,7 @@ func (r *Runner) runStepWithProgress(ctx context.Context, step Step, storyKey st
 	startTime := time.Now()
 
 	// Update progress bar for this step
-	r.progress.SetStepInfo(stepNum, totalSteps, step.Name, storyKey, step.Model)
+	r.progress.SetStepInfo(stepNum, totalSteps, step.Name, storyKey, step.Options.Model)
 
 	// Event handler
 	handler := func(event claude.Event) {
@@ -203,11 +283,9 @@ func (r *Runner) runStepWithProgress(ctx context.Context, step Step, storyKey st
 		}
 	}
 
-	exitCode, err := r.executor.ExecuteWithResult(ctx, step.Prompt, handler, step.Model)
-	if err != nil {
-		fmt.Printf("Error executing claude: %v\n", err)
-		exitCode = 1
-	}
+	exitCode := r.execute(ctx, step.Name, step.Prompt, step.Options, handler, func(model string) {
+		r.progress.SetStepInfo(stepNum, totalSteps, step.Name, storyKey, model)
+	})
 
 	duration := time.Since(startTime)
 	r.progress.Done(exitCode == 0, duration)
@@ -220,16 +298,22 @@ func (r *Runner) runStepWithProgress(ctx context.Context, step Step, storyKey st
 // This is the core execution method used by all public Runner methods.
 // It displays a command header, streams events to the printer via handleEvent,
 // updates the progress line, and displays a footer with timing and exit status.
-func (r *Runner) runClaude(ctx context.Context, prompt, label, model string) int {
+// The Claude CLI options and models are those of the named workflow; an empty
+// name runs with the defaults.
+func (r *Runner) runClaude(ctx context.Context, prompt, label, workflowName string) int {
+	opts := r.claudeOptions(workflowName)
+
 	// Reset correlator for new execution
 	r.correlator.Reset()
+	r.lastMessage = ""
+	r.lastSessionID = ""
 
 	// Initialize progress line FIRST (sets up scroll region at bottom)
 	// This must happen before any output so content flows naturally
 	r.progress.Init()
 
 	// Set initial step info
-	r.progress.SetStepInfo(0, 0, label, "", model)
+	r.progress.SetStepInfo(0, 0, label, "", opts.Model)
 
 	// Now print header (it will scroll within the scroll region)
 	r.printer.CommandHeader(label, prompt, r.config.Output.TruncateLength)
@@ -262,6 +346,13 @@ func (r *Runner) runClaude(ctx context.Context, prompt, label, model string) int
 			r.progress.SetCurrentTool("") // Back to thinking
 		}
 
+		if event.IsText() {
+			r.lastMessage = event.Text
+		}
+		if event.SessionID != "" {
+			r.lastSessionID = event.SessionID
+		}
+
 		// Print the event (output scrolls below status bar)
 		r.handleEvent(event)
 
@@ -277,11 +368,9 @@ func (r *Runner) runClaude(ctx context.Context, prompt, label, model string) int
 		}
 	}
 
-	exitCode, err := r.executor.ExecuteWithResult(ctx, prompt, handler, model)
-	if err != nil {
-		fmt.Printf("Error executing claude: %v\n", err)
-		exitCode = 1
-	}
+	exitCode := r.execute(ctx, workflowName, prompt, opts, handler, func(model string) {
+		r.progress.SetStepInfo(0, 0, label, "", model)
+	})
 
 	duration := time.Since(startTime)
 	r.progress.Done(exitCode == 0, duration)
@@ -290,6 +379,27 @@ func (r *Runner) runClaude(ctx context.Context, prompt, label, model string) int
 	return exitCode
 }
 
+// claudeOptions returns the Claude CLI options configured for a workflow,
+// with the first model of its chain. Unknown workflows get the zero
+// [claude.Options].
+func (r *Runner) claudeOptions(workflowName string) claude.Options {
+	wf, ok := r.config.Workflows[workflowName]
+	if !ok {
+		return claude.Options{}
+	}
+	return claude.Options{
+		Model:              r.config.GetModel(workflowName),
+		AllowedTools:       wf.AllowedTools,
+		DisallowedTools:    wf.DisallowedTools,
+		PermissionMode:     wf.PermissionMode,
+		MaxTurns:           wf.MaxTurns,
+		AppendSystemPrompt: wf.AppendSystemPrompt,
+		MCPConfig:          wf.MCPConfig,
+		AddDirs:            wf.AddDirs,
+		ExtraArgs:          wf.ExtraArgs,
+	}
+}
+
 // handleEvent routes a Claude streaming event to the appropriate printer method.
 // Tool uses are buffered and correlated with their results to print them together,
 // matching Claude Code's display behavior.
@@ -3,6 +3,7 @@ package workflow
 import (
 	"bytes"
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -50,6 +51,27 @@ func TestRunner_RunSingle(t *testing.T) {
 	assert.Contains(t, mockExecutor.RecordedPrompts[0], "test-123")
 }
 
+func TestRunner_RunSingle_ClaudeOptions(t *testing.T) {
+	runner, mockExecutor, _ := setupTestRunner()
+	wf := runner.config.Workflows["code-review"]
+	wf.Model = "opus"
+	wf.PermissionMode = "dontAsk"
+	wf.AllowedTools = []string{"Read", "Grep"}
+	wf.MaxTurns = 30
+	runner.config.Workflows["code-review"] = wf
+
+	exitCode := runner.RunSingle(context.Background(), "code-review", "test-123")
+
+	assert.Equal(t, 0, exitCode)
+	require.Len(t, mockExecutor.RecordedOptions, 1)
+	assert.Equal(t, claude.Options{
+		Model:          "opus",
+		PermissionMode: "dontAsk",
+		AllowedTools:   []string{"Read", "Grep"},
+		MaxTurns:       30,
+	}, mockExecutor.RecordedOptions[0])
+}
+
 func TestRunner_RunSingle_UnknownWorkflow(t *testing.T) {
 	runner, _, _ := setupTestRunner()
 
@@ -125,12 +147,12 @@ func (f *failOnNthCallExecutor) Execute(ctx context.Context, prompt string) (<-c
 	return f.inner.Execute(ctx, prompt)
 }
 
-func (f *failOnNthCallExecutor) ExecuteWithResult(ctx context.Context, prompt string, handler claude.EventHandler, model string) (int, error) {
+func (f *failOnNthCallExecutor) ExecuteWithResult(ctx context.Context, prompt string, handler claude.EventHandler, opts claude.Options) (int, error) {
 	*f.current++
 	if *f.current == f.failOn {
 		return 1, nil
 	}
-	return f.inner.ExecuteWithResult(ctx, prompt, handler, model)
+	return f.inner.ExecuteWithResult(ctx, prompt, handler, opts)
 }
 
 func TestRunner_HandleEvent(t *testing.T) {
@@ -221,3 +243,49 @@ func TestNewQueueRunner(t *testing.T) {
 
 // Note: QueueRunner.RunQueueWithStatus tests are in internal/cli/queue_test.go
 // since they require status.Reader and full CLI integration testing
+
+func TestRunner_RunWorkflow_EpicData(t *testing.T) {
+	runner, mockExecutor, _ := setupTestRunner()
+	runner.config.Workflows[config.RetrospectiveWorkflow] = config.WorkflowConfig{
+		PromptTemplate: "Retro {{.EpicID}}:{{range .Stories}} {{.}}{{end}}",
+	}
+
+	exitCode := runner.RunWorkflow(context.Background(), config.RetrospectiveWorkflow, config.PromptData{
+		EpicID:  "6",
+		Stories: []string{"6-1-a", "6-2-b"},
+	})
+
+	assert.Equal(t, 0, exitCode)
+	require.Len(t, mockExecutor.RecordedPrompts, 1)
+	assert.Equal(t, "Retro 6: 6-1-a 6-2-b", mockExecutor.RecordedPrompts[0])
+}
+
+func TestRunner_LastMessage(t *testing.T) {
+	runner, mockExecutor, _ := setupTestRunner()
+	mockExecutor.Events = []claude.Event{
+		{Type: claude.EventTypeAssistant, Text: "Reviewing..."},
+		{Type: claude.EventTypeAssistant, Text: "All good.\nREVIEW_RESULT: APPROVED"},
+		{Type: claude.EventTypeResult, SessionComplete: true},
+	}
+
+	runner.RunSingle(context.Background(), "code-review", "test-123")
+	assert.Equal(t, "All good.\nREVIEW_RESULT: APPROVED", runner.LastMessage())
+
+	// A run without text clears the previous message
+	mockExecutor.Events = []claude.Event{{Type: claude.EventTypeResult, SessionComplete: true}}
+	runner.RunSingle(context.Background(), "code-review", "test-123")
+	assert.Empty(t, runner.LastMessage())
+}
+
+func TestRunner_LastTranscriptPath(t *testing.T) {
+	runner, mockExecutor, _ := setupTestRunner()
+	assert.Empty(t, runner.LastTranscriptPath())
+
+	mockExecutor.Events = []claude.Event{
+		{Type: claude.EventTypeSystem, SessionStarted: true, SessionID: "abc-123"},
+		{Type: claude.EventTypeResult, SessionComplete: true, SessionID: "abc-123"},
+	}
+	runner.RunSingle(context.Background(), "dev-story", "test-123")
+
+	assert.True(t, strings.HasSuffix(runner.LastTranscriptPath(), "abc-123.jsonl"))
+}
@@ -0,0 +1,154 @@
+// Package worktree manages the project's git working tree on behalf of the
+// story lifecycle.
+//
+// [Worktree] implements [lifecycle.Worktree]: it records the commit a story
+// starts from and stashes or resets the agent's partial work when a story
+// fails. [Preserve] carries the sprint status file and its history log across
+// operations that rewrite the working tree, such as branch switches and
+// resets, since they hold the run's progress and must never be reverted.
+package worktree
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"bmaduum/internal/git"
+	"bmaduum/internal/state"
+	"bmaduum/internal/status"
+)
+
+// Worktree is the git working tree of a project.
+//
+// Use [New] to create an instance.
+type Worktree struct {
+	dir        string
+	statusPath string
+}
+
+// New creates a [Worktree] for the project in the current working
+// directory. The sprint status file at statusPath and its history log are
+// never stashed or reset.
+func New(statusPath string) *Worktree {
+	return &Worktree{statusPath: statusPath}
+}
+
+// Head returns the commit currently checked out.
+func (w *Worktree) Head() (string, error) {
+	return git.Head(w.dir)
+}
+
+// Stash stashes every uncommitted change, including untracked files, under
+// message. The bookkeeping files stay in place (see [Worktree.keptFiles]).
+// Returns false if there was nothing to stash.
+func (w *Worktree) Stash(message string) (bool, error) {
+	return git.Stash(w.dir, message, w.keptFiles()...)
+}
+
+// Reset resets the working tree to commit, discarding uncommitted changes,
+// untracked files that are not ignored and any commits made since. The
+// bookkeeping files are not removed (see [Worktree.keptFiles]).
+func (w *Worktree) Reset(commit string) error {
+	return Preserve(w.dir, w.statusPath, func() error {
+		return git.ResetHard(w.dir, commit, w.keptFiles()...)
+	})
+}
+
+// keptFiles returns the absolute paths of the files a stash or reset must
+// leave alone: the sprint status file, its history log and lock file, and
+// the checkpoint state file of the other stories.
+func (w *Worktree) keptFiles() []string {
+	paths := statusFiles(w.statusPath)
+	if w.statusPath != "" {
+		paths = append(paths, status.LockPath(w.statusPath))
+	}
+	paths = append(paths, filepath.Join(w.dir, state.StateFileName))
+
+	var kept []string
+	for _, path := range paths {
+		if abs, err := filepath.Abs(path); err == nil {
+			kept = append(kept, abs)
+		}
+	}
+	return kept
+}
+
+// statusFiles returns the sprint status file and its history log.
+func statusFiles(statusPath string) []string {
+	if statusPath == "" {
+		return nil
+	}
+	return []string{statusPath, status.HistoryPath(statusPath)}
+}
+
+// carriedFile is a file whose content and permissions survive a working
+// tree rewrite.
+type carriedFile struct {
+	path string
+	data []byte
+	mode os.FileMode
+}
+
+// Preserve runs fn with the sprint status file at statusPath and its history
+// log carried across, in the repository containing dir.
+//
+// Their content is saved and they are reset to HEAD (or removed when not
+// committed) so git does not refuse to overwrite them, then the saved content
+// is written back whether or not fn succeeded.
+func Preserve(dir, statusPath string, fn func() error) error {
+	carried, err := saveFiles(statusFiles(statusPath))
+	if err != nil {
+		return err
+	}
+
+	for _, f := range carried {
+		if err := git.RestoreFile(dir, f.path); err != nil {
+			if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+				return writeFiles(carried, err)
+			}
+		}
+	}
+
+	return writeFiles(carried, fn())
+}
+
+// saveFiles reads the files at paths; missing files are skipped.
+func saveFiles(paths []string) ([]carriedFile, error) {
+	var carried []carriedFile
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		carried = append(carried, carriedFile{path: path, data: data, mode: info.Mode().Perm()})
+	}
+	return carried, nil
+}
+
+// writeFiles writes carried content back with its original permissions and
+// returns fnErr, or the first write error if fn itself succeeded.
+func writeFiles(carried []carriedFile, fnErr error) error {
+	for _, f := range carried {
+		if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil && fnErr == nil {
+			fnErr = fmt.Errorf("restoring %s: %w", f.path, err)
+			continue
+		}
+		if err := os.WriteFile(f.path, f.data, f.mode); err != nil && fnErr == nil {
+			fnErr = fmt.Errorf("restoring %s: %w", f.path, err)
+			continue
+		}
+		// WriteFile only applies the mode to new files; the one restored
+		// from HEAD keeps git's.
+		if err := os.Chmod(f.path, f.mode); err != nil && fnErr == nil {
+			fnErr = fmt.Errorf("restoring %s: %w", f.path, err)
+		}
+	}
+	return fnErr
+}
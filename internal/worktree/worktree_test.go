@@ -0,0 +1,156 @@
+package worktree
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bmaduum/internal/state"
+	"bmaduum/internal/status"
+)
+
+// initRepo creates a git repository with a committed sprint status file and
+// returns it with the status file path.
+func initRepo(t *testing.T) (string, string) {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	gitCmd(t, dir, "init", "-q")
+	gitCmd(t, dir, "config", "user.email", "test@example.com")
+	gitCmd(t, dir, "config", "user.name", "Test")
+	statusPath := filepath.Join(dir, "sprint-status.yaml")
+	writeFile(t, statusPath, "6-1-first: ready-for-dev\n")
+	writeFile(t, filepath.Join(dir, "main.go"), "package main\n")
+	gitCmd(t, dir, "add", "-A")
+	gitCmd(t, dir, "commit", "-q", "-m", "initial")
+
+	return dir, statusPath
+}
+
+// gitCmd runs git in dir and returns its trimmed output, failing the test on error.
+func gitCmd(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	return strings.TrimSpace(string(out))
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func newTestWorktree(dir, statusPath string) *Worktree {
+	w := New(statusPath)
+	w.dir = dir
+	return w
+}
+
+func TestWorktree_Stash(t *testing.T) {
+	dir, statusPath := initRepo(t)
+	writeFile(t, filepath.Join(dir, "main.go"), "package main // partial\n")
+	writeFile(t, filepath.Join(dir, "new.go"), "package main\n")
+	writeFile(t, statusPath, "6-1-first: in-progress\n")
+	w := newTestWorktree(dir, statusPath)
+
+	stashed, err := w.Stash("bmaduum: 6-1-first partial work")
+
+	require.NoError(t, err)
+	assert.True(t, stashed)
+	assert.Equal(t, "M sprint-status.yaml", gitCmd(t, dir, "status", "--porcelain"))
+	assert.Equal(t, "6-1-first: in-progress\n", readFile(t, statusPath))
+	assert.Contains(t, gitCmd(t, dir, "stash", "list"), "bmaduum: 6-1-first partial work")
+}
+
+func TestWorktree_Reset(t *testing.T) {
+	dir, statusPath := initRepo(t)
+	w := newTestWorktree(dir, statusPath)
+	base, err := w.Head()
+	require.NoError(t, err)
+
+	writeFile(t, filepath.Join(dir, "main.go"), "package main // committed\n")
+	gitCmd(t, dir, "commit", "-q", "-am", "partial")
+	writeFile(t, filepath.Join(dir, "new.go"), "package main\n")
+	writeFile(t, statusPath, "6-1-first: review\n")
+	historyPath := filepath.Join(dir, "sprint-status.history.jsonl")
+	writeFile(t, historyPath, "{}\n")
+
+	require.NoError(t, w.Reset(base))
+
+	head, err := w.Head()
+	require.NoError(t, err)
+	assert.Equal(t, base, head)
+	assert.Equal(t, "package main\n", readFile(t, filepath.Join(dir, "main.go")))
+	_, err = os.Stat(filepath.Join(dir, "new.go"))
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+	assert.Equal(t, "6-1-first: review\n", readFile(t, statusPath), "status changes are kept")
+	assert.Equal(t, "{}\n", readFile(t, historyPath), "the untracked history log is kept")
+}
+
+func TestWorktree_Reset_KeepsBookkeepingFiles(t *testing.T) {
+	dir, statusPath := initRepo(t)
+	w := newTestWorktree(dir, statusPath)
+	base, err := w.Head()
+	require.NoError(t, err)
+
+	lockPath := status.LockPath(statusPath)
+	writeFile(t, lockPath, "")
+	statePath := filepath.Join(dir, state.StateFileName)
+	writeFile(t, statePath, `{"story_key":"6-2-second"}`)
+	writeFile(t, filepath.Join(dir, "new.go"), "package main\n")
+
+	require.NoError(t, w.Reset(base))
+
+	assert.FileExists(t, lockPath)
+	assert.Equal(t, `{"story_key":"6-2-second"}`, readFile(t, statePath))
+	assert.NoFileExists(t, filepath.Join(dir, "new.go"))
+}
+
+func TestPreserve_RestoresOnFailure(t *testing.T) {
+	dir, statusPath := initRepo(t)
+	writeFile(t, statusPath, "6-1-first: done\n")
+
+	err := Preserve(dir, statusPath, func() error {
+		assert.Equal(t, "6-1-first: ready-for-dev\n", readFile(t, statusPath), "reset to HEAD during fn")
+		return errors.New("switch failed")
+	})
+
+	assert.EqualError(t, err, "switch failed")
+	assert.Equal(t, "6-1-first: done\n", readFile(t, statusPath))
+}
+
+func TestPreserve_KeepsFileMode(t *testing.T) {
+	dir, statusPath := initRepo(t)
+	historyPath := filepath.Join(dir, "sprint-status.history.jsonl")
+	writeFile(t, historyPath, "{}\n")
+	require.NoError(t, os.Chmod(statusPath, 0600))
+	require.NoError(t, os.Chmod(historyPath, 0600))
+
+	require.NoError(t, Preserve(dir, statusPath, func() error { return nil }))
+
+	for _, path := range []string{statusPath, historyPath} {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), path)
+	}
+}